@@ -10,6 +10,10 @@ import { UserCreate } from "./user/UserCreate";
 import { UserEdit } from "./user/UserEdit";
 import { UserShow } from "./user/UserShow";
 import { jwtAuthProvider } from "./auth-provider/ra-auth-jwt";
+import {
+  isSessionAuthMode,
+  sessionAuthProvider,
+} from "./auth-provider/ra-auth-session";
 
 const App = (): React.ReactElement => {
   const [dataProvider, setDataProvider] = useState<DataProvider | null>(null);
@@ -30,7 +34,7 @@ const App = (): React.ReactElement => {
       <Admin
         title={"My service"}
         dataProvider={dataProvider}
-        authProvider={jwtAuthProvider}
+        authProvider={isSessionAuthMode() ? sessionAuthProvider : jwtAuthProvider}
         theme={theme}
         dashboard={Dashboard}
         loginPage={Login}
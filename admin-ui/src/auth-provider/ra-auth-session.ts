@@ -0,0 +1,76 @@
+import { AuthProvider } from "react-admin";
+import {
+  CSRF_HEADER,
+  CSRF_TOKEN_SESSION_STORAGE_ITEM,
+  USER_DATA_LOCAL_STORAGE_ITEM,
+} from "../constants";
+import { Credentials, LoginMutateResult } from "../types";
+
+const API_URL = `${process.env.REACT_APP_SERVER_URL}/api/v1`;
+
+// Session token is kept by browser in HttpOnly cookie, so scripts can't read it.
+// Only CSRF token is stored, it is sent back in header of unsafe requests.
+export const sessionAuthProvider: AuthProvider = {
+  login: async (credentials: Credentials) => {
+    const response = await fetch(`${API_URL}/user/sign/in?session=cookie`, {
+      method: "POST",
+      credentials: "include",
+      headers: { "Content-Type": "application/json" },
+      body: JSON.stringify(credentials),
+    });
+    if (!response.ok) {
+      return Promise.reject();
+    }
+
+    const data = await response.json();
+    sessionStorage.setItem(CSRF_TOKEN_SESSION_STORAGE_ITEM, data.csrf_token);
+    const userData: LoginMutateResult = {
+      login: { username: credentials.username, accessToken: "" },
+    };
+    localStorage.setItem(USER_DATA_LOCAL_STORAGE_ITEM, JSON.stringify(userData));
+    return Promise.resolve();
+  },
+  logout: async () => {
+    await fetch(`${API_URL}/session`, {
+      method: "DELETE",
+      credentials: "include",
+      headers: csrfHeaders(),
+    }).catch(() => undefined);
+    sessionStorage.removeItem(CSRF_TOKEN_SESSION_STORAGE_ITEM);
+    localStorage.removeItem(USER_DATA_LOCAL_STORAGE_ITEM);
+    return Promise.resolve();
+  },
+  checkError: ({ status }: any) => {
+    if (status === 401 || status === 403) {
+      sessionStorage.removeItem(CSRF_TOKEN_SESSION_STORAGE_ITEM);
+      return Promise.reject();
+    }
+    return Promise.resolve();
+  },
+  checkAuth: async () => {
+    const response = await fetch(`${API_URL}/session`, {
+      credentials: "include",
+    });
+    return response.ok ? Promise.resolve() : Promise.reject();
+  },
+  getPermissions: () => Promise.reject("Unknown method"),
+  getIdentity: () => {
+    const str = localStorage.getItem(USER_DATA_LOCAL_STORAGE_ITEM);
+    const userData: LoginMutateResult = JSON.parse(str || "");
+
+    return Promise.resolve({
+      id: userData.login.username,
+      fullName: userData.login.username,
+      avatar: undefined,
+    });
+  },
+};
+
+export function csrfHeaders(): Record<string, string> {
+  const token = sessionStorage.getItem(CSRF_TOKEN_SESSION_STORAGE_ITEM);
+  return token ? { [CSRF_HEADER]: token } : {};
+}
+
+export function isSessionAuthMode(): boolean {
+  return process.env.REACT_APP_AUTH_MODE === "session";
+}
@@ -1,2 +1,4 @@
 export const CREDENTIALS_LOCAL_STORAGE_ITEM = "credentials";
 export const USER_DATA_LOCAL_STORAGE_ITEM = "userData";
+export const CSRF_TOKEN_SESSION_STORAGE_ITEM = "csrfToken";
+export const CSRF_HEADER = "X-CSRF-Token";
@@ -2,12 +2,23 @@ import buildGraphQLProvider from "ra-data-graphql-amplication";
 import { ApolloClient, InMemoryCache, createHttpLink } from "@apollo/client";
 import { setContext } from "@apollo/client/link/context";
 import { CREDENTIALS_LOCAL_STORAGE_ITEM } from "../constants";
+import { csrfHeaders, isSessionAuthMode } from "../auth-provider/ra-auth-session";
 
 const httpLink = createHttpLink({
   uri: `${process.env.REACT_APP_SERVER_URL}/graphql`,
+  credentials: isSessionAuthMode() ? "include" : "same-origin",
 });
 
 const authLink = setContext((_, { headers }) => {
+  // Session cookie is sent by browser, GraphQL requests are POST, so they need CSRF token.
+  if (isSessionAuthMode()) {
+    return {
+      headers: {
+        ...headers,
+        ...csrfHeaders(),
+      },
+    };
+  }
   const token = localStorage.getItem(CREDENTIALS_LOCAL_STORAGE_ITEM);
   return {
     headers: {
@@ -0,0 +1,91 @@
+package controllers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/acl"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/problem"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/utils"
+)
+
+// forbidden func for return status 403 with the same body for every denial.
+func forbidden(c *fiber.Ctx) error {
+	return problem.Forbidden("permission denied, check credentials of your token")
+}
+
+// requestedOwner func for get owner of records, if only records of the current
+// user are requested by the "?mine=true" query parameter, or uuid.Nil otherwise.
+// Callers, who can read only own records by grants, always get their own records.
+func requestedOwner(c *fiber.Ctx) (uuid.UUID, error) {
+	permission, checked := acl.GetPermission(c)
+	if c.Query("mine") != "true" && (!checked || permission.Possession != acl.PossessionOwn) {
+		return uuid.Nil, nil
+	}
+
+	principal, err := currentPrincipal(c)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	// Records are owned only by users, not by other subjects.
+	if principal.UserID == uuid.Nil {
+		return uuid.Nil, errors.New("unauthorized, token subject is not a user")
+	}
+
+	return principal.UserID, nil
+}
+
+// canModify func for checking, if principal can modify a record of the given owner.
+// Role in organization of record is checked first, if the route is tenant scoped:
+// viewers modify nothing, owners and admins of organization modify any record.
+// Permission by grants is used, if the route is access controlled.
+func canModify(c *fiber.Ctx, principal *utils.Principal, ownerID uuid.UUID) bool {
+	tenant, _ := utils.GetTenant(c)
+	permission, checked := acl.GetPermission(c)
+
+	return canModifyRecord(principal, tenant, permission, checked, ownerID)
+}
+
+// canModifyRecord func for checking, if principal can modify a record of the
+// given owner by role in organization (if tenant is not nil) and permission
+// by grants (if it's checked), like canModify.
+func canModifyRecord(principal *utils.Principal, tenant *utils.Tenant, permission acl.Permission, checked bool, ownerID uuid.UUID) bool {
+	if tenant != nil {
+		if !tenant.CanWrite() {
+			return false
+		}
+		if tenant.CanManage() {
+			return true
+		}
+	}
+
+	if !checked {
+		return principal.CanModify(ownerID)
+	}
+
+	if permission.Possession == acl.PossessionAny {
+		return true
+	}
+
+	return principal.UserID != uuid.Nil && principal.UserID == ownerID
+}
+
+// currentPrincipal func for get principal of the current request. Public routes
+// have no auth middleware, so the Authorization header is parsed there.
+func currentPrincipal(c *fiber.Ctx) (*utils.Principal, error) {
+	if principal, err := utils.GetPrincipal(c); err == nil {
+		return principal, nil
+	}
+
+	principal, err := utils.ExtractPrincipal(c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Store principal for the rest of request.
+	utils.SetPrincipal(c, principal)
+
+	return principal, nil
+}
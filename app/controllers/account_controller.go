@@ -0,0 +1,433 @@
+package controllers
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/koddr/tutorial-go-fiber-rest-api/app/models"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/mailer"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/problem"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/utils"
+	"github.com/koddr/tutorial-go-fiber-rest-api/platform/database"
+)
+
+// ForgotPassword method to send link of password reset to verified email of user.
+// @Description Send link of password reset to email, if it's a verified email of user. Response is the same for unknown emails.
+// @Summary send link of password reset
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param email body string true "Email"
+// @Success 202 {string} status "ok"
+// @Router /v1/user/password/forgot [post]
+func ForgotPassword(c *fiber.Ctx) error {
+	// Create a new request struct.
+	forgot := &models.ForgotPassword{}
+
+	// Checking received data from JSON body.
+	if err := c.BodyParser(forgot); err != nil {
+		// Return status 400 and error message.
+		return problem.BadRequest(err.Error())
+	}
+
+	// Validate request fields.
+	if err := utils.NewValidator().Struct(forgot); err != nil {
+		// Return, if some fields are not valid.
+		return problem.Validation(err)
+	}
+
+	// Create database connection.
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		// Return status 500 and database connection error.
+		return problem.Internal(err)
+	}
+
+	// Link is sent only to verified emails, so nobody gets access by a mistyped email.
+	foundedUser, err := db.GetUserByEmail(forgot.Email)
+	if err == nil && foundedUser.EmailVerifiedAt != nil {
+		if err := sendUserToken(db, &foundedUser, models.PasswordResetPurpose); err != nil {
+			// Return status 500 and token creation error.
+			return problem.Internal(err)
+		}
+	}
+
+	// Return status 202 accepted, it doesn't tell, if email is known.
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+		"error": false,
+		"msg":   nil,
+	})
+}
+
+// ResetPassword method to set a new password of user by token from link of password reset.
+// @Description Set a new password by token from link of password reset. All sessions of user are revoked.
+// @Summary reset password
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param token body string true "Token from link"
+// @Param password body string true "New password"
+// @Success 204 {string} status "ok"
+// @Router /v1/user/password/reset [post]
+func ResetPassword(c *fiber.Ctx) error {
+	// Create a new request struct.
+	reset := &models.ResetPassword{}
+
+	// Checking received data from JSON body.
+	if err := c.BodyParser(reset); err != nil {
+		// Return status 400 and error message.
+		return problem.BadRequest(err.Error())
+	}
+
+	// Validate request fields.
+	if err := utils.NewValidator().Struct(reset); err != nil {
+		// Return, if some fields are not valid.
+		return problem.Validation(err)
+	}
+
+	// Create database connection.
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		// Return status 500 and database connection error.
+		return problem.Internal(err)
+	}
+
+	// Use token, it can't be used again.
+	token, err := db.UseUserToken(utils.HashToken(reset.Token), models.PasswordResetPurpose)
+	if err != nil {
+		// Return status 400 and token error.
+		return problem.BadRequest("token is not valid, expired or already used")
+	}
+
+	// Get user of token.
+	foundedUser, err := db.GetUserByID(token.UserID)
+	if err != nil {
+		// Return status 404 and user not found error.
+		return problem.NotFound("user with the given ID is not found")
+	}
+
+	// Make hash from the given password.
+	passwordHash, err := utils.GeneratePassword(reset.Password)
+	if err != nil {
+		// Return status 500 and password hashing error.
+		return problem.Internal(err)
+	}
+
+	// Set a new password, revoke sessions and other links of password reset.
+	if err := db.UpdateUserPassword(foundedUser.ID, passwordHash); err != nil {
+		// Return status 500 and database query error.
+		return problem.Internal(err)
+	}
+	if err := db.RevokeUserRefreshTokens(foundedUser.ID); err != nil {
+		// Return status 500 and database query error.
+		return problem.Internal(err)
+	}
+	if err := db.RevokeUserSessions(foundedUser.ID); err != nil {
+		// Return status 500 and database query error.
+		return problem.Internal(err)
+	}
+	if err := db.DeleteUserTokens(foundedUser.ID, models.PasswordResetPurpose); err != nil {
+		// Return status 500 and database query error.
+		return problem.Internal(err)
+	}
+
+	// Owner of email proved access, so lockout of username is lifted.
+	if err := db.ResetLoginAttempts(userLoginKey(foundedUser.Username)); err != nil {
+		// Return status 500 and database query error.
+		return problem.Internal(err)
+	}
+	recordSecurityEvent(db, c, models.PasswordResetEvent, &foundedUser.ID, foundedUser.Username)
+
+	// Return status 204 no content.
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// ChangeEmail method to set a new email of the current user and send link of its verification.
+// @Description Set a new email of the current user, it's not verified until link from email is opened.
+// @Summary change email
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param email body string true "Email"
+// @Success 202 {string} status "ok"
+// @Security ApiKeyAuth
+// @Router /v1/user/email [put]
+func ChangeEmail(c *fiber.Ctx) error {
+	// Get principal of the current request.
+	principal, err := utils.GetPrincipal(c)
+	if err != nil {
+		// Return status 401 and unauthorized error message.
+		return problem.Unauthorized(err.Error())
+	}
+
+	// Create a new request struct.
+	change := &models.ChangeEmail{}
+
+	// Checking received data from JSON body.
+	if err := c.BodyParser(change); err != nil {
+		// Return status 400 and error message.
+		return problem.BadRequest(err.Error())
+	}
+
+	// Validate request fields.
+	if err := utils.NewValidator().Struct(change); err != nil {
+		// Return, if some fields are not valid.
+		return problem.Validation(err)
+	}
+
+	// Create database connection.
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		// Return status 500 and database connection error.
+		return problem.Internal(err)
+	}
+
+	// Checking, if email is already taken.
+	email := strings.ToLower(change.Email)
+	if owner, err := db.GetUserByEmail(email); err == nil && owner.ID != principal.UserID {
+		// Return status 409 and conflict error.
+		return problem.Conflict("user with the given email already exists")
+	}
+
+	// Get user of principal.
+	foundedUser, err := db.GetUserByID(principal.UserID)
+	if err != nil {
+		// Return status 404 and user not found error.
+		return problem.NotFound("user with the given ID is not found")
+	}
+
+	// Set a new email and forget links, which were sent to the old one.
+	if err := db.UpdateUserEmail(foundedUser.ID, email); err != nil {
+		// Return status 500 and database query error.
+		return problem.Internal(err)
+	}
+	if err := db.DeleteUserTokens(foundedUser.ID, models.EmailVerificationPurpose); err != nil {
+		// Return status 500 and database query error.
+		return problem.Internal(err)
+	}
+
+	// Send link of verification to the new email.
+	foundedUser.Email = &email
+	if err := sendUserToken(db, &foundedUser, models.EmailVerificationPurpose); err != nil {
+		// Return status 500 and token creation error.
+		return problem.Internal(err)
+	}
+
+	// Return status 202 accepted.
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+		"error": false,
+		"msg":   nil,
+	})
+}
+
+// SendEmailVerification method to send link of email verification again.
+// @Description Send link of verification to email of the current user again.
+// @Summary send link of email verification
+// @Tags User
+// @Accept json
+// @Produce json
+// @Success 202 {string} status "ok"
+// @Security ApiKeyAuth
+// @Router /v1/user/email/verification [post]
+func SendEmailVerification(c *fiber.Ctx) error {
+	// Get principal of the current request.
+	principal, err := utils.GetPrincipal(c)
+	if err != nil {
+		// Return status 401 and unauthorized error message.
+		return problem.Unauthorized(err.Error())
+	}
+
+	// Create database connection.
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		// Return status 500 and database connection error.
+		return problem.Internal(err)
+	}
+
+	// Get user of principal.
+	foundedUser, err := db.GetUserByID(principal.UserID)
+	if err != nil {
+		// Return status 404 and user not found error.
+		return problem.NotFound("user with the given ID is not found")
+	}
+
+	// Checking, if there is email to verify.
+	if foundedUser.Email == nil {
+		// Return status 400 and error message.
+		return problem.BadRequest("user has no email")
+	}
+	if foundedUser.EmailVerifiedAt != nil {
+		// Return status 409 and conflict error.
+		return problem.Conflict("email is already verified")
+	}
+
+	// Send link of verification.
+	if err := sendUserToken(db, &foundedUser, models.EmailVerificationPurpose); err != nil {
+		// Return status 500 and token creation error.
+		return problem.Internal(err)
+	}
+
+	// Return status 202 accepted.
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+		"error": false,
+		"msg":   nil,
+	})
+}
+
+// VerifyEmail method to mark email of user as verified by token from link of email verification.
+// @Description Verify email by token from link, which was sent to it.
+// @Summary verify email
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param token body string true "Token from link"
+// @Success 204 {string} status "ok"
+// @Router /v1/user/email/verify [post]
+func VerifyEmail(c *fiber.Ctx) error {
+	// Create a new request struct.
+	verify := &models.VerifyEmail{}
+
+	// Checking received data from JSON body.
+	if err := c.BodyParser(verify); err != nil {
+		// Return status 400 and error message.
+		return problem.BadRequest(err.Error())
+	}
+
+	// Validate request fields.
+	if err := utils.NewValidator().Struct(verify); err != nil {
+		// Return, if some fields are not valid.
+		return problem.Validation(err)
+	}
+
+	// Create database connection.
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		// Return status 500 and database connection error.
+		return problem.Internal(err)
+	}
+
+	// Use token, it can't be used again.
+	token, err := db.UseUserToken(utils.HashToken(verify.Token), models.EmailVerificationPurpose)
+	if err != nil {
+		// Return status 400 and token error.
+		return problem.BadRequest("token is not valid, expired or already used")
+	}
+
+	// Email is verified, only if user still has the email, which the link was sent to.
+	verified, err := db.VerifyUserEmail(token.UserID, token.Email)
+	if err != nil {
+		// Return status 500 and database query error.
+		return problem.Internal(err)
+	}
+	if !verified {
+		// Return status 400 and token error.
+		return problem.BadRequest("email of user was changed after the link was sent")
+	}
+	recordSecurityEvent(db, c, models.EmailVerifiedEvent, &token.UserID, "")
+
+	// Return status 204 no content.
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// sendUserToken func for create single-use token of the given purpose and send
+// link with it to email of user. Email is sent in background, errors are logged.
+func sendUserToken(db *database.Queries, user *models.User, purpose string) error {
+	if user.Email == nil {
+		return nil
+	}
+
+	// Reset and verification tokens are random opaque tokens, as refresh tokens.
+	token, tokenHash, err := utils.GenerateNewRefreshToken()
+	if err != nil {
+		return err
+	}
+
+	// Get template, link and lifetime of token by its purpose.
+	template, link, lifetime := "email_verification", os.Getenv("EMAIL_VERIFICATION_URL"), emailVerificationLifetime()
+	if purpose == models.PasswordResetPurpose {
+		template, link, lifetime = "password_reset", os.Getenv("PASSWORD_RESET_URL"), passwordResetLifetime()
+	}
+
+	// Store hash of token.
+	now := time.Now()
+	if err := db.CreateUserToken(&models.UserToken{
+		ID:        uuid.New(),
+		CreatedAt: now,
+		ExpiresAt: now.Add(lifetime),
+		UserID:    user.ID,
+		Purpose:   purpose,
+		TokenHash: tokenHash,
+		Email:     *user.Email,
+	}); err != nil {
+		return err
+	}
+
+	// Send link with token.
+	return sendMail(*user.Email, template, map[string]string{
+		"Username":  user.Username,
+		"Email":     *user.Email,
+		"Link":      link + "?token=" + token,
+		"ExpiresIn": humanDuration(lifetime),
+	})
+}
+
+// sendMail func for render email by templates of the given name and send it
+// in background, errors of sending are logged.
+func sendMail(to, template string, data interface{}) error {
+	// Render message by templates.
+	message, err := mailer.Render(to, template, data)
+	if err != nil {
+		return err
+	}
+
+	transport, err := mailer.CurrentMailer()
+	if err != nil {
+		return err
+	}
+
+	// Response doesn't wait for mail server, so its time doesn't tell, if email is known.
+	go func() {
+		if err := transport.Send(message); err != nil {
+			log.Printf("Oops... Email is not sent! Reason: %v", err)
+		}
+	}()
+
+	return nil
+}
+
+// passwordResetLifetime func for get lifetime of link of password reset
+// from .env file (PASSWORD_RESET_EXPIRE_MINUTES, default 30).
+func passwordResetLifetime() time.Duration {
+	minutes, err := strconv.Atoi(os.Getenv("PASSWORD_RESET_EXPIRE_MINUTES"))
+	if err != nil || minutes <= 0 {
+		minutes = 30
+	}
+
+	return time.Duration(minutes) * time.Minute
+}
+
+// emailVerificationLifetime func for get lifetime of link of email verification
+// from .env file (EMAIL_VERIFICATION_EXPIRE_HOURS, default 48).
+func emailVerificationLifetime() time.Duration {
+	hours, err := strconv.Atoi(os.Getenv("EMAIL_VERIFICATION_EXPIRE_HOURS"))
+	if err != nil || hours <= 0 {
+		hours = 48
+	}
+
+	return time.Duration(hours) * time.Hour
+}
+
+// humanDuration func for format lifetime of link for email, like "30 minutes" or "48 hours".
+func humanDuration(d time.Duration) string {
+	if d%time.Hour == 0 {
+		return fmt.Sprintf("%d hours", int(d.Hours()))
+	}
+
+	return fmt.Sprintf("%d minutes", int(d.Minutes()))
+}
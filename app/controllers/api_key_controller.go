@@ -0,0 +1,193 @@
+package controllers
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/koddr/tutorial-go-fiber-rest-api/app/models"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/problem"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/utils"
+	"github.com/koddr/tutorial-go-fiber-rest-api/platform/database"
+)
+
+// GetAPIKeys func gets all API keys of the current user.
+// @Description Get all API keys of the current user (without secrets).
+// @Summary get all API keys of the current user
+// @Tags APIKey
+// @Accept json
+// @Produce json
+// @Success 200 {array} models.APIKey
+// @Security ApiKeyAuth
+// @Router /v1/apikeys [get]
+func GetAPIKeys(c *fiber.Ctx) error {
+	// Get principal of the current request.
+	principal, err := utils.GetPrincipal(c)
+	if err != nil {
+		// Return status 401 and unauthorized error message.
+		return problem.Unauthorized(err.Error())
+	}
+
+	// Create database connection.
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		// Return status 500 and database connection error.
+		return problem.Internal(err)
+	}
+
+	// Get all API keys of user.
+	keys, err := db.GetAPIKeysByUser(principal.UserID)
+	if err != nil {
+		// Return status 500 and database query error.
+		return problem.Internal(err)
+	}
+
+	// Return status 200 OK.
+	return c.JSON(fiber.Map{
+		"error":    false,
+		"msg":      nil,
+		"count":    len(keys),
+		"api_keys": keys,
+	})
+}
+
+// CreateAPIKey func for creates a new API key of the current user.
+// @Description Create a new API key. The key is returned only once, only its hash is stored.
+// @Summary create a new API key
+// @Tags APIKey
+// @Accept json
+// @Produce json
+// @Param name body string true "Name"
+// @Param scopes body []string true "Scopes, like books:read or servers:write"
+// @Param expires_at body string false "Expiration time"
+// @Param allowed_ips body []string false "Allowed IPs or CIDRs"
+// @Success 200 {object} models.APIKey
+// @Security ApiKeyAuth
+// @Router /v1/apikey [post]
+func CreateAPIKey(c *fiber.Ctx) error {
+	// Get principal of the current request.
+	principal, err := utils.GetPrincipal(c)
+	if err != nil {
+		// Return status 401 and unauthorized error message.
+		return problem.Unauthorized(err.Error())
+	}
+
+	// Create new NewAPIKey struct
+	newKey := &models.NewAPIKey{}
+
+	// Check, if received JSON data is valid.
+	if err := c.BodyParser(newKey); err != nil {
+		// Return status 400 and error message.
+		return problem.BadRequest(err.Error())
+	}
+
+	// Validate API key fields.
+	if err := utils.NewValidator().Struct(newKey); err != nil {
+		// Return, if some fields are not valid.
+		return problem.Validation(err)
+	}
+
+	// Checking, if expiration time is in the future.
+	if newKey.ExpiresAt != nil && newKey.ExpiresAt.Before(time.Now()) {
+		// Return status 400 and error message.
+		return problem.BadRequest("expiration time of API key must be in the future")
+	}
+
+	// Generate a new API key.
+	secret, prefix, hash, err := utils.GenerateNewAPIKey()
+	if err != nil {
+		// Return status 500 and key generation error.
+		return problem.Internal(err)
+	}
+
+	// Create database connection.
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		// Return status 500 and database connection error.
+		return problem.Internal(err)
+	}
+
+	// Set initialized default data for API key:
+	key := &models.APIKey{
+		ID:         uuid.New(),
+		CreatedAt:  time.Now(),
+		ExpiresAt:  newKey.ExpiresAt,
+		UserID:     principal.UserID,
+		Name:       newKey.Name,
+		Prefix:     prefix,
+		KeyHash:    hash,
+		Scopes:     newKey.Scopes,
+		AllowedIPs: newKey.AllowedIPs,
+	}
+
+	// Create a new API key.
+	if err := db.CreateAPIKey(key); err != nil {
+		// Return status 500 and error message.
+		return problem.Internal(err)
+	}
+
+	// Return status 200 OK, this is the only time the key is shown.
+	return c.JSON(fiber.Map{
+		"error":   false,
+		"msg":     nil,
+		"api_key": key,
+		"key":     secret,
+	})
+}
+
+// RevokeAPIKey func for revokes API key by given ID.
+// @Description Revoke API key by given ID.
+// @Summary revoke API key by given ID
+// @Tags APIKey
+// @Accept json
+// @Produce json
+// @Param id body string true "API key ID"
+// @Success 204 {string} status "ok"
+// @Security ApiKeyAuth
+// @Router /v1/apikey [delete]
+func RevokeAPIKey(c *fiber.Ctx) error {
+	// Get principal of the current request.
+	principal, err := utils.GetPrincipal(c)
+	if err != nil {
+		// Return status 401 and unauthorized error message.
+		return problem.Unauthorized(err.Error())
+	}
+
+	// Create new APIKey struct
+	key := &models.APIKey{}
+
+	// Check, if received JSON data is valid.
+	if err := c.BodyParser(key); err != nil {
+		// Return status 400 and error message.
+		return problem.BadRequest(err.Error())
+	}
+
+	// Create database connection.
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		// Return status 500 and database connection error.
+		return problem.Internal(err)
+	}
+
+	// Checking, if API key with given ID is exists.
+	foundedKey, err := db.GetAPIKey(key.ID)
+	if err != nil {
+		// Return status 404 and API key not found error.
+		return problem.NotFound("API key with this ID not found")
+	}
+
+	// Checking, if API key belongs to the current user.
+	if !principal.CanModify(foundedKey.UserID) {
+		// Return status 403 and permission denied error.
+		return forbidden(c)
+	}
+
+	// Revoke API key by given ID.
+	if err := db.RevokeAPIKey(foundedKey.ID); err != nil {
+		// Return status 500 and error message.
+		return problem.Internal(err)
+	}
+
+	// Return status 204 no content.
+	return c.SendStatus(fiber.StatusNoContent)
+}
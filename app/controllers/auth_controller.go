@@ -0,0 +1,412 @@
+package controllers
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/koddr/tutorial-go-fiber-rest-api/app/models"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/problem"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/repository"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/utils"
+	"github.com/koddr/tutorial-go-fiber-rest-api/platform/database"
+)
+
+// UserSignUp method to create a new user.
+// @Description Create a new user.
+// @Summary create a new user
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param username body string true "Username"
+// @Param password body string true "Password"
+// @Param email body string false "Email"
+// @Param firstName body string false "First name"
+// @Param lastName body string false "Last name"
+// @Success 200 {object} models.User
+// @Router /v1/user/sign/up [post]
+func UserSignUp(c *fiber.Ctx) error {
+	// Create a new user auth struct.
+	signUp := &models.SignUp{}
+
+	// Checking received data from JSON body.
+	if err := c.BodyParser(signUp); err != nil {
+		// Return status 400 and error message.
+		return problem.BadRequest(err.Error())
+	}
+
+	// Create a new validator for a SignUp model.
+	validate := utils.NewValidator()
+
+	// Validate sign up fields.
+	if err := validate.Struct(signUp); err != nil {
+		// Return, if some fields are not valid.
+		return problem.Validation(err)
+	}
+
+	// Create database connection.
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		// Return status 500 and database connection error.
+		return problem.Internal(err)
+	}
+
+	// Checking, if username is already taken.
+	if _, err := db.GetUserByUsername(signUp.Username); err == nil {
+		// Return status 409 and conflict error.
+		return problem.Conflict("user with the given username already exists")
+	}
+
+	// Checking, if email is already taken.
+	if signUp.Email != "" {
+		if _, err := db.GetUserByEmail(signUp.Email); err == nil {
+			// Return status 409 and conflict error.
+			return problem.Conflict("user with the given email already exists")
+		}
+	}
+
+	// Make hash from the given password.
+	passwordHash, err := utils.GeneratePassword(signUp.Password)
+	if err != nil {
+		// Return status 500 and password hashing error.
+		return problem.Internal(err)
+	}
+
+	// Create a new user struct.
+	user := &models.User{}
+
+	// Set initialized default data for user:
+	user.ID = uuid.New()
+	user.CreatedAt = time.Now()
+	user.Username = signUp.Username
+	user.FirstName = signUp.FirstName
+	user.LastName = signUp.LastName
+	user.Roles = models.Roles{repository.UserRoleName}
+	user.PasswordHash = passwordHash
+	if signUp.Email != "" {
+		email := strings.ToLower(signUp.Email)
+		user.Email = &email
+	}
+
+	// Validate user fields.
+	if err := validate.Struct(user); err != nil {
+		// Return, if some fields are not valid.
+		return problem.Validation(err)
+	}
+
+	// Create a new user.
+	if err := db.CreateUser(user); err != nil {
+		// Return status 500 and create user process error.
+		return problem.Internal(err)
+	}
+
+	// Send link of email verification.
+	if err := sendUserToken(db, user, models.EmailVerificationPurpose); err != nil {
+		// Return status 500 and token creation error.
+		return problem.Internal(err)
+	}
+
+	// Return status 200 OK.
+	return c.JSON(fiber.Map{
+		"error": false,
+		"msg":   nil,
+		"user":  user,
+	})
+}
+
+// UserSignIn method to auth user and return access and refresh tokens.
+// @Description Auth user and return access and refresh tokens.
+// @Summary auth user and return access and refresh tokens
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param username body string true "Username"
+// @Param password body string true "Password"
+// @Param session query string false "Set to \"cookie\" for cookie session instead of tokens"
+// @Success 200 {string} status "ok"
+// @Router /v1/user/sign/in [post]
+func UserSignIn(c *fiber.Ctx) error {
+	// Create a new user auth struct.
+	signIn := &models.SignIn{}
+
+	// Checking received data from JSON body.
+	if err := c.BodyParser(signIn); err != nil {
+		// Return status 400 and error message.
+		return problem.BadRequest(err.Error())
+	}
+
+	// Create a new validator for a SignIn model.
+	validate := utils.NewValidator()
+
+	// Validate sign in fields.
+	if err := validate.Struct(signIn); err != nil {
+		// Return, if some fields are not valid.
+		return problem.Validation(err)
+	}
+
+	// Create database connection.
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		// Return status 500 and database connection error.
+		return problem.Internal(err)
+	}
+
+	// Check username and password, failed attempts are counted.
+	foundedUser, wait, err := checkCredentials(c, db, signIn)
+	if wait > 0 {
+		// Return status 429 and lockout error.
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+		return problem.TooManyRequests("too many failed attempts, retry later")
+	}
+	if errors.Is(err, errWrongCredentials) {
+		// Return status 401, if user is not found or password is wrong.
+		return problem.Unauthorized(err.Error())
+	}
+	if err != nil {
+		// Return status 500 and database query error.
+		return problem.Internal(err)
+	}
+
+	// Checking, if user has the second factor.
+	twoFactor, err := db.HasTwoFactor(foundedUser.ID)
+	if err != nil {
+		// Return status 500 and database query error.
+		return problem.Internal(err)
+	}
+	if twoFactor {
+		// Generate intermediate token for the second step of sign in.
+		mfaToken, err := utils.GenerateTwoFactorToken(foundedUser.ID)
+		if err != nil {
+			// Return status 500 and token generation error.
+			return problem.Internal(err)
+		}
+
+		// Return status 200 OK, tokens are issued after the code is verified.
+		return c.JSON(fiber.Map{
+			"error":               false,
+			"msg":                 nil,
+			"two_factor_required": true,
+			"mfa_token":           mfaToken,
+		})
+	}
+
+	return signInCompleted(c, db, &foundedUser, false)
+}
+
+// errWrongCredentials is returned for unknown username and for wrong password,
+// so they can't be told apart.
+var errWrongCredentials = errors.New("wrong username or password")
+
+// checkCredentials func for get user by username and password of sign in.
+// Failed attempts are counted; it returns time to wait, if sign in of username
+// or IP is locked after failed attempts.
+func checkCredentials(c *fiber.Ctx, db *database.Queries, signIn *models.SignIn) (models.User, time.Duration, error) {
+	// Checking, if sign in of username or IP is locked after failed attempts.
+	if wait := loginLockedFor(db, signIn.Username, c.IP()); wait > 0 {
+		recordSecurityEvent(db, c, models.SignInBlockedEvent, nil, signIn.Username)
+		return models.User{}, wait, errors.New("too many failed attempts, retry later")
+	}
+
+	// Get user by username.
+	foundedUser, err := db.GetUserByUsername(signIn.Username)
+	if err != nil {
+		// Count failure for unknown users too, so they can't be told apart.
+		if err := recordLoginFailure(db, signIn.Username, c.IP()); err != nil {
+			return models.User{}, 0, err
+		}
+		recordSecurityEvent(db, c, models.SignInFailedEvent, nil, signIn.Username)
+
+		return models.User{}, 0, errWrongCredentials
+	}
+
+	// Compare given user password with stored in found user.
+	if !utils.ComparePasswords(foundedUser.PasswordHash, signIn.Password) {
+		// Count failure of username and IP.
+		if err := recordLoginFailure(db, signIn.Username, c.IP()); err != nil {
+			return models.User{}, 0, err
+		}
+		recordSecurityEvent(db, c, models.SignInFailedEvent, &foundedUser.ID, signIn.Username)
+
+		return models.User{}, 0, errWrongCredentials
+	}
+
+	return foundedUser, 0, nil
+}
+
+// signInCompleted func for finish sign in of user and return access and refresh tokens,
+// or start cookie session for requests with "session=cookie" query.
+func signInCompleted(c *fiber.Ctx, db *database.Queries, user *models.User, twoFactor bool) error {
+	// Forget failures of username, failures of IP are kept (other users may be attacked from it).
+	if err := db.ResetLoginAttempts(userLoginKey(user.Username)); err != nil {
+		// Return status 500 and database query error.
+		return problem.Internal(err)
+	}
+	recordSecurityEvent(db, c, models.SignInSucceededEvent, &user.ID, user.Username)
+
+	// Browsers may ask for cookie session instead of tokens.
+	if c.Query("session") == "cookie" {
+		return startSession(c, db, user, twoFactor)
+	}
+
+	// Generate a new pair of tokens for user, with a new refresh token family.
+	accessToken, refreshToken, err := issueTokens(db, user, uuid.New(), twoFactor)
+	if err != nil {
+		// Return status 500 and token generation error.
+		return problem.Internal(err)
+	}
+
+	// Return status 200 OK. User is told, if some roles are not granted without the second factor.
+	return c.JSON(fiber.Map{
+		"error":                          false,
+		"msg":                            nil,
+		"access_token":                   accessToken,
+		"refresh_token":                  refreshToken,
+		"two_factor_enrollment_required": len(utils.EffectiveRoles(user.Roles, twoFactor)) < len(user.Roles),
+	})
+}
+
+// UserSignOut method to revoke refresh tokens of the current session.
+// @Description Revoke refresh token and all its rotations (the current session).
+// @Summary sign out user from the current session
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param refresh_token body string true "Refresh token"
+// @Success 204 {string} status "ok"
+// @Security ApiKeyAuth
+// @Router /v1/user/sign/out [post]
+func UserSignOut(c *fiber.Ctx) error {
+	// Get principal of the current request.
+	principal, err := utils.GetPrincipal(c)
+	if err != nil {
+		// Return status 401 and unauthorized error message.
+		return problem.Unauthorized(err.Error())
+	}
+
+	// Create a new renew struct.
+	renew := &models.Renew{}
+
+	// Checking received data from JSON body.
+	if err := c.BodyParser(renew); err != nil {
+		// Return status 400 and error message.
+		return problem.BadRequest(err.Error())
+	}
+
+	// Create database connection.
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		// Return status 500 and database connection error.
+		return problem.Internal(err)
+	}
+
+	// Get refresh token by its hash.
+	foundedToken, err := db.GetRefreshTokenByHash(utils.HashToken(renew.RefreshToken))
+	if err != nil {
+		// Return status 404 and token not found error.
+		return problem.NotFound("refresh token is not found")
+	}
+
+	// Checking, if refresh token belongs to the current user.
+	if foundedToken.UserID != principal.UserID {
+		// Return status 403 and permission denied error.
+		return forbidden(c)
+	}
+
+	// Revoke all tokens of the session.
+	if err := db.RevokeRefreshTokenFamily(foundedToken.FamilyID); err != nil {
+		// Return status 500 and database query error.
+		return problem.Internal(err)
+	}
+
+	// Revoke access token of the session too, it must not outlive sign out.
+	if err := revokePrincipalToken(principal); err != nil {
+		// Return status 500 and revocation error.
+		return problem.Internal(err)
+	}
+
+	// Return status 204 no content.
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// UserSignOutEverywhere method to revoke all refresh tokens of the current user.
+// @Description Revoke all refresh tokens and cookie sessions of the current user (sign out everywhere).
+// @Summary sign out user from all sessions
+// @Tags User
+// @Accept json
+// @Produce json
+// @Success 204 {string} status "ok"
+// @Security ApiKeyAuth
+// @Router /v1/user/sign/out/all [post]
+func UserSignOutEverywhere(c *fiber.Ctx) error {
+	// Get principal of the current request.
+	principal, err := utils.GetPrincipal(c)
+	if err != nil {
+		// Return status 401 and unauthorized error message.
+		return problem.Unauthorized(err.Error())
+	}
+
+	// Create database connection.
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		// Return status 500 and database connection error.
+		return problem.Internal(err)
+	}
+
+	// Revoke all tokens of user.
+	if err := db.RevokeUserRefreshTokens(principal.UserID); err != nil {
+		// Return status 500 and database query error.
+		return problem.Internal(err)
+	}
+	if err := db.RevokeUserSessions(principal.UserID); err != nil {
+		// Return status 500 and database query error.
+		return problem.Internal(err)
+	}
+
+	// Revoke the current access token, other ones expire soon by themselves.
+	if err := revokePrincipalToken(principal); err != nil {
+		// Return status 500 and revocation error.
+		return problem.Internal(err)
+	}
+
+	// Return status 204 no content.
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// issueTokens func for generate a new access token and a new refresh token
+// of the given family for user. Refresh token is stored as hash only.
+// Roles, which require the second factor, are granted only to sessions with it.
+func issueTokens(db *database.Queries, user *models.User, familyID uuid.UUID, twoFactor bool) (string, string, error) {
+	// Generate a new Access token with identity of user.
+	accessToken, err := utils.GenerateNewAccessToken(&utils.Principal{
+		Subject: user.ID.String(),
+		Roles:   utils.EffectiveRoles(user.Roles, twoFactor),
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	// Generate a new Refresh token.
+	refreshToken, refreshTokenHash, err := utils.GenerateNewRefreshToken()
+	if err != nil {
+		return "", "", err
+	}
+
+	// Store hash of Refresh token.
+	if err := db.CreateRefreshToken(&models.RefreshToken{
+		ID:        uuid.New(),
+		CreatedAt: time.Now(),
+		ExpiresAt: utils.RefreshTokenExpiration(),
+		FamilyID:  familyID,
+		UserID:    user.ID,
+		TokenHash: refreshTokenHash,
+		TwoFactor: twoFactor,
+	}); err != nil {
+		return "", "", err
+	}
+
+	return accessToken, refreshToken, nil
+}
@@ -0,0 +1,355 @@
+package controllers
+
+import (
+	_ "embed" // load verification page
+	"errors"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/koddr/tutorial-go-fiber-rest-api/app/models"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/problem"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/utils"
+	"github.com/koddr/tutorial-go-fiber-rest-api/platform/database"
+)
+
+// deviceCodeGrantType is a grant type of token request by device code (RFC 8628, section 3.4).
+const deviceCodeGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+
+// errDeviceCodeNotFound is an error of user code, which is unknown, expired or already used.
+var errDeviceCodeNotFound = errors.New("device authorization request is not found or expired")
+
+// devicePage is a page, where user signs in and approves device by user code.
+//
+//go:embed views/device.html
+var devicePage []byte
+
+// DevicePage func for show page, where user approves device by user code.
+// @Description Show page, where user signs in and approves device by user code.
+// @Summary show device verification page
+// @Tags OAuth
+// @Produce html
+// @Param user_code query string false "User code shown by device"
+// @Success 200 {string} status "ok"
+// @Router /device [get]
+func DevicePage(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+	c.Set("X-Frame-Options", "DENY") // page must not be framed by other sites
+	return c.Send(devicePage)
+}
+
+// DeviceAuthorization func for start device authorization of CLI or another device without browser.
+// @Description Start device authorization (RFC 8628, section 3.1). Device shows user code and polls token endpoint.
+// @Summary start device authorization
+// @Tags OAuth
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param client_id formData string true "ID of device or application"
+// @Success 200 {string} status "ok"
+// @Router /oauth/device_authorization [post]
+func DeviceAuthorization(c *fiber.Ctx) error {
+	// Responses with codes must not be cached.
+	c.Set(fiber.HeaderCacheControl, "no-store")
+
+	// Device must tell, what it is, it's shown to user.
+	clientID := c.FormValue("client_id")
+	if clientID == "" || len(clientID) > 255 {
+		return oauthError(c, fiber.StatusBadRequest, "invalid_request", "client_id is required")
+	}
+
+	// Generate codes of request.
+	deviceCode, deviceCodeHash, userCode, err := utils.GenerateNewDeviceCode()
+	if err != nil {
+		return oauthInternalError(c, fiber.StatusInternalServerError, "server_error", err)
+	}
+
+	// Create database connection.
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		return oauthInternalError(c, fiber.StatusInternalServerError, "server_error", err)
+	}
+
+	// Set initialized default data for request:
+	now := time.Now()
+	code := &models.DeviceCode{
+		ID:             uuid.New(),
+		CreatedAt:      now,
+		ExpiresAt:      now.Add(deviceCodeLifetime()),
+		DeviceCodeHash: deviceCodeHash,
+		UserCode:       userCode,
+		ClientID:       clientID,
+		Status:         models.DeviceCodePending,
+		PollInterval:   devicePollInterval(),
+	}
+
+	// Create a new request.
+	if err := db.CreateDeviceCode(code); err != nil {
+		return oauthInternalError(c, fiber.StatusInternalServerError, "server_error", err)
+	}
+
+	// Return status 200 OK.
+	verificationURI := deviceVerificationURI(c)
+	return c.JSON(fiber.Map{
+		"device_code":               deviceCode,
+		"user_code":                 userCode,
+		"verification_uri":          verificationURI,
+		"verification_uri_complete": verificationURI + "?user_code=" + userCode,
+		"expires_in":                int(code.ExpiresAt.Sub(now).Seconds()),
+		"interval":                  code.PollInterval,
+	})
+}
+
+// GetDeviceAuthorization func for get device authorization request by user code.
+// @Description Get pending device authorization request by user code, so user can check it before approval.
+// @Summary get device authorization request
+// @Tags OAuth
+// @Accept json
+// @Produce json
+// @Param user_code query string true "User code shown by device"
+// @Success 200 {object} models.DeviceCode
+// @Security ApiKeyAuth
+// @Router /v1/device [get]
+func GetDeviceAuthorization(c *fiber.Ctx) error {
+	// Get principal of the current request.
+	principal, err := utils.GetPrincipal(c)
+	if err != nil {
+		// Return status 401 and unauthorized error message.
+		return problem.Unauthorized(err.Error())
+	}
+
+	// Only users can authorize devices.
+	if principal.UserID == uuid.Nil {
+		// Return status 403 and permission denied error.
+		return forbidden(c)
+	}
+
+	// Create database connection.
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		// Return status 500 and database connection error.
+		return problem.Internal(err)
+	}
+
+	// Get pending request by user code.
+	code, err := pendingDeviceCode(db, c.Query("user_code"))
+	if err != nil {
+		// Return status 404 and request not found error.
+		return problem.NotFound(err.Error())
+	}
+
+	// Return status 200 OK.
+	return c.JSON(fiber.Map{
+		"error":  false,
+		"msg":    nil,
+		"device": code,
+	})
+}
+
+// ApproveDeviceAuthorization func for approve device authorization request by the current user.
+// @Description Approve device authorization request. Device gets the same tokens as after sign in of user.
+// @Summary approve device authorization request
+// @Tags OAuth
+// @Accept json
+// @Produce json
+// @Param user_code body string true "User code shown by device"
+// @Success 204 {string} status "ok"
+// @Security ApiKeyAuth
+// @Router /v1/device/approve [post]
+func ApproveDeviceAuthorization(c *fiber.Ctx) error {
+	return verifyDeviceAuthorization(c, true)
+}
+
+// DenyDeviceAuthorization func for deny device authorization request.
+// @Description Deny device authorization request. Device gets access_denied error.
+// @Summary deny device authorization request
+// @Tags OAuth
+// @Accept json
+// @Produce json
+// @Param user_code body string true "User code shown by device"
+// @Success 204 {string} status "ok"
+// @Security ApiKeyAuth
+// @Router /v1/device/deny [post]
+func DenyDeviceAuthorization(c *fiber.Ctx) error {
+	return verifyDeviceAuthorization(c, false)
+}
+
+// verifyDeviceAuthorization func for approve or deny device authorization request.
+func verifyDeviceAuthorization(c *fiber.Ctx, approve bool) error {
+	// Get principal of the current request.
+	principal, err := utils.GetPrincipal(c)
+	if err != nil {
+		// Return status 401 and unauthorized error message.
+		return problem.Unauthorized(err.Error())
+	}
+
+	// Only users can authorize devices.
+	if principal.UserID == uuid.Nil {
+		// Return status 403 and permission denied error.
+		return forbidden(c)
+	}
+
+	// Create a new verification struct.
+	verification := &models.DeviceVerification{}
+
+	// Checking received data from JSON body.
+	if err := c.BodyParser(verification); err != nil {
+		// Return status 400 and error message.
+		return problem.BadRequest(err.Error())
+	}
+
+	// Validate verification fields.
+	if err := utils.NewValidator().Struct(verification); err != nil {
+		// Return, if some fields are not valid.
+		return problem.Validation(err)
+	}
+
+	// Create database connection.
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		// Return status 500 and database connection error.
+		return problem.Internal(err)
+	}
+
+	// Get pending request by user code.
+	code, err := pendingDeviceCode(db, verification.UserCode)
+	if err != nil {
+		// Return status 404 and request not found error.
+		return problem.NotFound(err.Error())
+	}
+
+	// Device gets roles of the current session, including roles, which require the second factor.
+	updated := false
+	if approve {
+		twoFactor := false
+		for _, role := range utils.TwoFactorRequiredRoles() {
+			twoFactor = twoFactor || principal.HasRole(role)
+		}
+		updated, err = db.ApproveDeviceCode(code.ID, principal.UserID, twoFactor)
+	} else {
+		updated, err = db.DenyDeviceCode(code.ID)
+	}
+	if err != nil {
+		// Return status 500 and database query error.
+		return problem.Internal(err)
+	}
+	if !updated {
+		// Return status 409, request was approved or denied in the meantime.
+		return problem.Conflict("device authorization request is already approved or denied")
+	}
+
+	// Return status 204 no content.
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// deviceCodeToken func for issue tokens of user to device, which authorization
+// request was approved (RFC 8628, section 3.4 and 3.5).
+func deviceCodeToken(c *fiber.Ctx, db *database.Queries) error {
+	// Get request by device code, it must be polled by the same device.
+	code, err := db.GetDeviceCodeByHash(utils.HashToken(c.FormValue("device_code")))
+	if err != nil || code.ClientID != c.FormValue("client_id") {
+		return oauthError(c, fiber.StatusBadRequest, "invalid_grant", "device code is not valid")
+	}
+
+	// Checking, if request is not expired.
+	now := time.Now()
+	if now.After(code.ExpiresAt) {
+		return oauthError(c, fiber.StatusBadRequest, "expired_token", "device code is expired")
+	}
+
+	// Device, which polls too often, must wait 5 seconds more from now on.
+	interval := code.PollInterval
+	tooOften := code.LastPolledAt != nil && now.Sub(*code.LastPolledAt) < time.Duration(interval)*time.Second
+	if tooOften {
+		interval += 5
+	}
+	if err := db.PollDeviceCode(code.ID, now, interval); err != nil {
+		return oauthInternalError(c, fiber.StatusInternalServerError, "server_error", err)
+	}
+	if tooOften {
+		return oauthError(c, fiber.StatusBadRequest, "slow_down", "polling is too frequent, interval is "+strconv.Itoa(interval)+" seconds")
+	}
+
+	switch code.Status {
+	case models.DeviceCodePending:
+		return oauthError(c, fiber.StatusBadRequest, "authorization_pending", "user has not approved device yet")
+	case models.DeviceCodeDenied:
+		return oauthError(c, fiber.StatusBadRequest, "access_denied", "user denied device")
+	case models.DeviceCodeApproved:
+	default:
+		return oauthError(c, fiber.StatusBadRequest, "invalid_grant", "device code is already used")
+	}
+
+	// Tokens are issued only once per request.
+	consumed, err := db.ConsumeDeviceCode(code.ID)
+	if err != nil {
+		return oauthInternalError(c, fiber.StatusInternalServerError, "server_error", err)
+	}
+	if !consumed || code.UserID == nil {
+		return oauthError(c, fiber.StatusBadRequest, "invalid_grant", "device code is already used")
+	}
+
+	// Get user, who approved device.
+	foundedUser, err := db.GetUserByID(*code.UserID)
+	if err != nil {
+		return oauthError(c, fiber.StatusBadRequest, "invalid_grant", "user of device code is not found")
+	}
+
+	// Generate a new pair of tokens for user, with a new refresh token family.
+	accessToken, refreshToken, err := issueTokens(db, &foundedUser, uuid.New(), code.TwoFactor)
+	if err != nil {
+		return oauthInternalError(c, fiber.StatusInternalServerError, "server_error", err)
+	}
+	recordSecurityEvent(db, c, models.SignInSucceededEvent, &foundedUser.ID, foundedUser.Username)
+
+	// Return status 200 OK.
+	return c.JSON(fiber.Map{
+		"access_token":  accessToken,
+		"refresh_token": refreshToken,
+		"token_type":    "Bearer",
+		"expires_in":    int(utils.AccessTokenExpiration(now).Sub(now).Seconds()),
+	})
+}
+
+// pendingDeviceCode func for get pending request, which is not expired, by user code.
+func pendingDeviceCode(db *database.Queries, userCode string) (models.DeviceCode, error) {
+	code, err := db.GetDeviceCodeByUserCode(utils.NormalizeUserCode(userCode))
+	if err != nil || code.Status != models.DeviceCodePending || time.Now().After(code.ExpiresAt) {
+		return models.DeviceCode{}, errDeviceCodeNotFound
+	}
+
+	return code, nil
+}
+
+// deviceCodeLifetime func for get lifetime of device authorization request
+// from .env file (DEVICE_CODE_EXPIRE_MINUTES, default 10).
+func deviceCodeLifetime() time.Duration {
+	minutes, err := strconv.Atoi(os.Getenv("DEVICE_CODE_EXPIRE_MINUTES"))
+	if err != nil || minutes <= 0 {
+		minutes = 10
+	}
+
+	return time.Duration(minutes) * time.Minute
+}
+
+// devicePollInterval func for get minimal seconds between polls of device
+// from .env file (DEVICE_CODE_POLL_SECONDS, default 5).
+func devicePollInterval() int {
+	seconds, err := strconv.Atoi(os.Getenv("DEVICE_CODE_POLL_SECONDS"))
+	if err != nil || seconds <= 0 {
+		seconds = 5
+	}
+
+	return seconds
+}
+
+// deviceVerificationURI func for get URL of page, where user types user code, from
+// .env file (DEVICE_VERIFICATION_URI), or "/device" of this server.
+func deviceVerificationURI(c *fiber.Ctx) string {
+	if uri := os.Getenv("DEVICE_VERIFICATION_URI"); uri != "" {
+		return uri
+	}
+
+	return c.BaseURL() + "/device"
+}
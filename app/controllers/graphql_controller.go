@@ -0,0 +1,518 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/acl"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/middleware"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/problem"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/repository"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/utils"
+)
+
+// Codes of GraphQL errors in "extensions" (same as in Apollo Server).
+const (
+	graphQLUnauthenticated = "UNAUTHENTICATED"
+	graphQLForbidden       = "FORBIDDEN"
+	graphQLBadUserInput    = "BAD_USER_INPUT"
+	graphQLNotFound        = "NOT_FOUND"
+	graphQLTooManyRequests = "TOO_MANY_REQUESTS"
+	graphQLInternalError   = "INTERNAL_SERVER_ERROR"
+)
+
+// graphQLRequest struct to describe body of GraphQL request.
+type graphQLRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// GraphQL func for execute GraphQL query of admin-ui (Amplication conventions).
+// @Description Execute GraphQL query or mutation. Users, books, servers and Info have the same
+// @Description queries and mutations as in the Amplication server, like users, user, _usersMeta,
+// @Description createUser, updateUser, deleteUser, and login mutation returns access token.
+// @Summary execute GraphQL query
+// @Tags GraphQL
+// @Accept json
+// @Produce json
+// @Param request body object true "GraphQL request with query, operationName and variables"
+// @Param X-Organization-ID header string false "Organization ID of books, servers and Info, default organization of user is used without it"
+// @Success 200 {object} graphql.Result
+// @Security ApiKeyAuth
+// @Router /graphql [post]
+func GraphQL(c *fiber.Ctx) error {
+	// Create a new GraphQL request struct.
+	request := &graphQLRequest{}
+
+	// Checking received data from JSON body.
+	if err := c.BodyParser(request); err != nil {
+		// Return status 400 and error message.
+		return problem.BadRequest(err.Error())
+	}
+
+	// Get schema of app, it's built once.
+	schema, err := currentGraphQLSchema()
+	if err != nil {
+		// Return status 500 and schema error.
+		return problem.Internal(err)
+	}
+
+	// Principal is set by middleware for callers with credentials.
+	g := &graphQLContext{c: c}
+	if principal, err := utils.GetPrincipal(c); err == nil {
+		g.principal = principal
+	}
+
+	// Execute query, errors of resolvers are returned in "errors" with status 200.
+	result := graphql.Do(graphql.Params{
+		Schema:         schema,
+		RequestString:  request.Query,
+		OperationName:  request.OperationName,
+		VariableValues: request.Variables,
+		Context:        context.WithValue(c.Context(), graphQLContextKey{}, g),
+	})
+	hideGraphQLInternalErrors(c, result)
+
+	return c.JSON(result)
+}
+
+// IsGraphQLLogin func for checking, if GraphQL request has login mutation, so
+// it's rate limited like sign in (see routes.GraphQLRoute). Requests, which
+// can't be parsed, are rejected by GraphQL anyway.
+func IsGraphQLLogin(c *fiber.Ctx) bool {
+	request := &graphQLRequest{}
+	if err := json.Unmarshal(c.Body(), request); err != nil {
+		return false
+	}
+	document, err := parser.Parse(parser.ParseParams{Source: request.Query})
+	if err != nil {
+		return false
+	}
+
+	// Fragments may be used in selection of mutation.
+	fragments := map[string]*ast.FragmentDefinition{}
+	for _, definition := range document.Definitions {
+		if fragment, ok := definition.(*ast.FragmentDefinition); ok {
+			fragments[fragment.Name.Value] = fragment
+		}
+	}
+
+	// All mutations are checked, not only operation of request.
+	for _, definition := range document.Definitions {
+		operation, ok := definition.(*ast.OperationDefinition)
+		if ok && operation.Operation == ast.OperationTypeMutation && selectsGraphQLField(operation.SelectionSet, "login", fragments, map[string]bool{}) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// selectsGraphQLField func for checking, if selection set has field of the given
+// name, directly or in fragments.
+func selectsGraphQLField(set *ast.SelectionSet, name string, fragments map[string]*ast.FragmentDefinition, visited map[string]bool) bool {
+	if set == nil {
+		return false
+	}
+
+	for _, selection := range set.Selections {
+		switch selection := selection.(type) {
+		case *ast.Field:
+			if selection.Name.Value == name {
+				return true
+			}
+		case *ast.InlineFragment:
+			if selectsGraphQLField(selection.SelectionSet, name, fragments, visited) {
+				return true
+			}
+		case *ast.FragmentSpread:
+			fragment, ok := fragments[selection.Name.Value]
+			if ok && !visited[fragment.Name.Value] {
+				visited[fragment.Name.Value] = true
+				if selectsGraphQLField(fragment.SelectionSet, name, fragments, visited) {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+var graphQLSchema struct {
+	once   sync.Once
+	schema graphql.Schema
+	err    error
+}
+
+// currentGraphQLSchema func for get schema of app with all resources.
+func currentGraphQLSchema() (graphql.Schema, error) {
+	graphQLSchema.once.Do(func() {
+		query, mutation := graphql.Fields{}, graphql.Fields{}
+		addGraphQLAuth(query, mutation)
+		for _, r := range []*graphQLResource{graphQLUsers(), graphQLBooks(), graphQLServers(), graphQLInfo()} {
+			r.addTo(query, mutation)
+		}
+
+		graphQLSchema.schema, graphQLSchema.err = graphql.NewSchema(graphql.SchemaConfig{
+			Query:    graphql.NewObject(graphql.ObjectConfig{Name: "Query", Fields: query}),
+			Mutation: graphql.NewObject(graphql.ObjectConfig{Name: "Mutation", Fields: mutation}),
+		})
+	})
+
+	return graphQLSchema.schema, graphQLSchema.err
+}
+
+type graphQLContextKey struct{}
+
+// graphQLContext struct to describe the current GraphQL request for resolvers.
+type graphQLContext struct {
+	c         *fiber.Ctx
+	principal *utils.Principal // nil for anonymous callers
+	tenant    *utils.Tenant    // resolved on first use
+	logins    int              // count of login mutations, only one is allowed
+}
+
+// graphQLContextOf func for get the current GraphQL request of resolver.
+func graphQLContextOf(p graphql.ResolveParams) *graphQLContext {
+	return p.Context.Value(graphQLContextKey{}).(*graphQLContext)
+}
+
+// permission method for get permission of caller by grants, like AccessControlled.
+func (g *graphQLContext) permission(resource, action string) (acl.Permission, error) {
+	roles := []string{repository.AnonymousRoleName}
+	if g.principal != nil {
+		roles = g.principal.Roles
+	}
+
+	permission := acl.CurrentPolicy().Permission(roles, resource, action)
+	if !permission.Granted && g.principal == nil {
+		return permission, newGraphQLError(graphQLUnauthenticated, "unauthorized, credentials are required")
+	}
+	if !permission.Granted || (g.principal != nil && !g.principal.AllowsScope(resource, action)) {
+		return permission, newGraphQLError(graphQLForbidden, "permission denied, check credentials of your token")
+	}
+
+	return permission, nil
+}
+
+// owner method for get ID of the current user, if permission allows only own
+// records, or uuid.Nil otherwise.
+func (g *graphQLContext) owner(permission acl.Permission) (uuid.UUID, error) {
+	if permission.Possession != acl.PossessionOwn {
+		return uuid.Nil, nil
+	}
+	if g.principal == nil || g.principal.UserID == uuid.Nil {
+		return uuid.Nil, newGraphQLError(graphQLForbidden, "permission denied, token subject is not a user")
+	}
+
+	return g.principal.UserID, nil
+}
+
+// currentTenant method for get organization of request, like TenantScoped.
+func (g *graphQLContext) currentTenant() (*utils.Tenant, error) {
+	if g.tenant != nil {
+		return g.tenant, nil
+	}
+	if g.principal == nil {
+		return nil, newGraphQLError(graphQLUnauthenticated, "unauthorized, credentials are required")
+	}
+
+	tenant, err := middleware.ResolveTenant(g.c, g.principal)
+	if err != nil {
+		switch err.Status {
+		case fiber.StatusBadRequest:
+			return nil, newGraphQLError(graphQLBadUserInput, err.Msg)
+		case fiber.StatusForbidden:
+			return nil, newGraphQLError(graphQLForbidden, err.Msg)
+		}
+		return nil, newGraphQLInternalError(errors.New(err.Msg))
+	}
+	g.tenant = tenant
+
+	return tenant, nil
+}
+
+// graphQLError struct to describe error of resolver with code in "extensions".
+type graphQLError struct {
+	code   string
+	msg    string
+	fields map[string]string // invalid fields of input
+	cause  error             // internal error, it's logged and never sent to client
+}
+
+func newGraphQLError(code, msg string) *graphQLError {
+	return &graphQLError{code: code, msg: msg}
+}
+
+// newGraphQLInternalError func for make internal error of resolver, like problem.Internal.
+func newGraphQLInternalError(err error) *graphQLError {
+	return &graphQLError{code: graphQLInternalError, msg: "internal error, report correlation ID to support", cause: err}
+}
+
+// hideGraphQLInternalErrors func for replace internal errors of resolvers in
+// result by message with correlation ID, errors are logged like by problem.Handler.
+// Errors of resolvers, which are not graphQLError, are internal too.
+func hideGraphQLInternalErrors(c *fiber.Ctx, result *graphql.Result) {
+	for i, formatted := range result.Errors {
+		err := formatted.OriginalError()
+		if located, ok := err.(*gqlerrors.Error); ok {
+			err = located.OriginalError
+		}
+
+		// Errors of query, like syntax errors, have no original error.
+		var resolverErr *graphQLError
+		if err == nil || (errors.As(err, &resolverErr) && resolverErr.code != graphQLInternalError) {
+			continue
+		}
+		if resolverErr != nil && resolverErr.cause != nil {
+			err = resolverErr.cause
+		}
+
+		id := problem.Report(c, err)
+		result.Errors[i].Message = "internal error, report correlation ID to support"
+		result.Errors[i].Extensions = map[string]interface{}{"code": graphQLInternalError, "correlationId": id}
+	}
+}
+
+// graphQLValidationError func for make error of invalid fields of input.
+func graphQLValidationError(err error) *graphQLError {
+	return &graphQLError{code: graphQLBadUserInput, msg: "fields of input are not valid", fields: utils.ValidatorErrors(err)}
+}
+
+// Error method for get message of error.
+func (e *graphQLError) Error() string {
+	return e.msg
+}
+
+// Extensions method for get code of error for clients.
+func (e *graphQLError) Extensions() map[string]interface{} {
+	extensions := map[string]interface{}{"code": e.code}
+	if e.fields != nil {
+		extensions["fields"] = e.fields
+	}
+
+	return extensions
+}
+
+// graphQLResource struct to describe resource of GraphQL schema. Queries and
+// mutations of resource are named by Amplication conventions, like books,
+// book, _booksMeta, createBook, updateBook and deleteBook for "Book".
+type graphQLResource struct {
+	name     string // name of object type, like "Book"
+	plural   string // name of list query, like "books"
+	resource string // resource of grants, like "books"
+	object   *graphql.Object
+	where    *graphql.InputObject
+	orderBy  *graphql.InputObject
+	create   *graphql.InputObject
+	update   *graphql.InputObject
+
+	// Functions of resource get arguments of query and return records with
+	// names of fields of object type. FindOne returns nil for unknown ID.
+	findMany  func(g *graphQLContext, args map[string]interface{}, permission acl.Permission) ([]map[string]interface{}, int, error)
+	findOne   func(g *graphQLContext, id uuid.UUID, permission acl.Permission) (map[string]interface{}, error)
+	createOne func(g *graphQLContext, data map[string]interface{}) (map[string]interface{}, error)
+	updateOne func(g *graphQLContext, id uuid.UUID, data map[string]interface{}, permission acl.Permission) (map[string]interface{}, error)
+	deleteOne func(g *graphQLContext, id uuid.UUID, permission acl.Permission) (map[string]interface{}, error)
+}
+
+// addTo method for add queries and mutations of resource to fields of schema.
+func (r *graphQLResource) addTo(query, mutation graphql.Fields) {
+	single := strings.ToLower(r.name[:1]) + r.name[1:]
+	whereUnique := newGraphQLWhereUniqueInput(r.name + "WhereUniqueInput")
+
+	query[r.plural] = &graphql.Field{
+		Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(r.object))),
+		Args: graphql.FieldConfigArgument{
+			"where":   &graphql.ArgumentConfig{Type: r.where},
+			"orderBy": &graphql.ArgumentConfig{Type: graphql.NewList(graphql.NewNonNull(r.orderBy))},
+			"skip":    &graphql.ArgumentConfig{Type: graphql.Int},
+			"take":    &graphql.ArgumentConfig{Type: graphql.Int},
+		},
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			g := graphQLContextOf(p)
+			permission, err := g.permission(r.resource, "read")
+			if err != nil {
+				return nil, err
+			}
+
+			records, _, err := r.findMany(g, p.Args, permission)
+			if err != nil {
+				return nil, err
+			}
+			for i, record := range records {
+				records[i] = permission.Filter(record)
+			}
+
+			return records, nil
+		},
+	}
+
+	query[single] = &graphql.Field{
+		Type: r.object,
+		Args: graphql.FieldConfigArgument{
+			"where": &graphql.ArgumentConfig{Type: graphql.NewNonNull(whereUnique)},
+		},
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			g := graphQLContextOf(p)
+			permission, err := g.permission(r.resource, "read")
+			if err != nil {
+				return nil, err
+			}
+
+			id, err := graphQLUniqueID(p.Args)
+			if err != nil {
+				return nil, err
+			}
+			record, err := r.findOne(g, id, permission)
+			if err != nil || record == nil {
+				return nil, err
+			}
+
+			return permission.Filter(record), nil
+		},
+	}
+
+	query["_"+r.plural+"Meta"] = &graphql.Field{
+		Type: graphql.NewNonNull(graphQLMetaQueryPayload),
+		Args: graphql.FieldConfigArgument{
+			"where": &graphql.ArgumentConfig{Type: r.where},
+		},
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			g := graphQLContextOf(p)
+			permission, err := g.permission(r.resource, "read")
+			if err != nil {
+				return nil, err
+			}
+
+			// Only count of records of filter is needed.
+			_, count, err := r.findMany(g, map[string]interface{}{"where": p.Args["where"], "take": 0}, permission)
+			if err != nil {
+				return nil, err
+			}
+
+			return map[string]interface{}{"count": count}, nil
+		},
+	}
+
+	mutation["create"+r.name] = &graphql.Field{
+		Type: graphql.NewNonNull(r.object),
+		Args: graphql.FieldConfigArgument{
+			"data": &graphql.ArgumentConfig{Type: graphql.NewNonNull(r.create)},
+		},
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			g := graphQLContextOf(p)
+			data, _ := p.Args["data"].(map[string]interface{})
+			permission, err := g.permission(r.resource, "create")
+			if err != nil {
+				return nil, err
+			}
+			if len(permission.InvalidAttributes(data)) > 0 {
+				return nil, newGraphQLError(graphQLForbidden, "permission denied, check credentials of your token")
+			}
+
+			record, err := r.createOne(g, data)
+			if err != nil {
+				return nil, err
+			}
+
+			return permission.Filter(record), nil
+		},
+	}
+
+	mutation["update"+r.name] = &graphql.Field{
+		Type: r.object,
+		Args: graphql.FieldConfigArgument{
+			"where": &graphql.ArgumentConfig{Type: graphql.NewNonNull(whereUnique)},
+			"data":  &graphql.ArgumentConfig{Type: graphql.NewNonNull(r.update)},
+		},
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			g := graphQLContextOf(p)
+			data, _ := p.Args["data"].(map[string]interface{})
+			permission, err := g.permission(r.resource, "update")
+			if err != nil {
+				return nil, err
+			}
+			if len(permission.InvalidAttributes(data)) > 0 {
+				return nil, newGraphQLError(graphQLForbidden, "permission denied, check credentials of your token")
+			}
+
+			id, err := graphQLUniqueID(p.Args)
+			if err != nil {
+				return nil, err
+			}
+			record, err := r.updateOne(g, id, data, permission)
+			if err != nil {
+				return nil, err
+			}
+
+			return permission.Filter(record), nil
+		},
+	}
+
+	mutation["delete"+r.name] = &graphql.Field{
+		Type: r.object,
+		Args: graphql.FieldConfigArgument{
+			"where": &graphql.ArgumentConfig{Type: graphql.NewNonNull(whereUnique)},
+		},
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			g := graphQLContextOf(p)
+			permission, err := g.permission(r.resource, "delete")
+			if err != nil {
+				return nil, err
+			}
+
+			id, err := graphQLUniqueID(p.Args)
+			if err != nil {
+				return nil, err
+			}
+			record, err := r.deleteOne(g, id, permission)
+			if err != nil {
+				return nil, err
+			}
+
+			return permission.Filter(record), nil
+		},
+	}
+}
+
+// graphQLUniqueID func for get ID of record from "where" argument of WhereUniqueInput type.
+func graphQLUniqueID(args map[string]interface{}) (uuid.UUID, error) {
+	where, _ := args["where"].(map[string]interface{})
+	value, _ := where["id"].(string)
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, newGraphQLError(graphQLBadUserInput, "ID of record is not valid")
+	}
+
+	return id, nil
+}
+
+// graphQLNotFoundError func for make error of record, which is not found by ID.
+func graphQLNotFoundError(id uuid.UUID) *graphQLError {
+	return newGraphQLError(graphQLNotFound, `No resource was found for {"id":"`+id.String()+`"}`)
+}
+
+// decodeGraphQLArgs func for decode arguments of query to struct with the same JSON names.
+func decodeGraphQLArgs(args map[string]interface{}, v interface{}) error {
+	data, err := json.Marshal(args)
+	if err == nil {
+		err = json.Unmarshal(data, v)
+	}
+	if err != nil {
+		return newGraphQLError(graphQLBadUserInput, err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/graphql-go/graphql"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/problem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHideGraphQLInternalErrors(t *testing.T) {
+	resolve := func(err error) graphql.FieldResolveFn {
+		return func(p graphql.ResolveParams) (interface{}, error) {
+			return nil, err
+		}
+	}
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{Name: "Query", Fields: graphql.Fields{
+			"internal": &graphql.Field{Type: graphql.String, Resolve: resolve(newGraphQLInternalError(errors.New("pq: relation does not exist")))},
+			"raw":      &graphql.Field{Type: graphql.String, Resolve: resolve(errors.New("pq: connection refused"))},
+			"notFound": &graphql.Field{Type: graphql.String, Resolve: resolve(newGraphQLError(graphQLNotFound, "book is not found"))},
+		}}),
+	})
+	require.NoError(t, err)
+
+	app := fiber.New()
+	app.Get("/graphql", func(c *fiber.Ctx) error {
+		result := graphql.Do(graphql.Params{Schema: schema, RequestString: c.Query("query")})
+		hideGraphQLInternalErrors(c, result)
+		return c.JSON(result)
+	})
+
+	tests := []struct {
+		description string
+		query       string
+		expected    string // code of error
+		internal    bool
+	}{
+		{"internal error of resolver", "{internal}", graphQLInternalError, true},
+		{"error of resolver, which is not graphQLError", "{raw}", graphQLInternalError, true},
+		{"error of client", "{notFound}", graphQLNotFound, false},
+		{"error of query", "{unknown}", "", false},
+	}
+
+	for _, test := range tests {
+		req := httptest.NewRequest("GET", "/graphql?query="+test.query, nil)
+		resp, err := app.Test(req)
+		require.NoError(t, err, test.description)
+
+		body, _ := io.ReadAll(resp.Body)
+		result := struct {
+			Errors []struct {
+				Message    string                 `json:"message"`
+				Extensions map[string]interface{} `json:"extensions"`
+			} `json:"errors"`
+		}{}
+		require.NoError(t, json.Unmarshal(body, &result), test.description)
+		require.Lenf(t, result.Errors, 1, test.description)
+
+		if !test.internal {
+			assert.NotContainsf(t, result.Errors[0].Extensions, "correlationId", test.description)
+			if test.expected != "" {
+				assert.Equalf(t, test.expected, result.Errors[0].Extensions["code"], test.description)
+			}
+			continue
+		}
+
+		// Internal errors are hidden, client gets only correlation ID.
+		assert.NotContainsf(t, string(body), "pq:", test.description)
+		assert.Equalf(t, test.expected, result.Errors[0].Extensions["code"], test.description)
+		assert.NotEmptyf(t, result.Errors[0].Extensions["correlationId"], test.description)
+		assert.Equalf(t, result.Errors[0].Extensions["correlationId"], resp.Header.Get(problem.HeaderCorrelationID), test.description)
+	}
+}
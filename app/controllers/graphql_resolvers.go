@@ -0,0 +1,909 @@
+package controllers
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/graphql-go/graphql"
+	"github.com/koddr/tutorial-go-fiber-rest-api/app/models"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/acl"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/utils"
+	"github.com/koddr/tutorial-go-fiber-rest-api/platform/database"
+)
+
+var graphQLUserInfo = graphql.NewObject(graphql.ObjectConfig{
+	Name: "UserInfo",
+	Fields: graphql.Fields{
+		"id":          &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"username":    &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"roles":       &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String)))},
+		"accessToken": &graphql.Field{Type: graphql.String},
+	},
+})
+
+// addGraphQLAuth func for add login mutation and userInfo query to fields of schema.
+func addGraphQLAuth(query, mutation graphql.Fields) {
+	credentials := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "Credentials",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"username": &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+			"password": &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+		},
+	})
+
+	mutation["login"] = &graphql.Field{
+		Type: graphql.NewNonNull(graphQLUserInfo),
+		Args: graphql.FieldConfigArgument{
+			"credentials": &graphql.ArgumentConfig{Type: graphql.NewNonNull(credentials)},
+		},
+		Resolve: graphQLLogin,
+	}
+
+	query["userInfo"] = &graphql.Field{
+		Type:    graphql.NewNonNull(graphQLUserInfo),
+		Resolve: graphQLCurrentUserInfo,
+	}
+}
+
+// graphQLLogin func for sign in of user by username and password, like UserSignIn.
+// Only access token is issued; users with the second factor sign in by REST API.
+func graphQLLogin(p graphql.ResolveParams) (interface{}, error) {
+	g := graphQLContextOf(p)
+
+	// Request is rate limited as one sign in, so it can't try many passwords.
+	if g.logins++; g.logins > 1 {
+		return nil, newGraphQLError(graphQLBadUserInput, "only one login mutation is allowed in request")
+	}
+
+	// Get username and password of sign in.
+	credentials, _ := p.Args["credentials"].(map[string]interface{})
+	signIn := &models.SignIn{}
+	if err := decodeGraphQLArgs(credentials, signIn); err != nil {
+		return nil, err
+	}
+	if err := utils.NewValidator().Struct(signIn); err != nil {
+		return nil, graphQLValidationError(err)
+	}
+
+	// Create database connection.
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		return nil, newGraphQLInternalError(err)
+	}
+
+	// Check username and password, failed attempts are counted.
+	user, wait, err := checkCredentials(g.c, db, signIn)
+	if wait > 0 {
+		g.c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+		return nil, newGraphQLError(graphQLTooManyRequests, err.Error())
+	}
+	if errors.Is(err, errWrongCredentials) {
+		return nil, newGraphQLError(graphQLUnauthenticated, err.Error())
+	}
+	if err != nil {
+		return nil, newGraphQLInternalError(err)
+	}
+
+	// The second factor is verified only by REST API.
+	twoFactor, err := db.HasTwoFactor(user.ID)
+	if err != nil {
+		return nil, newGraphQLInternalError(err)
+	}
+	if twoFactor {
+		return nil, newGraphQLError(graphQLForbidden, "second factor is required, sign in with /api/v1/user/sign/in")
+	}
+
+	// Forget failures of username, like signInCompleted.
+	if err := db.ResetLoginAttempts(userLoginKey(user.Username)); err != nil {
+		return nil, newGraphQLInternalError(err)
+	}
+	recordSecurityEvent(db, g.c, models.SignInSucceededEvent, &user.ID, user.Username)
+
+	// Generate a new Access token with identity of user.
+	roles := utils.EffectiveRoles(user.Roles, false)
+	accessToken, err := utils.GenerateNewAccessToken(&utils.Principal{
+		Subject: user.ID.String(),
+		Roles:   roles,
+	})
+	if err != nil {
+		return nil, newGraphQLInternalError(err)
+	}
+
+	return map[string]interface{}{
+		"id":          user.ID.String(),
+		"username":    user.Username,
+		"roles":       roles,
+		"accessToken": accessToken,
+	}, nil
+}
+
+// graphQLCurrentUserInfo func for get user of the current request.
+func graphQLCurrentUserInfo(p graphql.ResolveParams) (interface{}, error) {
+	g := graphQLContextOf(p)
+	if g.principal == nil {
+		return nil, newGraphQLError(graphQLUnauthenticated, "unauthorized, credentials are required")
+	}
+	if g.principal.UserID == uuid.Nil {
+		return nil, newGraphQLError(graphQLForbidden, "permission denied, token subject is not a user")
+	}
+
+	// Create database connection.
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		return nil, newGraphQLInternalError(err)
+	}
+
+	// Get user of principal.
+	user, err := db.GetUserByID(g.principal.UserID)
+	if err != nil {
+		return nil, graphQLNotFoundError(g.principal.UserID)
+	}
+
+	return map[string]interface{}{
+		"id":       user.ID.String(),
+		"username": user.Username,
+		"roles":    g.principal.Roles,
+	}, nil
+}
+
+// graphQLUsers func for describe users of GraphQL schema, like REST API of users.
+func graphQLUsers() *graphQLResource {
+	return &graphQLResource{
+		name:     "User",
+		plural:   "users",
+		resource: "users",
+		object: graphql.NewObject(graphql.ObjectConfig{
+			Name: "User",
+			Fields: graphql.Fields{
+				"createdAt": &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+				"firstName": &graphql.Field{Type: graphql.String},
+				"id":        &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+				"lastName":  &graphql.Field{Type: graphql.String},
+				"roles":     &graphql.Field{Type: graphql.NewNonNull(graphQLJSON)},
+				"updatedAt": &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+				"username":  &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			},
+		}),
+		where: graphql.NewInputObject(graphql.InputObjectConfig{
+			Name: "UserWhereInput",
+			Fields: graphql.InputObjectConfigFieldMap{
+				"firstName": &graphql.InputObjectFieldConfig{Type: graphQLStringNullableFilter},
+				"id":        &graphql.InputObjectFieldConfig{Type: graphQLStringFilter},
+				"lastName":  &graphql.InputObjectFieldConfig{Type: graphQLStringNullableFilter},
+				"username":  &graphql.InputObjectFieldConfig{Type: graphQLStringFilter},
+			},
+		}),
+		orderBy: newGraphQLOrderByInput("UserOrderByInput", "createdAt", "firstName", "id", "lastName", "roles", "updatedAt", "username"),
+		create: graphql.NewInputObject(graphql.InputObjectConfig{
+			Name: "UserCreateInput",
+			Fields: graphql.InputObjectConfigFieldMap{
+				"firstName": &graphql.InputObjectFieldConfig{Type: graphql.String},
+				"lastName":  &graphql.InputObjectFieldConfig{Type: graphql.String},
+				"password":  &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+				"roles":     &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphQLJSON)},
+				"username":  &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+			},
+		}),
+		update: graphql.NewInputObject(graphql.InputObjectConfig{
+			Name: "UserUpdateInput",
+			Fields: graphql.InputObjectConfigFieldMap{
+				"firstName": &graphql.InputObjectFieldConfig{Type: graphql.String},
+				"lastName":  &graphql.InputObjectFieldConfig{Type: graphql.String},
+				"password":  &graphql.InputObjectFieldConfig{Type: graphql.String},
+				"roles":     &graphql.InputObjectFieldConfig{Type: graphQLJSON},
+				"username":  &graphql.InputObjectFieldConfig{Type: graphql.String},
+			},
+		}),
+		findMany:  findGraphQLUsers,
+		findOne:   findGraphQLUser,
+		createOne: createGraphQLUser,
+		updateOne: updateGraphQLUser,
+		deleteOne: deleteGraphQLUser,
+	}
+}
+
+// graphQLUserRecord func for get fields of user in GraphQL.
+func graphQLUserRecord(u *models.User) map[string]interface{} {
+	return map[string]interface{}{
+		"createdAt": u.CreatedAt,
+		"firstName": u.FirstName,
+		"id":        u.ID.String(),
+		"lastName":  u.LastName,
+		"roles":     u.Roles,
+		"updatedAt": u.UpdatedAt,
+		"username":  u.Username,
+	}
+}
+
+func findGraphQLUsers(g *graphQLContext, args map[string]interface{}, permission acl.Permission) ([]map[string]interface{}, int, error) {
+	// Get filter, sort orders and page of users.
+	findArgs := &models.UserFindManyArgs{Take: 100}
+	if err := decodeGraphQLArgs(args, findArgs); err != nil {
+		return nil, 0, err
+	}
+	if err := utils.NewValidator().Struct(findArgs); err != nil {
+		return nil, 0, graphQLValidationError(err)
+	}
+
+	// Callers with "read:own" grant get only themselves.
+	owner, err := g.owner(permission)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Create database connection.
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		return nil, 0, newGraphQLInternalError(err)
+	}
+
+	// Get page of users.
+	users, count, err := db.GetUsers(findArgs, owner)
+	if err != nil {
+		return nil, 0, newGraphQLInternalError(err)
+	}
+
+	records := make([]map[string]interface{}, len(users))
+	for i := range users {
+		records[i] = graphQLUserRecord(&users[i])
+	}
+
+	return records, count, nil
+}
+
+func findGraphQLUser(g *graphQLContext, id uuid.UUID, permission acl.Permission) (map[string]interface{}, error) {
+	// Callers with "read:own" grant get only themselves.
+	owner, err := g.owner(permission)
+	if err != nil {
+		return nil, err
+	}
+	if owner != uuid.Nil && owner != id {
+		return nil, newGraphQLError(graphQLForbidden, "permission denied, check credentials of your token")
+	}
+
+	// Create database connection.
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		return nil, newGraphQLInternalError(err)
+	}
+
+	// Get user by ID, unknown user is null.
+	user, err := db.GetUserByID(id)
+	if err != nil {
+		return nil, nil
+	}
+
+	return graphQLUserRecord(&user), nil
+}
+
+func createGraphQLUser(g *graphQLContext, data map[string]interface{}) (map[string]interface{}, error) {
+	// Get fields of a new user.
+	input := &models.UserCreateInput{}
+	if err := decodeGraphQLArgs(data, input); err != nil {
+		return nil, err
+	}
+	if err := utils.NewValidator().Struct(input); err != nil {
+		return nil, graphQLValidationError(err)
+	}
+
+	// Create database connection.
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		return nil, newGraphQLInternalError(err)
+	}
+
+	// Checking, if username is not taken.
+	if _, err := db.GetUserByUsername(input.Username); err == nil {
+		return nil, newGraphQLError(graphQLBadUserInput, "user with the given username already exists")
+	}
+
+	// Make hash from the given password.
+	passwordHash, err := utils.GeneratePassword(input.Password)
+	if err != nil {
+		return nil, newGraphQLInternalError(err)
+	}
+
+	// Create a new user.
+	user := &models.User{
+		ID:           uuid.New(),
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
+		Username:     input.Username,
+		FirstName:    input.FirstName,
+		LastName:     input.LastName,
+		Roles:        input.Roles,
+		PasswordHash: passwordHash,
+	}
+	if err := db.CreateUser(user); err != nil {
+		return nil, newGraphQLInternalError(err)
+	}
+
+	return graphQLUserRecord(user), nil
+}
+
+func updateGraphQLUser(g *graphQLContext, id uuid.UUID, data map[string]interface{}, permission acl.Permission) (map[string]interface{}, error) {
+	// Get changed fields of user.
+	input := &models.UserUpdateInput{}
+	if err := decodeGraphQLArgs(data, input); err != nil {
+		return nil, err
+	}
+	if err := utils.NewValidator().Struct(input); err != nil {
+		return nil, graphQLValidationError(err)
+	}
+
+	// Callers with "update:own" grant change only themselves.
+	owner, err := g.owner(permission)
+	if err != nil {
+		return nil, err
+	}
+	if owner != uuid.Nil && owner != id {
+		return nil, newGraphQLError(graphQLForbidden, "permission denied, check credentials of your token")
+	}
+
+	// Create database connection.
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		return nil, newGraphQLInternalError(err)
+	}
+
+	// Checking, if user with given ID is exists.
+	user, err := db.GetUserByID(id)
+	if err != nil {
+		return nil, graphQLNotFoundError(id)
+	}
+
+	// Set only given fields of user, like UpdateUser.
+	if input.Username != nil && *input.Username != user.Username {
+		if _, err := db.GetUserByUsername(*input.Username); err == nil {
+			return nil, newGraphQLError(graphQLBadUserInput, "user with the given username already exists")
+		}
+		user.Username = *input.Username
+	}
+	if input.FirstName != nil {
+		user.FirstName = *input.FirstName
+	}
+	if input.LastName != nil {
+		user.LastName = *input.LastName
+	}
+	if input.Roles != nil {
+		user.Roles = *input.Roles
+	}
+	if input.Password != nil {
+		if user.PasswordHash, err = utils.GeneratePassword(*input.Password); err != nil {
+			return nil, newGraphQLInternalError(err)
+		}
+	}
+	user.UpdatedAt = time.Now()
+
+	// Update user.
+	if err := db.UpdateUser(&user); err != nil {
+		return nil, newGraphQLInternalError(err)
+	}
+
+	// Sessions, which were signed in with the old password, are revoked.
+	if input.Password != nil {
+		if err := db.RevokeUserRefreshTokens(user.ID); err != nil {
+			return nil, newGraphQLInternalError(err)
+		}
+		if err := db.RevokeUserSessions(user.ID); err != nil {
+			return nil, newGraphQLInternalError(err)
+		}
+		recordSecurityEvent(db, g.c, models.PasswordChangedEvent, &user.ID, user.Username)
+	}
+
+	return graphQLUserRecord(&user), nil
+}
+
+func deleteGraphQLUser(g *graphQLContext, id uuid.UUID, permission acl.Permission) (map[string]interface{}, error) {
+	// Callers with "delete:own" grant delete only themselves.
+	owner, err := g.owner(permission)
+	if err != nil {
+		return nil, err
+	}
+	if owner != uuid.Nil && owner != id {
+		return nil, newGraphQLError(graphQLForbidden, "permission denied, check credentials of your token")
+	}
+
+	// Create database connection.
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		return nil, newGraphQLInternalError(err)
+	}
+
+	// Checking, if user with given ID is exists.
+	user, err := db.GetUserByID(id)
+	if err != nil {
+		return nil, graphQLNotFoundError(id)
+	}
+
+	// Delete user by given ID.
+	if err := db.DeleteUser(id); err != nil {
+		return nil, newGraphQLInternalError(err)
+	}
+
+	return graphQLUserRecord(&user), nil
+}
+
+// graphQLOrganizationRecords struct to describe records of organization, like
+// books, in GraphQL. Functions get records of tenant with its row-level security.
+type graphQLOrganizationRecords struct {
+	find   func(db *database.Queries, tenant *utils.Tenant, args map[string]interface{}, owner uuid.UUID) ([]map[string]interface{}, int, error)
+	get    func(db *database.Queries, tenant *utils.Tenant, id uuid.UUID) (record map[string]interface{}, ownerID uuid.UUID, err error)
+	create func(db *database.Queries, tenant *utils.Tenant, userID uuid.UUID, data map[string]interface{}) (map[string]interface{}, error)
+	update func(db *database.Queries, tenant *utils.Tenant, id uuid.UUID, data map[string]interface{}) (map[string]interface{}, error)
+	delete func(db *database.Queries, tenant *utils.Tenant, id uuid.UUID) error
+}
+
+// withTenant method for get tenant of request and its database connection.
+func (r *graphQLOrganizationRecords) withTenant(g *graphQLContext) (*utils.Tenant, *database.Queries, error) {
+	tenant, err := g.currentTenant()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Create database connection with row-level security of user.
+	db, err := database.OpenTenantDBConnection(tenant.UserID)
+	if err != nil {
+		return nil, nil, newGraphQLInternalError(err)
+	}
+
+	return tenant, db, nil
+}
+
+// resource method for describe records as resource of GraphQL schema.
+func (r *graphQLOrganizationRecords) resource(resource *graphQLResource) *graphQLResource {
+	resource.findMany = func(g *graphQLContext, args map[string]interface{}, permission acl.Permission) ([]map[string]interface{}, int, error) {
+		tenant, db, err := r.withTenant(g)
+		if err != nil {
+			return nil, 0, err
+		}
+		owner, err := g.owner(permission)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		return r.find(db, tenant, args, owner)
+	}
+
+	resource.findOne = func(g *graphQLContext, id uuid.UUID, permission acl.Permission) (map[string]interface{}, error) {
+		tenant, db, err := r.withTenant(g)
+		if err != nil {
+			return nil, err
+		}
+		owner, err := g.owner(permission)
+		if err != nil {
+			return nil, err
+		}
+
+		// Unknown records and records of other users for "read:own" grant are null.
+		record, ownerID, err := r.get(db, tenant, id)
+		if err != nil || (owner != uuid.Nil && owner != ownerID) {
+			return nil, nil
+		}
+
+		return record, nil
+	}
+
+	resource.createOne = func(g *graphQLContext, data map[string]interface{}) (map[string]interface{}, error) {
+		tenant, db, err := r.withTenant(g)
+		if err != nil {
+			return nil, err
+		}
+
+		// Checking, if the current user can create records of organization.
+		if !tenant.CanWrite() {
+			return nil, newGraphQLError(graphQLForbidden, "permission denied, check credentials of your token")
+		}
+
+		return r.create(db, tenant, g.principal.UserID, data)
+	}
+
+	resource.updateOne = func(g *graphQLContext, id uuid.UUID, data map[string]interface{}, permission acl.Permission) (map[string]interface{}, error) {
+		tenant, db, err := r.withTenant(g)
+		if err != nil {
+			return nil, err
+		}
+
+		// Checking, if record with given ID is exists and can be changed.
+		_, ownerID, err := r.get(db, tenant, id)
+		if err != nil {
+			return nil, graphQLNotFoundError(id)
+		}
+		if !canModifyRecord(g.principal, tenant, permission, true, ownerID) {
+			return nil, newGraphQLError(graphQLForbidden, "permission denied, check credentials of your token")
+		}
+
+		return r.update(db, tenant, id, data)
+	}
+
+	resource.deleteOne = func(g *graphQLContext, id uuid.UUID, permission acl.Permission) (map[string]interface{}, error) {
+		tenant, db, err := r.withTenant(g)
+		if err != nil {
+			return nil, err
+		}
+
+		// Checking, if record with given ID is exists and can be deleted.
+		record, ownerID, err := r.get(db, tenant, id)
+		if err != nil {
+			return nil, graphQLNotFoundError(id)
+		}
+		if !canModifyRecord(g.principal, tenant, permission, true, ownerID) {
+			return nil, newGraphQLError(graphQLForbidden, "permission denied, check credentials of your token")
+		}
+
+		if err := r.delete(db, tenant, id); err != nil {
+			return nil, newGraphQLInternalError(err)
+		}
+
+		return record, nil
+	}
+
+	return resource
+}
+
+// graphQLOwnerFilter func for get filter of records of the given owner, if it's given.
+func graphQLOwnerFilter(owner uuid.UUID, filter *models.StringFilter) *models.StringFilter {
+	if owner == uuid.Nil {
+		return filter
+	}
+
+	id := owner.String()
+	return &models.StringFilter{Equals: &id}
+}
+
+// newGraphQLRecordObject func for make object type of records of organization
+// with the given fields of record.
+func newGraphQLRecordObject(name string, fields graphql.Fields) *graphql.Object {
+	fields["id"] = &graphql.Field{Type: graphql.NewNonNull(graphql.String)}
+	fields["createdAt"] = &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)}
+	fields["updatedAt"] = &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)}
+	fields["userId"] = &graphql.Field{Type: graphql.NewNonNull(graphql.String)}
+
+	return graphql.NewObject(graphql.ObjectConfig{Name: name, Fields: fields})
+}
+
+// newGraphQLRecordWhereInput func for make filter type of records of organization
+// with the given string fields and status field.
+func newGraphQLRecordWhereInput(name, first, second, status string) *graphql.InputObject {
+	return graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: name,
+		Fields: graphql.InputObjectConfigFieldMap{
+			"id":     &graphql.InputObjectFieldConfig{Type: graphQLStringFilter},
+			"userId": &graphql.InputObjectFieldConfig{Type: graphQLStringFilter},
+			first:    &graphql.InputObjectFieldConfig{Type: graphQLStringFilter},
+			second:   &graphql.InputObjectFieldConfig{Type: graphQLStringFilter},
+			status:   &graphql.InputObjectFieldConfig{Type: graphQLIntFilter},
+		},
+	})
+}
+
+// newGraphQLRecordInput func for make input type of fields of records of
+// organization; string fields are required by create input.
+func newGraphQLRecordInput(name, first, second, status, attrs string, required bool) *graphql.InputObject {
+	var text graphql.Input = graphql.String
+	if required {
+		text = graphql.NewNonNull(graphql.String)
+	}
+
+	return graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: name,
+		Fields: graphql.InputObjectConfigFieldMap{
+			first:  &graphql.InputObjectFieldConfig{Type: text},
+			second: &graphql.InputObjectFieldConfig{Type: text},
+			status: &graphql.InputObjectFieldConfig{Type: graphql.Int},
+			attrs:  &graphql.InputObjectFieldConfig{Type: graphQLJSON},
+		},
+	})
+}
+
+// newGraphQLRecordResource func for describe records of organization with the
+// given fields as resource of GraphQL schema.
+func newGraphQLRecordResource(name, plural, resource, first, second, status, attrs string, records *graphQLOrganizationRecords) *graphQLResource {
+	return records.resource(&graphQLResource{
+		name:     name,
+		plural:   plural,
+		resource: resource,
+		object: newGraphQLRecordObject(name, graphql.Fields{
+			first:  &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			second: &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			status: &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			attrs:  &graphql.Field{Type: graphql.NewNonNull(graphQLJSON)},
+		}),
+		where:   newGraphQLRecordWhereInput(name+"WhereInput", first, second, status),
+		orderBy: newGraphQLOrderByInput(name+"OrderByInput", "createdAt", "updatedAt", "id", first, second, status),
+		create:  newGraphQLRecordInput(name+"CreateInput", first, second, status, attrs, true),
+		update:  newGraphQLRecordInput(name+"UpdateInput", first, second, status, attrs, false),
+	})
+}
+
+// graphQLBooks func for describe books of GraphQL schema, like REST API of books.
+func graphQLBooks() *graphQLResource {
+	record := func(b *models.Book) map[string]interface{} {
+		return map[string]interface{}{
+			"id":         b.ID.String(),
+			"createdAt":  b.CreatedAt,
+			"updatedAt":  b.UpdatedAt,
+			"userId":     b.UserID.String(),
+			"title":      b.Title,
+			"author":     b.Author,
+			"bookStatus": b.BookStatus,
+			"bookAttrs":  b.BookAttrs,
+		}
+	}
+	apply := func(b *models.Book, data map[string]interface{}) error {
+		input := &models.BookInput{}
+		if err := decodeGraphQLArgs(data, input); err != nil {
+			return err
+		}
+		if input.Title != nil {
+			b.Title = *input.Title
+		}
+		if input.Author != nil {
+			b.Author = *input.Author
+		}
+		if input.BookStatus != nil {
+			b.BookStatus = *input.BookStatus
+		}
+		if input.BookAttrs != nil {
+			b.BookAttrs = *input.BookAttrs
+		}
+		if err := utils.NewValidator().Struct(b); err != nil {
+			return graphQLValidationError(err)
+		}
+		return nil
+	}
+
+	return newGraphQLRecordResource("Book", "books", "books", "title", "author", "bookStatus", "bookAttrs", &graphQLOrganizationRecords{
+		find: func(db *database.Queries, tenant *utils.Tenant, args map[string]interface{}, owner uuid.UUID) ([]map[string]interface{}, int, error) {
+			findArgs := &models.BookFindManyArgs{Take: 100}
+			if err := decodeGraphQLArgs(args, findArgs); err != nil {
+				return nil, 0, err
+			}
+			if err := utils.NewValidator().Struct(findArgs); err != nil {
+				return nil, 0, graphQLValidationError(err)
+			}
+			findArgs.Where.UserID = graphQLOwnerFilter(owner, findArgs.Where.UserID)
+
+			books, count, err := db.FindBooks(tenant.OrganizationID, findArgs)
+			if err != nil {
+				return nil, 0, newGraphQLInternalError(err)
+			}
+			records := make([]map[string]interface{}, len(books))
+			for i := range books {
+				records[i] = record(&books[i])
+			}
+			return records, count, nil
+		},
+		get: func(db *database.Queries, tenant *utils.Tenant, id uuid.UUID) (map[string]interface{}, uuid.UUID, error) {
+			book, err := db.GetBook(tenant.OrganizationID, id)
+			if err != nil {
+				return nil, uuid.Nil, err
+			}
+			return record(&book), book.UserID, nil
+		},
+		create: func(db *database.Queries, tenant *utils.Tenant, userID uuid.UUID, data map[string]interface{}) (map[string]interface{}, error) {
+			book := &models.Book{}
+			Books.initRecord(book, userID, tenant.OrganizationID)
+			if err := apply(book, data); err != nil {
+				return nil, err
+			}
+			if err := db.CreateBook(book); err != nil {
+				return nil, newGraphQLInternalError(err)
+			}
+			return record(book), nil
+		},
+		update: func(db *database.Queries, tenant *utils.Tenant, id uuid.UUID, data map[string]interface{}) (map[string]interface{}, error) {
+			book, err := db.GetBook(tenant.OrganizationID, id)
+			if err != nil {
+				return nil, graphQLNotFoundError(id)
+			}
+			book.UpdatedAt = time.Now()
+			if err := apply(&book, data); err != nil {
+				return nil, err
+			}
+			if err := db.UpdateBook(id, &book); err != nil {
+				return nil, newGraphQLInternalError(err)
+			}
+			return record(&book), nil
+		},
+		delete: func(db *database.Queries, tenant *utils.Tenant, id uuid.UUID) error {
+			return db.DeleteBook(tenant.OrganizationID, id)
+		},
+	})
+}
+
+// graphQLServers func for describe servers of GraphQL schema, like REST API of servers.
+func graphQLServers() *graphQLResource {
+	record := func(s *models.Server) map[string]interface{} {
+		return map[string]interface{}{
+			"id":           s.ID.String(),
+			"createdAt":    s.CreatedAt,
+			"updatedAt":    s.UpdatedAt,
+			"userId":       s.UserID.String(),
+			"title":        s.Title,
+			"author":       s.Author,
+			"serverStatus": s.ServerStatus,
+			"serverAttrs":  s.ServerAttrs,
+		}
+	}
+	apply := func(s *models.Server, data map[string]interface{}) error {
+		input := &models.ServerInput{}
+		if err := decodeGraphQLArgs(data, input); err != nil {
+			return err
+		}
+		if input.Title != nil {
+			s.Title = *input.Title
+		}
+		if input.Author != nil {
+			s.Author = *input.Author
+		}
+		if input.ServerStatus != nil {
+			s.ServerStatus = *input.ServerStatus
+		}
+		if input.ServerAttrs != nil {
+			s.ServerAttrs = *input.ServerAttrs
+		}
+		if err := utils.NewValidator().Struct(s); err != nil {
+			return graphQLValidationError(err)
+		}
+		return nil
+	}
+
+	return newGraphQLRecordResource("Server", "servers", "servers", "title", "author", "serverStatus", "serverAttrs", &graphQLOrganizationRecords{
+		find: func(db *database.Queries, tenant *utils.Tenant, args map[string]interface{}, owner uuid.UUID) ([]map[string]interface{}, int, error) {
+			findArgs := &models.ServerFindManyArgs{Take: 100}
+			if err := decodeGraphQLArgs(args, findArgs); err != nil {
+				return nil, 0, err
+			}
+			if err := utils.NewValidator().Struct(findArgs); err != nil {
+				return nil, 0, graphQLValidationError(err)
+			}
+			findArgs.Where.UserID = graphQLOwnerFilter(owner, findArgs.Where.UserID)
+
+			servers, count, err := db.FindServers(tenant.OrganizationID, findArgs)
+			if err != nil {
+				return nil, 0, newGraphQLInternalError(err)
+			}
+			records := make([]map[string]interface{}, len(servers))
+			for i := range servers {
+				records[i] = record(&servers[i])
+			}
+			return records, count, nil
+		},
+		get: func(db *database.Queries, tenant *utils.Tenant, id uuid.UUID) (map[string]interface{}, uuid.UUID, error) {
+			server, err := db.GetServer(tenant.OrganizationID, id)
+			if err != nil {
+				return nil, uuid.Nil, err
+			}
+			return record(&server), server.UserID, nil
+		},
+		create: func(db *database.Queries, tenant *utils.Tenant, userID uuid.UUID, data map[string]interface{}) (map[string]interface{}, error) {
+			server := &models.Server{}
+			Servers.initRecord(server, userID, tenant.OrganizationID)
+			if err := apply(server, data); err != nil {
+				return nil, err
+			}
+			if err := db.CreateServer(server); err != nil {
+				return nil, newGraphQLInternalError(err)
+			}
+			return record(server), nil
+		},
+		update: func(db *database.Queries, tenant *utils.Tenant, id uuid.UUID, data map[string]interface{}) (map[string]interface{}, error) {
+			server, err := db.GetServer(tenant.OrganizationID, id)
+			if err != nil {
+				return nil, graphQLNotFoundError(id)
+			}
+			server.UpdatedAt = time.Now()
+			if err := apply(&server, data); err != nil {
+				return nil, err
+			}
+			if err := db.UpdateServer(id, &server); err != nil {
+				return nil, newGraphQLInternalError(err)
+			}
+			return record(&server), nil
+		},
+		delete: func(db *database.Queries, tenant *utils.Tenant, id uuid.UUID) error {
+			return db.DeleteServer(tenant.OrganizationID, id)
+		},
+	})
+}
+
+// graphQLInfo func for describe Info of GraphQL schema, like REST API of Info.
+func graphQLInfo() *graphQLResource {
+	record := func(i *models.Info) map[string]interface{} {
+		return map[string]interface{}{
+			"id":         i.ID.String(),
+			"createdAt":  i.CreatedAt,
+			"updatedAt":  i.UpdatedAt,
+			"userId":     i.UserID.String(),
+			"name":       i.Name,
+			"portfolio":  i.Portfolio,
+			"infoStatus": i.InfoStatus,
+			"infoAttrs":  i.InfoAttrs,
+		}
+	}
+	apply := func(i *models.Info, data map[string]interface{}) error {
+		input := &models.InfoInput{}
+		if err := decodeGraphQLArgs(data, input); err != nil {
+			return err
+		}
+		if input.Name != nil {
+			i.Name = *input.Name
+		}
+		if input.Portfolio != nil {
+			i.Portfolio = *input.Portfolio
+		}
+		if input.InfoStatus != nil {
+			i.InfoStatus = *input.InfoStatus
+		}
+		if input.InfoAttrs != nil {
+			i.InfoAttrs = *input.InfoAttrs
+		}
+		if err := utils.NewValidator().Struct(i); err != nil {
+			return graphQLValidationError(err)
+		}
+		return nil
+	}
+
+	return newGraphQLRecordResource("Info", "infos", "info", "name", "portfolio", "infoStatus", "infoAttrs", &graphQLOrganizationRecords{
+		find: func(db *database.Queries, tenant *utils.Tenant, args map[string]interface{}, owner uuid.UUID) ([]map[string]interface{}, int, error) {
+			findArgs := &models.InfoFindManyArgs{Take: 100}
+			if err := decodeGraphQLArgs(args, findArgs); err != nil {
+				return nil, 0, err
+			}
+			if err := utils.NewValidator().Struct(findArgs); err != nil {
+				return nil, 0, graphQLValidationError(err)
+			}
+			findArgs.Where.UserID = graphQLOwnerFilter(owner, findArgs.Where.UserID)
+
+			info, count, err := db.FindInfo(tenant.OrganizationID, findArgs)
+			if err != nil {
+				return nil, 0, newGraphQLInternalError(err)
+			}
+			records := make([]map[string]interface{}, len(info))
+			for i := range info {
+				records[i] = record(&info[i])
+			}
+			return records, count, nil
+		},
+		get: func(db *database.Queries, tenant *utils.Tenant, id uuid.UUID) (map[string]interface{}, uuid.UUID, error) {
+			info, err := db.GetInfo(tenant.OrganizationID, id)
+			if err != nil {
+				return nil, uuid.Nil, err
+			}
+			return record(&info), info.UserID, nil
+		},
+		create: func(db *database.Queries, tenant *utils.Tenant, userID uuid.UUID, data map[string]interface{}) (map[string]interface{}, error) {
+			info := &models.Info{}
+			Info.initRecord(info, userID, tenant.OrganizationID)
+			if err := apply(info, data); err != nil {
+				return nil, err
+			}
+			if err := db.CreateInfo(info); err != nil {
+				return nil, newGraphQLInternalError(err)
+			}
+			return record(info), nil
+		},
+		update: func(db *database.Queries, tenant *utils.Tenant, id uuid.UUID, data map[string]interface{}) (map[string]interface{}, error) {
+			info, err := db.GetInfo(tenant.OrganizationID, id)
+			if err != nil {
+				return nil, graphQLNotFoundError(id)
+			}
+			info.UpdatedAt = time.Now()
+			if err := apply(&info, data); err != nil {
+				return nil, err
+			}
+			if err := db.UpdateInfo(id, &info); err != nil {
+				return nil, newGraphQLInternalError(err)
+			}
+			return record(&info), nil
+		},
+		delete: func(db *database.Queries, tenant *utils.Tenant, id uuid.UUID) error {
+			return db.DeleteInfo(tenant.OrganizationID, id)
+		},
+	})
+}
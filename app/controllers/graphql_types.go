@@ -0,0 +1,135 @@
+package controllers
+
+import (
+	"strconv"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/koddr/tutorial-go-fiber-rest-api/app/models"
+)
+
+// Types of GraphQL schema, which are shared by resources. Names and fields are
+// the same as in the Amplication server, so ra-data-graphql-amplication works.
+var (
+	// graphQLJSON is a scalar of any JSON value, like roles of user.
+	graphQLJSON = graphql.NewScalar(graphql.ScalarConfig{
+		Name:         "JSON",
+		Description:  "The `JSON` scalar type represents JSON values.",
+		Serialize:    func(value interface{}) interface{} { return value },
+		ParseValue:   func(value interface{}) interface{} { return value },
+		ParseLiteral: parseJSONLiteral,
+	})
+
+	graphQLSortOrder = graphql.NewEnum(graphql.EnumConfig{
+		Name: "SortOrder",
+		Values: graphql.EnumValueConfigMap{
+			"Asc":  &graphql.EnumValueConfig{Value: models.SortAsc},
+			"Desc": &graphql.EnumValueConfig{Value: models.SortDesc},
+		},
+	})
+
+	graphQLQueryMode = graphql.NewEnum(graphql.EnumConfig{
+		Name: "QueryMode",
+		Values: graphql.EnumValueConfigMap{
+			"Default":     &graphql.EnumValueConfig{Value: "default"},
+			"Insensitive": &graphql.EnumValueConfig{Value: models.InsensitiveMode},
+		},
+	})
+
+	graphQLStringFilter         = newGraphQLStringFilter("StringFilter")
+	graphQLStringNullableFilter = newGraphQLStringFilter("StringNullableFilter")
+
+	graphQLIntFilter = graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "IntFilter",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"equals": &graphql.InputObjectFieldConfig{Type: graphql.Int},
+			"in":     &graphql.InputObjectFieldConfig{Type: graphql.NewList(graphql.NewNonNull(graphql.Int))},
+			"notIn":  &graphql.InputObjectFieldConfig{Type: graphql.NewList(graphql.NewNonNull(graphql.Int))},
+			"lt":     &graphql.InputObjectFieldConfig{Type: graphql.Int},
+			"lte":    &graphql.InputObjectFieldConfig{Type: graphql.Int},
+			"gt":     &graphql.InputObjectFieldConfig{Type: graphql.Int},
+			"gte":    &graphql.InputObjectFieldConfig{Type: graphql.Int},
+			"not":    &graphql.InputObjectFieldConfig{Type: graphql.Int},
+		},
+	})
+
+	graphQLMetaQueryPayload = graphql.NewObject(graphql.ObjectConfig{
+		Name: "MetaQueryPayload",
+		Fields: graphql.Fields{
+			"count": &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+		},
+	})
+)
+
+// newGraphQLStringFilter func for make input type of StringFilter with the given name.
+func newGraphQLStringFilter(name string) *graphql.InputObject {
+	return graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: name,
+		Fields: graphql.InputObjectConfigFieldMap{
+			"equals":     &graphql.InputObjectFieldConfig{Type: graphql.String},
+			"in":         &graphql.InputObjectFieldConfig{Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+			"notIn":      &graphql.InputObjectFieldConfig{Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+			"lt":         &graphql.InputObjectFieldConfig{Type: graphql.String},
+			"lte":        &graphql.InputObjectFieldConfig{Type: graphql.String},
+			"gt":         &graphql.InputObjectFieldConfig{Type: graphql.String},
+			"gte":        &graphql.InputObjectFieldConfig{Type: graphql.String},
+			"contains":   &graphql.InputObjectFieldConfig{Type: graphql.String},
+			"startsWith": &graphql.InputObjectFieldConfig{Type: graphql.String},
+			"endsWith":   &graphql.InputObjectFieldConfig{Type: graphql.String},
+			"mode":       &graphql.InputObjectFieldConfig{Type: graphQLQueryMode},
+			"not":        &graphql.InputObjectFieldConfig{Type: graphql.String},
+		},
+	})
+}
+
+// newGraphQLOrderByInput func for make input type of sort orders by the given fields.
+func newGraphQLOrderByInput(name string, fields ...string) *graphql.InputObject {
+	config := graphql.InputObjectConfigFieldMap{}
+	for _, field := range fields {
+		config[field] = &graphql.InputObjectFieldConfig{Type: graphQLSortOrder}
+	}
+
+	return graphql.NewInputObject(graphql.InputObjectConfig{Name: name, Fields: config})
+}
+
+// newGraphQLWhereUniqueInput func for make input type, which selects one record by ID.
+func newGraphQLWhereUniqueInput(name string) *graphql.InputObject {
+	return graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: name,
+		Fields: graphql.InputObjectConfigFieldMap{
+			"id": &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+		},
+	})
+}
+
+// parseJSONLiteral func for get JSON value of literal of query.
+func parseJSONLiteral(valueAST ast.Value) interface{} {
+	switch value := valueAST.(type) {
+	case *ast.StringValue:
+		return value.Value
+	case *ast.BooleanValue:
+		return value.Value
+	case *ast.IntValue:
+		i, _ := strconv.ParseInt(value.Value, 10, 64)
+		return i
+	case *ast.FloatValue:
+		f, _ := strconv.ParseFloat(value.Value, 64)
+		return f
+	case *ast.EnumValue:
+		return value.Value
+	case *ast.ListValue:
+		list := make([]interface{}, len(value.Values))
+		for i, v := range value.Values {
+			list[i] = parseJSONLiteral(v)
+		}
+		return list
+	case *ast.ObjectValue:
+		object := map[string]interface{}{}
+		for _, field := range value.Fields {
+			object[field.Name.Value] = parseJSONLiteral(field.Value)
+		}
+		return object
+	}
+
+	return nil
+}
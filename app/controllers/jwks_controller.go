@@ -0,0 +1,30 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/problem"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/utils"
+)
+
+// GetJWKS func for get public keys to verify access tokens.
+// @Description Get public keys to verify access tokens (JSON Web Key Set).
+// @Summary get public keys to verify access tokens
+// @Tags Token
+// @Accept json
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /.well-known/jwks.json [get]
+func GetJWKS(c *fiber.Ctx) error {
+	// Get keys for verifying tokens.
+	keys, err := utils.JWTKeySet()
+	if err != nil {
+		// Return status 500 and key loading error.
+		return problem.Internal(err)
+	}
+
+	// Allow clients to cache keys for a while, they are rotated rarely.
+	c.Set(fiber.HeaderCacheControl, "public, max-age=300")
+
+	// Return status 200 OK.
+	return c.JSON(keys.PublicJWKS())
+}
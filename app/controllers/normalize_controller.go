@@ -0,0 +1,150 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/koddr/tutorial-go-fiber-rest-api/app/models"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/acl"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/normalize"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/problem"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/utils"
+	"github.com/koddr/tutorial-go-fiber-rest-api/platform/database"
+)
+
+// NormalizeDocument func for normalizes a JSON document and calculates its digest.
+// @Description Normalize a JSON document (RFC 8785) by inline options or a stored profile and calculate its digest.
+// @Summary normalize a JSON document
+// @Tags Normalize
+// @Accept json
+// @Produce json
+// @Param document body object true "JSON document"
+// @Param profile_id body string false "Profile ID, token of its owner is required"
+// @Param options body normalize.Options false "Normalization options"
+// @Param algorithm body string false "Digest algorithm (sha256, sha384, sha512)"
+// @Success 200 {object} models.Normalization
+// @Router /v1/normalize [post]
+func NormalizeDocument(c *fiber.Ctx) error {
+	// Create new Normalization struct
+	normalization := &models.Normalization{}
+
+	// Check, if received JSON data is valid.
+	if err := c.BodyParser(normalization); err != nil {
+		// Return status 400 and error message.
+		return problem.BadRequest(err.Error())
+	}
+
+	// Create a new validator for a Normalization model.
+	validate := utils.NewValidator()
+
+	// Validate normalization fields.
+	if err := validate.Struct(normalization); err != nil {
+		// Return, if some fields are not valid.
+		return problem.Validation(err)
+	}
+
+	// Set normalization options, inline options are used without a profile.
+	options := normalize.Options{}
+	if normalization.Options != nil {
+		options = *normalization.Options
+	}
+
+	if normalization.ProfileID != nil {
+		// Profiles are not public, callers use own profiles, if grants allow
+		// reading only own profiles.
+		owner, err := profileOwner(c)
+		if err != nil {
+			return err
+		}
+
+		// Create database connection.
+		db, err := database.OpenDBConnection()
+		if err != nil {
+			// Return status 500 and database connection error.
+			return problem.Internal(err)
+		}
+
+		// Get profile by ID.
+		profile, err := db.GetProfile(*normalization.ProfileID)
+		if err != nil || (owner != uuid.Nil && profile.UserID != owner) {
+			// Return, if profile not found.
+			return problem.NotFound("profile with the given ID is not found")
+		}
+
+		options = profile.ProfileOptions.Options
+	}
+
+	// Compile normalization options.
+	normalizer, err := normalize.New(options)
+	if err != nil {
+		// Return status 400 and options error.
+		return problem.BadRequest(err.Error())
+	}
+
+	// Normalize JSON document.
+	normalized, err := normalizer.Normalize(normalization.Document)
+	if err != nil {
+		// Return status 422 and document error.
+		return problem.Unprocessable(err.Error())
+	}
+
+	// Calculate digest of the normalized document.
+	digest, err := normalize.Digest(normalized, normalization.Algorithm)
+	if err != nil {
+		// Return status 400 and digest error.
+		return problem.BadRequest(err.Error())
+	}
+
+	// Set default digest algorithm.
+	if normalization.Algorithm == "" {
+		normalization.Algorithm = "sha256"
+	}
+
+	// Encode response without HTML escaping, so normalized document stays byte-exact.
+	response := &bytes.Buffer{}
+	encoder := json.NewEncoder(response)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(fiber.Map{
+		"error":      false,
+		"msg":        nil,
+		"normalized": json.RawMessage(normalized),
+		"algorithm":  normalization.Algorithm,
+		"digest":     digest,
+	}); err != nil {
+		// Return status 500 and encoding error.
+		return problem.Internal(err)
+	}
+
+	// Return status 200 OK.
+	c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	return c.Send(response.Bytes())
+}
+
+// profileOwner func for get ID of the current user, if grants allow reading
+// only own profiles, or uuid.Nil, if any profile can be read. Route of
+// normalization is public, so profiles are checked like by AccessControlled.
+func profileOwner(c *fiber.Ctx) (uuid.UUID, error) {
+	principal, err := utils.GetPrincipal(c)
+	if err != nil {
+		// Return status 401 and unauthorized error message.
+		return uuid.Nil, problem.Unauthorized("unauthorized, credentials are required to use profile")
+	}
+
+	permission := acl.CurrentPolicy().Permission(principal.Roles, "profiles", "read")
+	if !permission.Granted || !principal.AllowsScope("profiles", "read") {
+		// Return status 403 and permission denied error.
+		return uuid.Nil, problem.Forbidden("permission denied, check credentials of your token")
+	}
+	if permission.Possession != acl.PossessionOwn {
+		return uuid.Nil, nil
+	}
+
+	// Profiles are owned only by users, not by other subjects.
+	if principal.UserID == uuid.Nil {
+		return uuid.Nil, problem.Forbidden("permission denied, token subject is not a user")
+	}
+
+	return principal.UserID, nil
+}
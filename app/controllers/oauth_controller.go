@@ -0,0 +1,504 @@
+package controllers
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/koddr/tutorial-go-fiber-rest-api/app/models"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/problem"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/repository"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/revocation"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/utils"
+	"github.com/koddr/tutorial-go-fiber-rest-api/platform/database"
+)
+
+// errInvalidClient is an error of failed authentication of OAuth client.
+var errInvalidClient = errors.New("client authentication failed")
+
+// OAuthToken func for issue tokens by client credentials or device code.
+// @Description Issue access token by client credentials grant (RFC 6749, section 4.4),
+// @Description or access and refresh tokens of user by device code grant (RFC 8628, section 3.4).
+// @Summary issue tokens of OAuth grant
+// @Tags OAuth
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param grant_type formData string true "client_credentials or urn:ietf:params:oauth:grant-type:device_code"
+// @Param device_code formData string false "Device code, for device_code grant"
+// @Param scope formData string false "Space-separated scopes, all allowed scopes if not set"
+// @Param client_id formData string false "ID of client, if Basic authentication is not used (or ID of device)"
+// @Param client_secret formData string false "Secret of client, if Basic authentication is not used"
+// @Success 200 {string} status "ok"
+// @Router /oauth/token [post]
+func OAuthToken(c *fiber.Ctx) error {
+	// Responses with tokens must not be cached.
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	c.Set(fiber.HeaderPragma, "no-cache")
+
+	// Create database connection.
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		return oauthInternalError(c, fiber.StatusInternalServerError, "server_error", err)
+	}
+
+	// Issue tokens by the requested grant.
+	switch c.FormValue("grant_type") {
+	case "client_credentials":
+		return clientCredentialsToken(c, db)
+	case deviceCodeGrantType:
+		return deviceCodeToken(c, db)
+	default:
+		return oauthError(c, fiber.StatusBadRequest, "unsupported_grant_type", "only client_credentials and device_code grants are supported")
+	}
+}
+
+// clientCredentialsToken func for issue access token to OAuth client by its credentials.
+func clientCredentialsToken(c *fiber.Ctx, db *database.Queries) error {
+	// Authenticate client.
+	client, err := authenticateOAuthClient(c, db)
+	if err != nil {
+		return oauthClientError(c, err)
+	}
+
+	// Checking, if requested scopes are allowed to client.
+	scopes := strings.Fields(c.FormValue("scope"))
+	if len(scopes) == 0 {
+		scopes = client.Scopes
+	}
+	for _, scope := range scopes {
+		if !containsScope(client.Scopes, scope) {
+			return oauthError(c, fiber.StatusBadRequest, "invalid_scope", "scope "+scope+" is not allowed to client")
+		}
+
+		// Records are owned by service account of client, without it records can't be written.
+		if client.UserID == nil && strings.HasSuffix(scope, ":write") {
+			return oauthError(c, fiber.StatusBadRequest, "invalid_scope", "scope "+scope+" needs service account of client, register client again")
+		}
+	}
+
+	// Clients have at least the role of usual user, scopes limit what they can do.
+	roles := []string(client.Roles)
+	if len(roles) == 0 {
+		roles = []string{repository.UserRoleName}
+	}
+
+	// Subject of token is service account of client, or client, if it has no service account.
+	subject := client.ClientID
+	if client.UserID != nil {
+		subject = client.UserID.String()
+	}
+
+	// Generate a new access token.
+	now := time.Now()
+	accessToken, err := utils.GenerateNewAccessToken(&utils.Principal{
+		Subject:  subject,
+		ClientID: client.ClientID,
+		Roles:    roles,
+		Scopes:   scopes,
+	})
+	if err != nil {
+		return oauthInternalError(c, fiber.StatusInternalServerError, "server_error", err)
+	}
+
+	// Return status 200 OK.
+	return c.JSON(fiber.Map{
+		"access_token": accessToken,
+		"token_type":   "Bearer",
+		"expires_in":   int(utils.AccessTokenExpiration(now).Sub(now).Seconds()),
+		"scope":        strings.Join(scopes, " "),
+	})
+}
+
+// OAuthIntrospect func for get state of access token.
+// @Description Get state and claims of access token (RFC 7662). Caller is authenticated as OAuth client.
+// @Summary introspect access token
+// @Tags OAuth
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param token formData string true "Access token"
+// @Param token_type_hint formData string false "access_token"
+// @Success 200 {string} status "ok"
+// @Router /oauth/introspect [post]
+func OAuthIntrospect(c *fiber.Ctx) error {
+	// Create database connection.
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		return oauthInternalError(c, fiber.StatusInternalServerError, "server_error", err)
+	}
+
+	// Authenticate client, which asks for state of token.
+	if _, err := authenticateOAuthClient(c, db); err != nil {
+		return oauthClientError(c, err)
+	}
+
+	// Tokens, which are not valid, revoked or issued to revoked clients, are inactive.
+	principal, err := parseOAuthToken(c.FormValue("token"))
+	if err != nil {
+		return c.JSON(fiber.Map{"active": false})
+	}
+	if principal.ClientID != "" {
+		tokenClient, err := db.GetOAuthClientByClientID(principal.ClientID)
+		if err != nil || tokenClient.RevokedAt != nil {
+			return c.JSON(fiber.Map{"active": false})
+		}
+	}
+
+	// Return status 200 OK and claims of active token.
+	response := fiber.Map{
+		"active":     true,
+		"token_type": "Bearer",
+		"sub":        principal.Subject,
+		"scope":      strings.Join(principal.Scopes, " "),
+		"roles":      principal.Roles,
+		"jti":        principal.TokenID,
+		"iat":        principal.IssuedAt,
+		"exp":        principal.Expires,
+	}
+	if principal.ClientID != "" {
+		response["client_id"] = principal.ClientID
+	}
+
+	return c.JSON(response)
+}
+
+// OAuthRevoke func for revoke access token of OAuth client.
+// @Description Revoke access token issued to the calling client (RFC 7009). Unknown tokens are ignored.
+// @Summary revoke access token of OAuth client
+// @Tags OAuth
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param token formData string true "Access token"
+// @Param token_type_hint formData string false "access_token"
+// @Success 200 {string} status "ok"
+// @Router /oauth/revoke [post]
+func OAuthRevoke(c *fiber.Ctx) error {
+	// Create database connection.
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		return oauthInternalError(c, fiber.StatusInternalServerError, "server_error", err)
+	}
+
+	// Authenticate client, which revokes token.
+	client, err := authenticateOAuthClient(c, db)
+	if err != nil {
+		return oauthClientError(c, err)
+	}
+
+	// Tokens, which are not valid (or already expired), need no revocation.
+	principal, err := parseOAuthToken(c.FormValue("token"))
+	if err != nil {
+		return c.SendStatus(fiber.StatusOK)
+	}
+
+	// Client can revoke only its own tokens.
+	if principal.ClientID != client.ClientID {
+		return oauthError(c, fiber.StatusBadRequest, "unauthorized_client", "token was not issued to client")
+	}
+
+	// Revoke token until its expiration.
+	if err := revokePrincipalToken(principal); err != nil {
+		return oauthInternalError(c, fiber.StatusServiceUnavailable, "temporarily_unavailable", err)
+	}
+
+	// Return status 200 OK.
+	return c.SendStatus(fiber.StatusOK)
+}
+
+// GetOAuthClients func gets all OAuth clients (admin only).
+// @Description Get all registered OAuth clients (without secrets), for admins only.
+// @Summary get all OAuth clients
+// @Tags OAuth
+// @Accept json
+// @Produce json
+// @Success 200 {array} models.OAuthClient
+// @Security ApiKeyAuth
+// @Router /v1/admin/oauth/clients [get]
+func GetOAuthClients(c *fiber.Ctx) error {
+	// Get principal of the current request.
+	principal, err := utils.GetPrincipal(c)
+	if err != nil {
+		// Return status 401 and unauthorized error message.
+		return problem.Unauthorized(err.Error())
+	}
+
+	// Checking, if principal is admin.
+	if !principal.IsAdmin() {
+		// Return status 403 and permission denied error.
+		return forbidden(c)
+	}
+
+	// Create database connection.
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		// Return status 500 and database connection error.
+		return problem.Internal(err)
+	}
+
+	// Get all OAuth clients.
+	clients, err := db.GetOAuthClients()
+	if err != nil {
+		// Return status 500 and database query error.
+		return problem.Internal(err)
+	}
+
+	// Return status 200 OK.
+	return c.JSON(fiber.Map{
+		"error":   false,
+		"msg":     nil,
+		"count":   len(clients),
+		"clients": clients,
+	})
+}
+
+// CreateOAuthClient func for register a new OAuth client (admin only).
+// @Description Register a new OAuth client. Its secret is returned only once, only its hash is stored.
+// @Summary register a new OAuth client
+// @Tags OAuth
+// @Accept json
+// @Produce json
+// @Param name body string true "Name"
+// @Param scopes body []string true "Allowed scopes, like books:read or servers:write"
+// @Param roles body []string false "Roles of access tokens (default user)"
+// @Param organization_id body string false "Organization of service account of client, personal organization if not set"
+// @Param organization_role body string false "Role of service account in organization: admin, member (default) or viewer"
+// @Success 200 {object} models.OAuthClient
+// @Security ApiKeyAuth
+// @Router /v1/admin/oauth/client [post]
+func CreateOAuthClient(c *fiber.Ctx) error {
+	// Get principal of the current request.
+	principal, err := utils.GetPrincipal(c)
+	if err != nil {
+		// Return status 401 and unauthorized error message.
+		return problem.Unauthorized(err.Error())
+	}
+
+	// Checking, if principal is admin.
+	if !principal.IsAdmin() {
+		// Return status 403 and permission denied error.
+		return forbidden(c)
+	}
+
+	// Create new NewOAuthClient struct
+	newClient := &models.NewOAuthClient{}
+
+	// Check, if received JSON data is valid.
+	if err := c.BodyParser(newClient); err != nil {
+		// Return status 400 and error message.
+		return problem.BadRequest(err.Error())
+	}
+
+	// Validate OAuth client fields.
+	if err := utils.NewValidator().Struct(newClient); err != nil {
+		// Return, if some fields are not valid.
+		return problem.Validation(err)
+	}
+
+	// Generate credentials of client.
+	clientID, secret, hash, err := utils.GenerateNewOAuthClient()
+	if err != nil {
+		// Return status 500 and credentials generation error.
+		return problem.Internal(err)
+	}
+
+	// Create database connection.
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		// Return status 500 and database connection error.
+		return problem.Internal(err)
+	}
+
+	// Checking, if organization of service account is exists.
+	if newClient.OrganizationID != uuid.Nil {
+		if _, err := db.GetOrganization(newClient.OrganizationID); err != nil {
+			// Return status 404 and organization not found error.
+			return problem.NotFound("organization with this ID not found")
+		}
+	}
+
+	// Set initialized default data for OAuth client and its service account,
+	// which owns records created by client:
+	account := &models.ServiceAccount{
+		UserID:    uuid.New(),
+		CreatedAt: time.Now(),
+		Identity:  models.OAuthClientIdentityPrefix + clientID,
+	}
+	client := &models.OAuthClient{
+		ID:         uuid.New(),
+		CreatedAt:  account.CreatedAt,
+		ClientID:   clientID,
+		SecretHash: hash,
+		Name:       newClient.Name,
+		Scopes:     newClient.Scopes,
+		Roles:      newClient.Roles,
+		UserID:     &account.UserID,
+	}
+	if len(client.Roles) == 0 {
+		client.Roles = models.StringList{repository.UserRoleName}
+	}
+
+	// Create a new OAuth client with its service account.
+	if err := db.CreateOAuthClient(client, account, newClient.OrganizationID, newClient.OrganizationRole); err != nil {
+		// Return status 500 and error message.
+		return problem.Internal(err)
+	}
+
+	// Return status 200 OK, this is the only time the secret is shown.
+	return c.JSON(fiber.Map{
+		"error":         false,
+		"msg":           nil,
+		"client":        client,
+		"client_secret": secret,
+	})
+}
+
+// RevokeOAuthClient func for revoke OAuth client by given ID (admin only).
+// @Description Revoke OAuth client by given ID. Its tokens become inactive for introspection and expire soon.
+// @Summary revoke OAuth client by given ID
+// @Tags OAuth
+// @Accept json
+// @Produce json
+// @Param id body string true "OAuth client ID"
+// @Success 204 {string} status "ok"
+// @Security ApiKeyAuth
+// @Router /v1/admin/oauth/client [delete]
+func RevokeOAuthClient(c *fiber.Ctx) error {
+	// Get principal of the current request.
+	principal, err := utils.GetPrincipal(c)
+	if err != nil {
+		// Return status 401 and unauthorized error message.
+		return problem.Unauthorized(err.Error())
+	}
+
+	// Checking, if principal is admin.
+	if !principal.IsAdmin() {
+		// Return status 403 and permission denied error.
+		return forbidden(c)
+	}
+
+	// Create new OAuthClient struct
+	client := &models.OAuthClient{}
+
+	// Check, if received JSON data is valid.
+	if err := c.BodyParser(client); err != nil {
+		// Return status 400 and error message.
+		return problem.BadRequest(err.Error())
+	}
+
+	// Create database connection.
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		// Return status 500 and database connection error.
+		return problem.Internal(err)
+	}
+
+	// Checking, if OAuth client with given ID is exists.
+	foundedClient, err := db.GetOAuthClient(client.ID)
+	if err != nil {
+		// Return status 404 and OAuth client not found error.
+		return problem.NotFound("OAuth client with this ID not found")
+	}
+
+	// Revoke OAuth client by given ID.
+	if err := db.RevokeOAuthClient(foundedClient.ID); err != nil {
+		// Return status 500 and error message.
+		return problem.Internal(err)
+	}
+
+	// Return status 204 no content.
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// authenticateOAuthClient func for get OAuth client by credentials of request,
+// sent by HTTP Basic authentication or in the form body (RFC 6749, section 2.3.1).
+func authenticateOAuthClient(c *fiber.Ctx, db *database.Queries) (*models.OAuthClient, error) {
+	clientID, secret := c.FormValue("client_id"), c.FormValue("client_secret")
+	if header := c.Get(fiber.HeaderAuthorization); strings.HasPrefix(header, "Basic ") {
+		credentials, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(header, "Basic "))
+		if err != nil {
+			return nil, errInvalidClient
+		}
+		parts := strings.SplitN(string(credentials), ":", 2)
+		if len(parts) != 2 {
+			return nil, errInvalidClient
+		}
+
+		// Credentials are form-encoded before Basic encoding.
+		if clientID, err = url.QueryUnescape(parts[0]); err != nil {
+			return nil, errInvalidClient
+		}
+		if secret, err = url.QueryUnescape(parts[1]); err != nil {
+			return nil, errInvalidClient
+		}
+	}
+	if clientID == "" || secret == "" {
+		return nil, errInvalidClient
+	}
+
+	// Get client by its ID, revoked clients can't be authenticated.
+	client, err := db.GetOAuthClientByClientID(clientID)
+	if err != nil || client.RevokedAt != nil || !utils.CompareSecretHash(client.SecretHash, secret) {
+		return nil, errInvalidClient
+	}
+
+	return &client, nil
+}
+
+// parseOAuthToken func for make principal from access token, which is
+// valid and not revoked.
+func parseOAuthToken(tokenString string) (*utils.Principal, error) {
+	principal, err := utils.ParsePrincipal(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if revocation.CurrentDenylist().IsRevoked(principal.TokenID) {
+		return nil, errors.New("token is revoked")
+	}
+
+	return principal, nil
+}
+
+// containsScope func for checking, if scope is in the list of scopes.
+func containsScope(scopes []string, scope string) bool {
+	for _, s := range scopes {
+		if s == scope {
+			return true
+		}
+	}
+
+	return false
+}
+
+// oauthClientError func for return error of failed client authentication.
+func oauthClientError(c *fiber.Ctx, err error) error {
+	if err == errInvalidClient {
+		c.Set(fiber.HeaderWWWAuthenticate, `Basic realm="oauth"`)
+		return oauthError(c, fiber.StatusUnauthorized, "invalid_client", err.Error())
+	}
+
+	return oauthInternalError(c, fiber.StatusInternalServerError, "server_error", err)
+}
+
+// oauthInternalError func for return internal error in format of OAuth 2.0.
+// Error is logged with correlation ID like by problem.Handler, client gets
+// only the ID, because error may contain details of database.
+func oauthInternalError(c *fiber.Ctx, status int, code string, err error) error {
+	id := problem.Report(c, err)
+
+	return c.Status(status).JSON(fiber.Map{
+		"error":             code,
+		"error_description": "internal error, report correlation ID to support",
+		"correlation_id":    id,
+	})
+}
+
+// oauthError func for return error in format of OAuth 2.0 (RFC 6749, section 5.2).
+func oauthError(c *fiber.Ctx, status int, code, description string) error {
+	return c.Status(status).JSON(fiber.Map{
+		"error":             code,
+		"error_description": description,
+	})
+}
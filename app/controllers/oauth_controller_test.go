@@ -0,0 +1,34 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/problem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOAuthInternalError(t *testing.T) {
+	app := fiber.New()
+	app.Post("/oauth/token", func(c *fiber.Ctx) error {
+		return oauthInternalError(c, fiber.StatusInternalServerError, "server_error", errors.New("pq: connection refused"))
+	})
+
+	resp, err := app.Test(httptest.NewRequest("POST", "/oauth/token", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 500, resp.StatusCode)
+
+	// Internal errors are hidden, client gets only correlation ID.
+	body, _ := io.ReadAll(resp.Body)
+	assert.NotContains(t, string(body), "pq:")
+	response := map[string]string{}
+	require.NoError(t, json.Unmarshal(body, &response))
+	assert.Equal(t, "server_error", response["error"])
+	assert.NotEmpty(t, response["correlation_id"])
+	assert.Equal(t, response["correlation_id"], resp.Header.Get(problem.HeaderCorrelationID))
+}
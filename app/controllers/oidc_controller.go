@@ -0,0 +1,195 @@
+package controllers
+
+import (
+	"crypto/subtle"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/koddr/tutorial-go-fiber-rest-api/app/models"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/oidc"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/problem"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/repository"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/utils"
+	"github.com/koddr/tutorial-go-fiber-rest-api/platform/database"
+)
+
+// oidcStateCookie is a name of cookie with signed state of login.
+const oidcStateCookie = "oidc_state"
+
+// noPasswordHash is stored for users of OpenID Connect provider,
+// it never matches any password, so they can't sign in by password.
+const noPasswordHash = "!"
+
+// UserOIDCLogin method to redirect user for login at OpenID Connect provider.
+// @Description Redirect to OpenID Connect provider for login (authorization code flow with PKCE).
+// @Summary redirect to OpenID Connect provider for login
+// @Tags User
+// @Success 302 {string} status "redirect"
+// @Router /v1/user/oidc/login [get]
+func UserOIDCLogin(c *fiber.Ctx) error {
+	// Get the configured provider.
+	provider, err := oidc.CurrentProvider()
+	if err != nil {
+		// Return status 503 and provider error.
+		return problem.Unavailable(err)
+	}
+
+	// Generate state, nonce and PKCE code verifier for the login.
+	state := &oidc.LoginState{Expires: time.Now().Add(10 * time.Minute).Unix()}
+	for _, value := range []*string{&state.State, &state.Nonce, &state.Verifier} {
+		if *value, err = oidc.RandomString(); err != nil {
+			// Return status 500 and random generation error.
+			return problem.Internal(err)
+		}
+	}
+
+	// Keep state of the login in signed cookie until callback.
+	cookie, err := oidc.EncodeState(oidc.StateSecret(), state)
+	if err != nil {
+		// Return status 500 and encoding error.
+		return problem.Internal(err)
+	}
+	c.Cookie(&fiber.Cookie{
+		Name:     oidcStateCookie,
+		Value:    cookie,
+		Path:     "/api/v1/user/oidc",
+		MaxAge:   10 * 60,
+		Secure:   c.Protocol() == "https",
+		HTTPOnly: true,
+		SameSite: "Lax", // cookie must be sent on redirect back from provider
+	})
+
+	// Return status 302 and redirect to provider.
+	return c.Redirect(provider.AuthCodeURL(state.State, state.Nonce, state.Verifier), fiber.StatusFound)
+}
+
+// UserOIDCCallback method to finish login at OpenID Connect provider and return access and refresh tokens.
+// @Description Finish login at OpenID Connect provider, create or update user and return access and refresh tokens.
+// @Summary finish login at OpenID Connect provider
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param code query string true "Authorization code"
+// @Param state query string true "State of login"
+// @Success 200 {string} status "ok"
+// @Router /v1/user/oidc/callback [get]
+func UserOIDCCallback(c *fiber.Ctx) error {
+	// Get the configured provider.
+	provider, err := oidc.CurrentProvider()
+	if err != nil {
+		// Return status 503 and provider error.
+		return problem.Unavailable(err)
+	}
+
+	// Get state of the login from cookie, it can be used only once.
+	state, err := oidc.DecodeState(oidc.StateSecret(), c.Cookies(oidcStateCookie))
+	c.ClearCookie(oidcStateCookie)
+	if err != nil {
+		// Return status 400 and state error.
+		return problem.BadRequest(err.Error())
+	}
+
+	// Checking, if provider returned the same state (CSRF protection).
+	if subtle.ConstantTimeCompare([]byte(c.Query("state")), []byte(state.State)) != 1 {
+		// Return status 400 and state error.
+		return problem.BadRequest("login state is not valid")
+	}
+
+	// Checking, if user denied login or provider failed.
+	if errorCode := c.Query("error"); errorCode != "" {
+		// Return status 401 and provider error.
+		return problem.Unauthorized(errorCode + ": " + c.Query("error_description"))
+	}
+
+	// Exchange authorization code to tokens and verify ID token.
+	tokens, err := provider.Exchange(c.Query("code"), state.Verifier)
+	if err != nil {
+		// Return status 401 and exchange error.
+		return problem.Unauthorized(err.Error())
+	}
+	identity, err := provider.VerifyIDToken(tokens.IDToken, state.Nonce)
+	if err != nil {
+		// Return status 401 and ID token error.
+		return problem.Unauthorized(err.Error())
+	}
+
+	// Create database connection.
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		// Return status 500 and database connection error.
+		return problem.Internal(err)
+	}
+
+	// Roles are managed by provider, users without mapped roles are usual users.
+	roles := models.Roles(identity.Roles)
+	if len(roles) == 0 {
+		roles = models.Roles{repository.UserRoleName}
+	}
+
+	// Get user linked to account at provider, or create a new one.
+	user, err := db.GetUserByIdentity(identity.Issuer, identity.Subject)
+	if err == nil {
+		if err := db.UpdateUserRoles(user.ID, roles); err != nil {
+			// Return status 500 and database query error.
+			return problem.Internal(err)
+		}
+		user.Roles = roles
+	} else {
+		user = models.User{
+			ID:           uuid.New(),
+			CreatedAt:    time.Now(),
+			Username:     identity.PreferredUsername,
+			FirstName:    identity.GivenName,
+			LastName:     identity.FamilyName,
+			Roles:        roles,
+			PasswordHash: noPasswordHash,
+		}
+		if user.Username == "" {
+			user.Username = identity.Email
+		}
+		if user.Username == "" {
+			user.Username = identity.Subject
+		}
+
+		// Local accounts are never linked automatically, it would allow to take them over.
+		if _, err := db.GetUserByUsername(user.Username); err == nil {
+			// Return status 409 and conflict error.
+			return problem.Conflict("user with the given username already exists")
+		}
+
+		// Validate user fields.
+		if err := utils.NewValidator().Struct(&user); err != nil {
+			// Return, if some fields are not valid.
+			return problem.Validation(err)
+		}
+
+		// Create a new user linked to account at provider.
+		if err := db.CreateUserWithIdentity(&user, &models.UserIdentity{
+			ID:        uuid.New(),
+			CreatedAt: time.Now(),
+			UserID:    user.ID,
+			Issuer:    identity.Issuer,
+			Subject:   identity.Subject,
+		}); err != nil {
+			// Return status 500 and create user process error.
+			return problem.Internal(err)
+		}
+	}
+
+	// Generate a new pair of tokens for user, with a new refresh token family.
+	// Second factor is trusted, if provider reports it.
+	accessToken, refreshToken, err := issueTokens(db, &user, uuid.New(), identity.MultiFactor)
+	if err != nil {
+		// Return status 500 and token generation error.
+		return problem.Internal(err)
+	}
+
+	// Return status 200 OK.
+	return c.JSON(fiber.Map{
+		"error":         false,
+		"msg":           nil,
+		"access_token":  accessToken,
+		"refresh_token": refreshToken,
+	})
+}
@@ -0,0 +1,707 @@
+package controllers
+
+import (
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/koddr/tutorial-go-fiber-rest-api/app/models"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/problem"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/repository"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/utils"
+	"github.com/koddr/tutorial-go-fiber-rest-api/platform/database"
+)
+
+// GetOrganizations func gets all organizations of the current user with roles of user.
+// @Description Get all organizations of the current user.
+// @Summary get organizations of user
+// @Tags Organization
+// @Accept json
+// @Produce json
+// @Success 200 {array} models.Organization
+// @Security ApiKeyAuth
+// @Router /v1/organizations [get]
+func GetOrganizations(c *fiber.Ctx) error {
+	// Get principal of the current request.
+	principal, err := utils.GetPrincipal(c)
+	if err != nil {
+		// Return status 401 and unauthorized error message.
+		return problem.Unauthorized(err.Error())
+	}
+
+	// Create database connection.
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		// Return status 500 and database connection error.
+		return problem.Internal(err)
+	}
+
+	// Get all organizations of user.
+	organizations, err := db.GetOrganizationsByUser(principal.UserID)
+	if err != nil {
+		// Return status 500 and database query error.
+		return problem.Internal(err)
+	}
+
+	// Return status 200 OK.
+	return c.JSON(fiber.Map{
+		"error":         false,
+		"msg":           nil,
+		"count":         len(organizations),
+		"organizations": organizations,
+	})
+}
+
+// CreateOrganization func for creates a new organization, the current user is its owner.
+// @Description Create a new organization, the current user is its owner.
+// @Summary create a new organization
+// @Tags Organization
+// @Accept json
+// @Produce json
+// @Param name body string true "Name"
+// @Success 201 {object} models.Organization
+// @Security ApiKeyAuth
+// @Router /v1/organization [post]
+func CreateOrganization(c *fiber.Ctx) error {
+	// Get principal of the current request.
+	principal, err := utils.GetPrincipal(c)
+	if err != nil {
+		// Return status 401 and unauthorized error message.
+		return problem.Unauthorized(err.Error())
+	}
+
+	// Organizations are owned only by users.
+	if principal.UserID == uuid.Nil {
+		// Return status 403 and permission denied error.
+		return forbidden(c)
+	}
+
+	// Create a new organization struct.
+	organization := &models.Organization{}
+
+	// Checking received data from JSON body.
+	if err := c.BodyParser(organization); err != nil {
+		// Return status 400 and error message.
+		return problem.BadRequest(err.Error())
+	}
+
+	// Validate organization fields.
+	if err := utils.NewValidator().Struct(organization); err != nil {
+		// Return, if some fields are not valid.
+		return problem.Validation(err)
+	}
+
+	// Create database connection.
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		// Return status 500 and database connection error.
+		return problem.Internal(err)
+	}
+
+	// Set initialized default data for organization:
+	organization.ID = uuid.New()
+	organization.CreatedAt = time.Now()
+	organization.UpdatedAt = nil
+	organization.Role = repository.OrganizationOwnerRole
+
+	// Create a new organization with its owner.
+	if err := db.CreateOrganization(organization, principal.UserID); err != nil {
+		// Return status 500 and database query error.
+		return problem.Internal(err)
+	}
+
+	// Return status 201 created.
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"error":        false,
+		"msg":          nil,
+		"organization": organization,
+	})
+}
+
+// UpdateOrganization func for updates name of organization by given ID.
+// @Description Update name of organization, only for owners and admins of organization.
+// @Summary update organization
+// @Tags Organization
+// @Accept json
+// @Produce json
+// @Param id path string true "Organization ID"
+// @Param name body string true "Name"
+// @Success 204 {string} status "ok"
+// @Security ApiKeyAuth
+// @Router /v1/organization/{id} [put]
+func UpdateOrganization(c *fiber.Ctx) error {
+	// Get principal of the current request.
+	principal, err := utils.GetPrincipal(c)
+	if err != nil {
+		// Return status 401 and unauthorized error message.
+		return problem.Unauthorized(err.Error())
+	}
+
+	// Create a new organization struct.
+	organization := &models.Organization{}
+
+	// Checking received data from JSON body.
+	if err := c.BodyParser(organization); err != nil {
+		// Return status 400 and error message.
+		return problem.BadRequest(err.Error())
+	}
+
+	// Validate organization fields.
+	if err := utils.NewValidator().Struct(organization); err != nil {
+		// Return, if some fields are not valid.
+		return problem.Validation(err)
+	}
+
+	// Create database connection.
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		// Return status 500 and database connection error.
+		return problem.Internal(err)
+	}
+
+	// Get role of the current user in organization.
+	tenant, err := organizationTenant(c, db, principal)
+	if err != nil {
+		return organizationNotFound(c)
+	}
+	if !tenant.CanManage() {
+		// Return status 403 and permission denied error.
+		return forbidden(c)
+	}
+
+	// Update name of organization.
+	if err := db.UpdateOrganization(tenant.OrganizationID, organization.Name); err != nil {
+		// Return status 500 and database query error.
+		return problem.Internal(err)
+	}
+
+	// Return status 204 no content.
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// DeleteOrganization func for deletes organization with all its records by given ID.
+// @Description Delete organization with its members, books, servers and Info, only for owners of organization.
+// @Summary delete organization
+// @Tags Organization
+// @Accept json
+// @Produce json
+// @Param id path string true "Organization ID"
+// @Success 204 {string} status "ok"
+// @Security ApiKeyAuth
+// @Router /v1/organization/{id} [delete]
+func DeleteOrganization(c *fiber.Ctx) error {
+	// Get principal of the current request.
+	principal, err := utils.GetPrincipal(c)
+	if err != nil {
+		// Return status 401 and unauthorized error message.
+		return problem.Unauthorized(err.Error())
+	}
+
+	// Create database connection.
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		// Return status 500 and database connection error.
+		return problem.Internal(err)
+	}
+
+	// Get role of the current user in organization.
+	tenant, err := organizationTenant(c, db, principal)
+	if err != nil {
+		return organizationNotFound(c)
+	}
+	if !tenant.IsOwner() {
+		// Return status 403 and permission denied error.
+		return forbidden(c)
+	}
+
+	// Delete organization, its memberships and records.
+	if err := db.DeleteOrganization(tenant.OrganizationID); err != nil {
+		// Return status 500 and database query error.
+		return problem.Internal(err)
+	}
+
+	// Return status 204 no content.
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// GetMembers func gets all members of organization with their roles.
+// @Description Get all members of organization, only for its members.
+// @Summary get members of organization
+// @Tags Organization
+// @Accept json
+// @Produce json
+// @Param id path string true "Organization ID"
+// @Success 200 {array} models.Membership
+// @Security ApiKeyAuth
+// @Router /v1/organization/{id}/members [get]
+func GetMembers(c *fiber.Ctx) error {
+	// Get principal of the current request.
+	principal, err := utils.GetPrincipal(c)
+	if err != nil {
+		// Return status 401 and unauthorized error message.
+		return problem.Unauthorized(err.Error())
+	}
+
+	// Create database connection.
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		// Return status 500 and database connection error.
+		return problem.Internal(err)
+	}
+
+	// Get role of the current user in organization.
+	tenant, err := organizationTenant(c, db, principal)
+	if err != nil {
+		return organizationNotFound(c)
+	}
+
+	// Get all members of organization.
+	members, err := db.GetMembers(tenant.OrganizationID)
+	if err != nil {
+		// Return status 500 and database query error.
+		return problem.Internal(err)
+	}
+
+	// Return status 200 OK.
+	return c.JSON(fiber.Map{
+		"error":   false,
+		"msg":     nil,
+		"count":   len(members),
+		"members": members,
+	})
+}
+
+// UpdateMember func for changes role of member of organization.
+// @Description Change role of member. Owners and admins change roles, only owners grant and revoke the owner role.
+// @Summary change role of member
+// @Tags Organization
+// @Accept json
+// @Produce json
+// @Param id path string true "Organization ID"
+// @Param user_id body string true "User ID"
+// @Param role body string true "Role (owner, admin, member or viewer)"
+// @Success 204 {string} status "ok"
+// @Security ApiKeyAuth
+// @Router /v1/organization/{id}/member [put]
+func UpdateMember(c *fiber.Ctx) error {
+	// Get principal of the current request.
+	principal, err := utils.GetPrincipal(c)
+	if err != nil {
+		// Return status 401 and unauthorized error message.
+		return problem.Unauthorized(err.Error())
+	}
+
+	// Create a new request struct.
+	update := &models.UpdateMembership{}
+
+	// Checking received data from JSON body.
+	if err := c.BodyParser(update); err != nil {
+		// Return status 400 and error message.
+		return problem.BadRequest(err.Error())
+	}
+
+	// Validate request fields.
+	if err := utils.NewValidator().Struct(update); err != nil {
+		// Return, if some fields are not valid.
+		return problem.Validation(err)
+	}
+
+	// Create database connection.
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		// Return status 500 and database connection error.
+		return problem.Internal(err)
+	}
+
+	// Get role of the current user in organization.
+	tenant, err := organizationTenant(c, db, principal)
+	if err != nil {
+		return organizationNotFound(c)
+	}
+	if !tenant.CanManage() {
+		// Return status 403 and permission denied error.
+		return forbidden(c)
+	}
+
+	// Checking, if user is a member of organization.
+	member, err := db.GetMembership(tenant.OrganizationID, update.UserID)
+	if err != nil {
+		// Return status 404 and member not found error.
+		return problem.NotFound("member with the given user ID is not found")
+	}
+
+	// Only owners grant and revoke the owner role.
+	if (member.Role == repository.OrganizationOwnerRole || update.Role == repository.OrganizationOwnerRole) && !tenant.IsOwner() {
+		// Return status 403 and permission denied error.
+		return forbidden(c)
+	}
+
+	// Organization can't lose its last owner.
+	if member.Role == repository.OrganizationOwnerRole && update.Role != repository.OrganizationOwnerRole {
+		lastOwner, err := isLastOwner(db, tenant.OrganizationID)
+		if err != nil {
+			// Return status 500 and database query error.
+			return problem.Internal(err)
+		}
+		if lastOwner {
+			// Return status 409 and conflict error.
+			return problem.Conflict("organization must have at least one owner")
+		}
+	}
+
+	// Update role of member.
+	if err := db.UpdateMembership(tenant.OrganizationID, member.UserID, update.Role); err != nil {
+		// Return status 500 and database query error.
+		return problem.Internal(err)
+	}
+
+	// Return status 204 no content.
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// DeleteMember func for removes member from organization, members can leave by themselves.
+// @Description Remove member from organization. Owners and admins remove members, only owners remove owners.
+// @Summary remove member of organization
+// @Tags Organization
+// @Accept json
+// @Produce json
+// @Param id path string true "Organization ID"
+// @Param user_id body string true "User ID"
+// @Success 204 {string} status "ok"
+// @Security ApiKeyAuth
+// @Router /v1/organization/{id}/member [delete]
+func DeleteMember(c *fiber.Ctx) error {
+	// Get principal of the current request.
+	principal, err := utils.GetPrincipal(c)
+	if err != nil {
+		// Return status 401 and unauthorized error message.
+		return problem.Unauthorized(err.Error())
+	}
+
+	// Create a new request struct.
+	remove := &models.DeleteMembership{}
+
+	// Checking received data from JSON body.
+	if err := c.BodyParser(remove); err != nil {
+		// Return status 400 and error message.
+		return problem.BadRequest(err.Error())
+	}
+
+	// Validate request fields.
+	if err := utils.NewValidator().Struct(remove); err != nil {
+		// Return, if some fields are not valid.
+		return problem.Validation(err)
+	}
+
+	// Create database connection.
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		// Return status 500 and database connection error.
+		return problem.Internal(err)
+	}
+
+	// Get role of the current user in organization.
+	tenant, err := organizationTenant(c, db, principal)
+	if err != nil {
+		return organizationNotFound(c)
+	}
+
+	// Checking, if user is a member of organization.
+	member, err := db.GetMembership(tenant.OrganizationID, remove.UserID)
+	if err != nil {
+		// Return status 404 and member not found error.
+		return problem.NotFound("member with the given user ID is not found")
+	}
+
+	// Members leave by themselves, others are removed by owners and admins,
+	// and only owners remove owners.
+	if member.UserID != principal.UserID {
+		if !tenant.CanManage() || (member.Role == repository.OrganizationOwnerRole && !tenant.IsOwner()) {
+			// Return status 403 and permission denied error.
+			return forbidden(c)
+		}
+	}
+
+	// Organization can't lose its last owner.
+	if member.Role == repository.OrganizationOwnerRole {
+		lastOwner, err := isLastOwner(db, tenant.OrganizationID)
+		if err != nil {
+			// Return status 500 and database query error.
+			return problem.Internal(err)
+		}
+		if lastOwner {
+			// Return status 409 and conflict error.
+			return problem.Conflict("organization must have at least one owner")
+		}
+	}
+
+	// Remove member from organization.
+	if err := db.DeleteMembership(tenant.OrganizationID, member.UserID); err != nil {
+		// Return status 500 and database query error.
+		return problem.Internal(err)
+	}
+
+	// Return status 204 no content.
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// CreateInvitation func for invites user with the given email to organization by signed link.
+// @Description Create signed link of invitation and send it to email. Only user with this verified email can accept it.
+// @Summary invite to organization
+// @Tags Organization
+// @Accept json
+// @Produce json
+// @Param id path string true "Organization ID"
+// @Param email body string true "Email"
+// @Param role body string true "Role (owner, admin, member or viewer)"
+// @Success 201 {string} status "ok"
+// @Security ApiKeyAuth
+// @Router /v1/organization/{id}/invitation [post]
+func CreateInvitation(c *fiber.Ctx) error {
+	// Get principal of the current request.
+	principal, err := utils.GetPrincipal(c)
+	if err != nil {
+		// Return status 401 and unauthorized error message.
+		return problem.Unauthorized(err.Error())
+	}
+
+	// Create a new request struct.
+	invite := &models.CreateInvitation{}
+
+	// Checking received data from JSON body.
+	if err := c.BodyParser(invite); err != nil {
+		// Return status 400 and error message.
+		return problem.BadRequest(err.Error())
+	}
+
+	// Validate request fields.
+	if err := utils.NewValidator().Struct(invite); err != nil {
+		// Return, if some fields are not valid.
+		return problem.Validation(err)
+	}
+
+	// Create database connection.
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		// Return status 500 and database connection error.
+		return problem.Internal(err)
+	}
+
+	// Get role of the current user in organization.
+	tenant, err := organizationTenant(c, db, principal)
+	if err != nil {
+		return organizationNotFound(c)
+	}
+
+	// Owners and admins invite, only owners invite owners.
+	if !tenant.CanManage() || (invite.Role == repository.OrganizationOwnerRole && !tenant.IsOwner()) {
+		// Return status 403 and permission denied error.
+		return forbidden(c)
+	}
+
+	// Get organization and inviting user for text of email.
+	organization, err := db.GetOrganization(tenant.OrganizationID)
+	if err != nil {
+		return organizationNotFound(c)
+	}
+	invitedBy, err := db.GetUserByID(principal.UserID)
+	if err != nil {
+		// Return status 404 and user not found error.
+		return problem.NotFound("user with the given ID is not found")
+	}
+
+	// Get secret to sign invitation.
+	secret, err := utils.InvitationSecret()
+	if err != nil {
+		// Return status 500 and secret error.
+		return problem.Internal(err)
+	}
+
+	// Sign invitation, it's valid until expiration.
+	expires := time.Now().Add(invitationLifetime())
+	token, err := utils.GenerateNewInvitation(secret, &utils.Invitation{
+		OrganizationID: organization.ID,
+		Email:          strings.ToLower(invite.Email),
+		Role:           invite.Role,
+		InvitedBy:      invitedBy.ID,
+		Expires:        expires.Unix(),
+	})
+	if err != nil {
+		// Return status 500 and invitation signing error.
+		return problem.Internal(err)
+	}
+	link := os.Getenv("INVITATION_URL") + "?token=" + token
+
+	// Send link to email.
+	if err := sendMail(invite.Email, "organization_invitation", map[string]string{
+		"Organization": organization.Name,
+		"InvitedBy":    invitedBy.Username,
+		"Email":        invite.Email,
+		"Role":         invite.Role,
+		"Link":         link,
+		"ExpiresIn":    humanDuration(invitationLifetime()),
+	}); err != nil {
+		// Return status 500 and email error.
+		return problem.Internal(err)
+	}
+
+	// Return status 201 created, link is returned to share it by other ways too.
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"error": false,
+		"msg":   nil,
+		"invitation": fiber.Map{
+			"link":       link,
+			"email":      invite.Email,
+			"role":       invite.Role,
+			"expires_at": expires,
+		},
+	})
+}
+
+// AcceptInvitation func for joins the current user to organization by token from link of invitation.
+// @Description Accept invitation, the current user must have verified email, which the invitation was sent to.
+// @Summary accept invitation to organization
+// @Tags Organization
+// @Accept json
+// @Produce json
+// @Param token body string true "Token from link"
+// @Success 200 {object} models.Membership
+// @Security ApiKeyAuth
+// @Router /v1/invitation/accept [post]
+func AcceptInvitation(c *fiber.Ctx) error {
+	// Get principal of the current request.
+	principal, err := utils.GetPrincipal(c)
+	if err != nil {
+		// Return status 401 and unauthorized error message.
+		return problem.Unauthorized(err.Error())
+	}
+
+	// Create a new request struct.
+	accept := &models.AcceptInvitation{}
+
+	// Checking received data from JSON body.
+	if err := c.BodyParser(accept); err != nil {
+		// Return status 400 and error message.
+		return problem.BadRequest(err.Error())
+	}
+
+	// Validate request fields.
+	if err := utils.NewValidator().Struct(accept); err != nil {
+		// Return, if some fields are not valid.
+		return problem.Validation(err)
+	}
+
+	// Get secret to verify invitation.
+	secret, err := utils.InvitationSecret()
+	if err != nil {
+		// Return status 500 and secret error.
+		return problem.Internal(err)
+	}
+
+	// Verify signature and expiration of invitation.
+	invitation, err := utils.ParseInvitation(secret, accept.Token)
+	if err != nil {
+		// Return status 400 and invitation error.
+		return problem.BadRequest(err.Error())
+	}
+
+	// Create database connection.
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		// Return status 500 and database connection error.
+		return problem.Internal(err)
+	}
+
+	// Only owner of the verified email, which invitation was sent to, can accept it,
+	// so forwarded links are useless.
+	user, err := db.GetUserByID(principal.UserID)
+	if err != nil {
+		// Return status 404 and user not found error.
+		return problem.NotFound("user with the given ID is not found")
+	}
+	if user.Email == nil || user.EmailVerifiedAt == nil || *user.Email != invitation.Email {
+		// Return status 403 and permission denied error.
+		return problem.Forbidden("invitation is sent to another email, verify email of invitation first")
+	}
+
+	// Checking, if organization still exists.
+	if _, err := db.GetOrganization(invitation.OrganizationID); err != nil {
+		return organizationNotFound(c)
+	}
+
+	// Add user to organization, role of existing member is not changed.
+	membership := &models.Membership{
+		OrganizationID: invitation.OrganizationID,
+		UserID:         user.ID,
+		Role:           invitation.Role,
+		CreatedAt:      time.Now(),
+	}
+	created, err := db.CreateMembership(membership)
+	if err != nil {
+		// Return status 500 and database query error.
+		return problem.Internal(err)
+	}
+	if !created {
+		// Return status 409 and conflict error.
+		return problem.Conflict("user is already a member of organization")
+	}
+
+	// Return status 200 OK.
+	return c.JSON(fiber.Map{
+		"error":      false,
+		"msg":        nil,
+		"membership": membership,
+	})
+}
+
+// organizationTenant func for get role of user of principal in organization
+// by ID from URL. Admins of app have admin role in any organization.
+func organizationTenant(c *fiber.Ctx, db *database.Queries, principal *utils.Principal) (*utils.Tenant, error) {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return nil, err
+	}
+
+	if membership, err := db.GetMembership(id, principal.UserID); err == nil {
+		return &utils.Tenant{OrganizationID: id, Role: membership.Role}, nil
+	}
+
+	if principal.IsAdmin() {
+		if _, err := db.GetOrganization(id); err == nil {
+			return &utils.Tenant{OrganizationID: id, Role: repository.OrganizationAdminRole}, nil
+		}
+	}
+
+	return nil, errors.New("organization with the given ID is not found")
+}
+
+// organizationNotFound func for return status 404 with the same body for
+// unknown organizations and organizations of others.
+func organizationNotFound(c *fiber.Ctx) error {
+	return problem.NotFound("organization with the given ID is not found")
+}
+
+// isLastOwner func for checking, if organization has only one owner.
+func isLastOwner(db *database.Queries, organizationID uuid.UUID) (bool, error) {
+	owners, err := db.CountOwners(organizationID)
+	if err != nil {
+		return false, err
+	}
+
+	return owners <= 1, nil
+}
+
+// invitationLifetime func for get lifetime of invitation from .env file
+// (INVITATION_EXPIRE_HOURS, default 168).
+func invitationLifetime() time.Duration {
+	hours, err := strconv.Atoi(os.Getenv("INVITATION_EXPIRE_HOURS"))
+	if err != nil || hours <= 0 {
+		hours = 168
+	}
+
+	return time.Duration(hours) * time.Hour
+}
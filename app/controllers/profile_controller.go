@@ -0,0 +1,318 @@
+package controllers
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/koddr/tutorial-go-fiber-rest-api/app/models"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/normalize"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/problem"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/utils"
+	"github.com/koddr/tutorial-go-fiber-rest-api/platform/database"
+)
+
+// GetProfiles func gets all normalization profiles of user.
+// @Description Get all normalization profiles of user.
+// @Summary get all normalization profiles of user
+// @Tags Profiles
+// @Accept json
+// @Produce json
+// @Param user_id query string false "User ID"
+// @Param mine query bool false "Only profiles of the current user"
+// @Success 200 {array} models.Profile
+// @Router /v1/profiles [get]
+func GetProfiles(c *fiber.Ctx) error {
+	// Get owner of profiles, the current user by "?mine=true" or user ID from URL.
+	userID, err := requestedOwner(c)
+	if err != nil {
+		// Return status 401 and unauthorized error message.
+		return problem.Unauthorized(err.Error())
+	}
+	if userID == uuid.Nil {
+		if userID, err = uuid.Parse(c.Query("user_id")); err != nil {
+			return problem.BadRequest(err.Error())
+		}
+	}
+
+	// Create database connection.
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		// Return status 500 and database connection error.
+		return problem.Internal(err)
+	}
+
+	// Get all profiles of user.
+	profiles, err := db.GetProfilesByUser(userID)
+	if err != nil {
+		// Return status 500 and database query error.
+		return problem.Internal(err)
+	}
+
+	// Return status 200 OK.
+	return c.JSON(fiber.Map{
+		"error":    false,
+		"msg":      nil,
+		"count":    len(profiles),
+		"profiles": profiles,
+	})
+}
+
+// GetProfile func gets normalization profile by given ID or 404 error.
+// @Description Get normalization profile by given ID.
+// @Summary get normalization profile by given ID
+// @Tags Profile
+// @Accept json
+// @Produce json
+// @Param id path string true "Profile ID"
+// @Success 200 {object} models.Profile
+// @Router /v1/profile/{id} [get]
+func GetProfile(c *fiber.Ctx) error {
+	// Catch profile ID from URL.
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		// Return status 400 and error message.
+		return problem.BadRequest(err.Error())
+	}
+
+	// Create database connection.
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		// Return status 500 and database connection error.
+		return problem.Internal(err)
+	}
+
+	// Callers, who can read only own profiles by grants, get only their profiles.
+	owner, err := requestedOwner(c)
+	if err != nil {
+		// Return status 401 and unauthorized error message.
+		return problem.Unauthorized(err.Error())
+	}
+
+	// Get profile by ID.
+	profile, err := db.GetProfile(id)
+	if err != nil || (owner != uuid.Nil && profile.UserID != owner) {
+		// Return, if profile not found.
+		return problem.NotFound("profile with the given ID is not found")
+	}
+
+	// Return status 200 OK.
+	return c.JSON(fiber.Map{
+		"error":   false,
+		"msg":     nil,
+		"profile": profile,
+	})
+}
+
+// CreateProfile func for creates a new normalization profile.
+// @Description Create a new normalization profile.
+// @Summary create a new normalization profile
+// @Tags Profile
+// @Accept json
+// @Produce json
+// @Param name body string true "Name"
+// @Param profile_options body normalize.Options true "Normalization options"
+// @Success 200 {object} models.Profile
+// @Security ApiKeyAuth
+// @Router /v1/profile [post]
+func CreateProfile(c *fiber.Ctx) error {
+	// Get principal of the current request.
+	principal, err := utils.GetPrincipal(c)
+	if err != nil {
+		// Return status 401 and unauthorized error message.
+		return problem.Unauthorized(err.Error())
+	}
+
+	// Create new Profile struct
+	profile := &models.Profile{}
+
+	// Check, if received JSON data is valid.
+	if err := c.BodyParser(profile); err != nil {
+		// Return status 400 and error message.
+		return problem.BadRequest(err.Error())
+	}
+
+	// Create a new validator for a Profile model.
+	validate := utils.NewValidator()
+
+	// Set initialized default data for profile:
+	profile.ID = uuid.New()
+	profile.UserID = principal.UserID
+	profile.CreatedAt = time.Now()
+
+	// Validate profile fields.
+	if err := validate.Struct(profile); err != nil {
+		// Return, if some fields are not valid.
+		return problem.Validation(err)
+	}
+
+	// Checking, if normalization options can be compiled.
+	if _, err := normalize.New(profile.ProfileOptions.Options); err != nil {
+		// Return status 400 and options error.
+		return problem.BadRequest(err.Error())
+	}
+
+	// Create database connection.
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		// Return status 500 and database connection error.
+		return problem.Internal(err)
+	}
+
+	// Create a new profile.
+	if err := db.CreateProfile(profile); err != nil {
+		// Return status 500 and error message.
+		return problem.Internal(err)
+	}
+
+	// Return status 200 OK.
+	return c.JSON(fiber.Map{
+		"error":   false,
+		"msg":     nil,
+		"profile": profile,
+	})
+}
+
+// UpdateProfile func for updates normalization profile by given ID.
+// @Description Update normalization profile.
+// @Summary update normalization profile
+// @Tags Profile
+// @Accept json
+// @Produce json
+// @Param id body string true "Profile ID"
+// @Param name body string true "Name"
+// @Param profile_options body normalize.Options true "Normalization options"
+// @Success 201 {string} status "ok"
+// @Security ApiKeyAuth
+// @Router /v1/profile [put]
+func UpdateProfile(c *fiber.Ctx) error {
+	// Get principal of the current request.
+	principal, err := utils.GetPrincipal(c)
+	if err != nil {
+		// Return status 401 and unauthorized error message.
+		return problem.Unauthorized(err.Error())
+	}
+
+	// Create new Profile struct
+	profile := &models.Profile{}
+
+	// Check, if received JSON data is valid.
+	if err := c.BodyParser(profile); err != nil {
+		// Return status 400 and error message.
+		return problem.BadRequest(err.Error())
+	}
+
+	// Create database connection.
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		// Return status 500 and database connection error.
+		return problem.Internal(err)
+	}
+
+	// Checking, if profile with given ID is exists.
+	foundedProfile, err := db.GetProfile(profile.ID)
+	if err != nil {
+		// Return status 404 and profile not found error.
+		return problem.NotFound("profile with this ID not found")
+	}
+
+	// Checking, if profile belongs to the current user.
+	if !canModify(c, principal, foundedProfile.UserID) {
+		// Return status 403 and permission denied error.
+		return forbidden(c)
+	}
+
+	// Set initialized default data for profile:
+	profile.UserID = foundedProfile.UserID
+	profile.UpdatedAt = time.Now()
+
+	// Create a new validator for a Profile model.
+	validate := utils.NewValidator()
+
+	// Validate profile fields.
+	if err := validate.Struct(profile); err != nil {
+		// Return, if some fields are not valid.
+		return problem.Validation(err)
+	}
+
+	// Checking, if normalization options can be compiled.
+	if _, err := normalize.New(profile.ProfileOptions.Options); err != nil {
+		// Return status 400 and options error.
+		return problem.BadRequest(err.Error())
+	}
+
+	// Update profile by given ID.
+	if err := db.UpdateProfile(foundedProfile.ID, profile); err != nil {
+		// Return status 500 and error message.
+		return problem.Internal(err)
+	}
+
+	// Return status 201.
+	return c.SendStatus(fiber.StatusCreated)
+}
+
+// DeleteProfile func for deletes normalization profile by given ID.
+// @Description Delete normalization profile by given ID.
+// @Summary delete normalization profile by given ID
+// @Tags Profile
+// @Accept json
+// @Produce json
+// @Param id body string true "Profile ID"
+// @Success 204 {string} status "ok"
+// @Security ApiKeyAuth
+// @Router /v1/profile [delete]
+func DeleteProfile(c *fiber.Ctx) error {
+	// Get principal of the current request.
+	principal, err := utils.GetPrincipal(c)
+	if err != nil {
+		// Return status 401 and unauthorized error message.
+		return problem.Unauthorized(err.Error())
+	}
+
+	// Create new Profile struct
+	profile := &models.Profile{}
+
+	// Check, if received JSON data is valid.
+	if err := c.BodyParser(profile); err != nil {
+		// Return status 400 and error message.
+		return problem.BadRequest(err.Error())
+	}
+
+	// Create a new validator for a Profile model.
+	validate := utils.NewValidator()
+
+	// Validate only one profile field ID.
+	if err := validate.StructPartial(profile, "id"); err != nil {
+		// Return, if some fields are not valid.
+		return problem.Validation(err)
+	}
+
+	// Create database connection.
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		// Return status 500 and database connection error.
+		return problem.Internal(err)
+	}
+
+	// Checking, if profile with given ID is exists.
+	foundedProfile, err := db.GetProfile(profile.ID)
+	if err != nil {
+		// Return status 404 and profile not found error.
+		return problem.NotFound("profile with this ID not found")
+	}
+
+	// Checking, if profile belongs to the current user.
+	if !canModify(c, principal, foundedProfile.UserID) {
+		// Return status 403 and permission denied error.
+		return forbidden(c)
+	}
+
+	// Delete profile by given ID.
+	if err := db.DeleteProfile(foundedProfile.ID); err != nil {
+		// Return status 500 and error message.
+		return problem.Internal(err)
+	}
+
+	// Return status 204 no content.
+	return c.SendStatus(fiber.StatusNoContent)
+}
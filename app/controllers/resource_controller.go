@@ -0,0 +1,511 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/koddr/tutorial-go-fiber-rest-api/app/models"
+	"github.com/koddr/tutorial-go-fiber-rest-api/app/queries"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/acl"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/jsonpatch"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/problem"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/utils"
+	"github.com/koddr/tutorial-go-fiber-rest-api/platform/database"
+)
+
+// HeaderAcceptPatch is header of media types of patches, which PATCH routes accept.
+const HeaderAcceptPatch = "Accept-Patch"
+
+// RecordPointer interface to describe pointer to model of records of
+// organization, which embeds models.Record, like *models.Book.
+type RecordPointer[T any] interface {
+	*T
+	Base() *models.Record
+}
+
+// Resource struct to describe REST API of records of organization, like books.
+// Handlers of resource check organization of request, grants and owner of
+// record the same way for all models; hooks add rules of model. Routes of
+// resource are registered by routes package.
+type Resource[T any, P RecordPointer[T]] struct {
+	Name       string // name of record in messages and responses, like "book"
+	PluralName string // name of records in responses, like "books"
+	Path       string // path of one record, like "/book"
+	PluralPath string // path of all records, like "/books"
+	ACL        string // resource of grants, like "books"
+
+	// Repository returns queries of records for the given database connection.
+	Repository func(db *database.Queries) queries.Repository[T]
+
+	// Defaults sets default fields of a new record, it's optional.
+	Defaults func(record P)
+	// Validate checks record after validation tags of model, it's optional.
+	Validate func(record P) error
+	// Authorize checks, if principal can change or delete record, after role
+	// in organization and grants, it's optional.
+	Authorize func(c *fiber.Ctx, principal *utils.Principal, record P) bool
+}
+
+// List method gets all records of organization.
+func (r *Resource[T, P]) List(c *fiber.Ctx) error {
+	// Get organization of the current request.
+	tenant, err := utils.GetTenant(c)
+	if err != nil {
+		// Return status 403 and permission denied error.
+		return forbidden(c)
+	}
+
+	// Create database connection with row-level security of user.
+	db, err := database.OpenTenantDBConnection(tenant.UserID)
+	if err != nil {
+		// Return status 500 and database connection error.
+		return problem.Internal(err)
+	}
+
+	// Get owner of records, if only records of the current user are requested.
+	owner, err := requestedOwner(c)
+	if err != nil {
+		// Return status 401 and unauthorized error message.
+		return problem.Unauthorized(err.Error())
+	}
+
+	// Get all records, or only records of the owner.
+	records, err := r.Repository(db).List(tenant.OrganizationID, owner)
+	if err != nil {
+		// Return status 500 and database query error.
+		return problem.Internal(err)
+	}
+
+	// Return status 200 OK.
+	return c.JSON(fiber.Map{
+		"error":      false,
+		"msg":        nil,
+		"count":      len(records),
+		r.PluralName: records,
+	})
+}
+
+// Get method gets record by given ID or 404 error.
+func (r *Resource[T, P]) Get(c *fiber.Ctx) error {
+	// Catch record ID from URL.
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		// Return status 400 and error message.
+		return problem.BadRequest(err.Error())
+	}
+
+	// Get organization of the current request.
+	tenant, err := utils.GetTenant(c)
+	if err != nil {
+		// Return status 403 and permission denied error.
+		return forbidden(c)
+	}
+
+	// Create database connection with row-level security of user.
+	db, err := database.OpenTenantDBConnection(tenant.UserID)
+	if err != nil {
+		// Return status 500 and database connection error.
+		return problem.Internal(err)
+	}
+
+	// Callers, who can read only own records by grants, get only their records.
+	owner, err := requestedOwner(c)
+	if err != nil {
+		// Return status 401 and unauthorized error message.
+		return problem.Unauthorized(err.Error())
+	}
+
+	// Get record by ID.
+	record, err := r.Repository(db).Get(tenant.OrganizationID, id)
+	if err != nil || (owner != uuid.Nil && P(&record).Base().UserID != owner) {
+		// Return, if record not found.
+		return problem.NotFound(r.Name + " with the given ID is not found")
+	}
+
+	// Return status 200 OK.
+	return c.JSON(fiber.Map{
+		"error": false,
+		"msg":   nil,
+		r.Name:  record,
+	})
+}
+
+// Create method creates a new record of the current user in organization.
+func (r *Resource[T, P]) Create(c *fiber.Ctx) error {
+	// Get principal of the current request.
+	principal, err := utils.GetPrincipal(c)
+	if err != nil {
+		// Return status 401 and unauthorized error message.
+		return problem.Unauthorized(err.Error())
+	}
+
+	// Check, if received JSON data is valid.
+	record := P(new(T))
+	if err := c.BodyParser(record); err != nil {
+		// Return status 400 and error message.
+		return problem.BadRequest(err.Error())
+	}
+
+	// Get organization of the current request.
+	tenant, err := utils.GetTenant(c)
+	if err != nil {
+		// Return status 403 and permission denied error.
+		return forbidden(c)
+	}
+
+	// Checking, if the current user can create records of organization.
+	if !tenant.CanWrite() {
+		// Return status 403 and permission denied error.
+		return forbidden(c)
+	}
+
+	// Create database connection with row-level security of user.
+	db, err := database.OpenTenantDBConnection(tenant.UserID)
+	if err != nil {
+		// Return status 500 and database connection error.
+		return problem.Internal(err)
+	}
+
+	// Set initialized default data for record and validate it.
+	r.initRecord(record, principal.UserID, tenant.OrganizationID)
+	if err := r.validate(record); err != nil {
+		// Return status 400 and invalid fields.
+		return err
+	}
+
+	// Create a new record.
+	if err := r.Repository(db).Create(record); err != nil {
+		// Return status 500 and error message.
+		return problem.Internal(err)
+	}
+
+	// Return status 200 OK.
+	return c.JSON(fiber.Map{
+		"error": false,
+		"msg":   nil,
+		r.Name:  record,
+	})
+}
+
+// Update method replaces all fields of record by given ID in JSON body.
+func (r *Resource[T, P]) Update(c *fiber.Ctx) error {
+	// Get principal of the current request.
+	principal, err := utils.GetPrincipal(c)
+	if err != nil {
+		// Return status 401 and unauthorized error message.
+		return problem.Unauthorized(err.Error())
+	}
+
+	// Check, if received JSON data is valid.
+	record := P(new(T))
+	if err := c.BodyParser(record); err != nil {
+		// Return status 400 and error message.
+		return problem.BadRequest(err.Error())
+	}
+
+	// Get organization of the current request.
+	tenant, err := utils.GetTenant(c)
+	if err != nil {
+		// Return status 403 and permission denied error.
+		return forbidden(c)
+	}
+
+	// Create database connection with row-level security of user.
+	db, err := database.OpenTenantDBConnection(tenant.UserID)
+	if err != nil {
+		// Return status 500 and database connection error.
+		return problem.Internal(err)
+	}
+
+	// Checking, if record with given ID is exists and can be changed.
+	repository := r.Repository(db)
+	found, err := repository.Get(tenant.OrganizationID, record.Base().ID)
+	if err != nil {
+		// Return status 404 and record not found error.
+		return problem.NotFound(r.Name + " with this ID not found")
+	}
+	if !r.canModify(c, principal, &found) {
+		// Return status 403 and permission denied error.
+		return forbidden(c)
+	}
+
+	// Keep fields, which are not changed by request, and validate record.
+	r.keepRecord(record, &found)
+	if err := r.validate(record); err != nil {
+		// Return status 400 and invalid fields.
+		return err
+	}
+
+	// Update record by given ID.
+	if err := repository.Update(record.Base().ID, record); err != nil {
+		// Return status 500 and error message.
+		return problem.Internal(err)
+	}
+
+	// Return status 201.
+	return c.SendStatus(fiber.StatusCreated)
+}
+
+// Patch method changes record by ID from URL with JSON Merge Patch (RFC 7396)
+// or JSON Patch (RFC 6902) by media type of body, other fields of record are
+// kept. Patch is applied to the found record as a whole: record is updated,
+// if all operations are applied and the patched record is valid, or not at all.
+// Record is locked in transaction of update, so concurrent patches are applied
+// one after another and don't lose changes of each other.
+func (r *Resource[T, P]) Patch(c *fiber.Ctx) error {
+	// Catch record ID from URL.
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		// Return status 400 and error message.
+		return problem.BadRequest(err.Error())
+	}
+
+	// Get function of patch by media type of body.
+	applyPatch, err := patchFunc(c)
+	if err != nil {
+		// Return status 415 and supported media types.
+		return err
+	}
+
+	// Get principal of the current request.
+	principal, err := utils.GetPrincipal(c)
+	if err != nil {
+		// Return status 401 and unauthorized error message.
+		return problem.Unauthorized(err.Error())
+	}
+
+	// Get organization of the current request.
+	tenant, err := utils.GetTenant(c)
+	if err != nil {
+		// Return status 403 and permission denied error.
+		return forbidden(c)
+	}
+
+	// Patch record in transaction with row-level security of user.
+	var record P
+	err = database.InTenantTransaction(tenant.UserID, func(db *database.Queries) error {
+		record, err = r.patchRecord(c, principal, r.Repository(db), tenant, id, applyPatch)
+		return err
+	})
+	if err != nil {
+		// Return problem of patch, or status 500 and error message.
+		return err
+	}
+
+	// Return status 200 OK.
+	return c.JSON(fiber.Map{
+		"error": false,
+		"msg":   nil,
+		r.Name:  record,
+	})
+}
+
+// patchRecord method for apply patch to record by given ID, which is locked
+// until the end of transaction of repository, and update it.
+func (r *Resource[T, P]) patchRecord(c *fiber.Ctx, principal *utils.Principal, repository queries.Repository[T], tenant *utils.Tenant, id uuid.UUID, applyPatch func(document, patch []byte) ([]byte, error)) (P, error) {
+	// Checking, if record with given ID is exists and can be changed.
+	found, err := repository.GetForUpdate(tenant.OrganizationID, id)
+	if err != nil {
+		// Return status 404 and record not found error.
+		return nil, problem.NotFound(r.Name + " with this ID not found")
+	}
+	if !r.canModify(c, principal, &found) {
+		// Return status 403 and permission denied error.
+		return nil, forbidden(c)
+	}
+
+	// Apply patch to JSON document of found record.
+	document, err := json.Marshal(&found)
+	if err != nil {
+		// Return status 500 and error message.
+		return nil, problem.Internal(err)
+	}
+	patched, err := applyPatch(document, c.Body())
+	if err != nil {
+		// Return status 400 for malformed patch, or 409 for patch, which can't be applied.
+		return nil, patchError(err)
+	}
+
+	// Decode patched record.
+	record := P(new(T))
+	if err := json.Unmarshal(patched, record); err != nil {
+		// Return status 422 and error message.
+		return nil, problem.Unprocessable(err.Error())
+	}
+
+	// Checking, if patch changes only allowed attributes. Body of JSON Patch
+	// isn't an object, so grants are checked on changes of record.
+	if permission, ok := acl.GetPermission(c); ok && !permission.AllowsAll() {
+		changes, err := patchChanges(document, patched)
+		if err != nil {
+			// Return status 500 and error message.
+			return nil, problem.Internal(err)
+		}
+		if len(permission.InvalidAttributes(changes)) > 0 {
+			// Return status 403 and permission denied error.
+			return nil, forbidden(c)
+		}
+	}
+
+	// Keep fields, which are not changed by request, and validate record.
+	r.keepRecord(record, &found)
+	if err := r.validate(record); err != nil {
+		// Return status 400 and invalid fields.
+		return nil, err
+	}
+
+	// Update record by given ID.
+	if err := repository.Update(id, record); err != nil {
+		// Return status 500 and error message.
+		return nil, problem.Internal(err)
+	}
+
+	return record, nil
+}
+
+// Delete method deletes record by given ID in JSON body.
+func (r *Resource[T, P]) Delete(c *fiber.Ctx) error {
+	// Get principal of the current request.
+	principal, err := utils.GetPrincipal(c)
+	if err != nil {
+		// Return status 401 and unauthorized error message.
+		return problem.Unauthorized(err.Error())
+	}
+
+	// Check, if received JSON data is valid.
+	record := P(new(T))
+	if err := c.BodyParser(record); err != nil {
+		// Return status 400 and error message.
+		return problem.BadRequest(err.Error())
+	}
+
+	// Get organization of the current request.
+	tenant, err := utils.GetTenant(c)
+	if err != nil {
+		// Return status 403 and permission denied error.
+		return forbidden(c)
+	}
+
+	// Create database connection with row-level security of user.
+	db, err := database.OpenTenantDBConnection(tenant.UserID)
+	if err != nil {
+		// Return status 500 and database connection error.
+		return problem.Internal(err)
+	}
+
+	// Checking, if record with given ID is exists and can be deleted.
+	repository := r.Repository(db)
+	found, err := repository.Get(tenant.OrganizationID, record.Base().ID)
+	if err != nil {
+		// Return status 404 and record not found error.
+		return problem.NotFound(r.Name + " with this ID not found")
+	}
+	if !r.canModify(c, principal, &found) {
+		// Return status 403 and permission denied error.
+		return forbidden(c)
+	}
+
+	// Delete record by given ID.
+	if err := repository.Delete(tenant.OrganizationID, record.Base().ID); err != nil {
+		// Return status 500 and error message.
+		return problem.Internal(err)
+	}
+
+	// Return status 204 no content.
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// initRecord method for set fields of a new record of the given user in organization.
+func (r *Resource[T, P]) initRecord(record P, userID, orgID uuid.UUID) {
+	base := record.Base()
+	base.ID = uuid.New()
+	base.CreatedAt = time.Now()
+	base.UserID = userID
+	base.OrgID = orgID
+
+	if r.Defaults != nil {
+		r.Defaults(record)
+	}
+}
+
+// keepRecord method for set fields of changed record, which are not changed by
+// request, from the found record.
+func (r *Resource[T, P]) keepRecord(record, found P) {
+	base, foundBase := record.Base(), found.Base()
+	base.ID = foundBase.ID
+	base.CreatedAt = foundBase.CreatedAt
+	base.UpdatedAt = time.Now()
+	base.UserID = foundBase.UserID
+	base.OrgID = foundBase.OrgID
+}
+
+// patchFunc func for get function, which applies body of PATCH request to JSON
+// document, by media type of body. Plain JSON isn't accepted, because it's
+// not clear, if it's merge patch or the whole record.
+func patchFunc(c *fiber.Ctx) (func(document, patch []byte) ([]byte, error), error) {
+	mediaType := strings.ToLower(strings.TrimSpace(strings.Split(c.Get(fiber.HeaderContentType), ";")[0]))
+	switch mediaType {
+	case jsonpatch.MergePatchType:
+		return jsonpatch.MergePatch, nil
+	case jsonpatch.PatchType:
+		return jsonpatch.Apply, nil
+	}
+
+	// Tell client media types of patches (RFC 5789).
+	c.Set(HeaderAcceptPatch, jsonpatch.MergePatchType+", "+jsonpatch.PatchType)
+	return nil, problem.UnsupportedMediaType("media type of patch must be " + jsonpatch.MergePatchType + " or " + jsonpatch.PatchType)
+}
+
+// patchError func for get problem of patch, which can't be applied.
+func patchError(err error) error {
+	if errors.Is(err, jsonpatch.ErrConflict) {
+		return problem.Conflict(err.Error())
+	}
+	if errors.Is(err, jsonpatch.ErrMalformed) {
+		return problem.BadRequest(err.Error())
+	}
+
+	return problem.Internal(err)
+}
+
+// patchChanges func for get changed attributes of patched document as JSON
+// Merge Patch, removed attributes are null.
+func patchChanges(document, patched []byte) (map[string]interface{}, error) {
+	diff, err := jsonpatch.CreateMergePatch(document, patched)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := map[string]interface{}{}
+	return changes, json.Unmarshal(diff, &changes)
+}
+
+// canModify method for checking, if principal can change or delete record by
+// role in organization, grants and Authorize hook.
+func (r *Resource[T, P]) canModify(c *fiber.Ctx, principal *utils.Principal, record P) bool {
+	if !canModify(c, principal, record.Base().UserID) {
+		return false
+	}
+
+	return r.Authorize == nil || r.Authorize(c, principal, record)
+}
+
+// validate method for check record by validation tags of model and Validate
+// hook, it returns problem with invalid fields or nil, if record is valid.
+func (r *Resource[T, P]) validate(record P) error {
+	if err := utils.NewValidator().Struct(record); err != nil {
+		return problem.Validation(err)
+	}
+
+	if r.Validate != nil {
+		if err := r.Validate(record); err != nil {
+			return problem.Validation(err)
+		}
+	}
+
+	return nil
+}
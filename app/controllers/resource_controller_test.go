@@ -0,0 +1,43 @@
+package controllers
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/configs"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/jsonpatch"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPatchMediaType(t *testing.T) {
+	app := fiber.New(configs.FiberConfig())
+	app.Patch("/book/:id", Books.Patch)
+
+	tests := []struct {
+		description  string
+		contentType  string
+		expectedCode int
+	}{
+		{"JSON Merge Patch", jsonpatch.MergePatchType, 401},
+		{"JSON Patch with charset", jsonpatch.PatchType + "; charset=utf-8", 401},
+		{"plain JSON", fiber.MIMEApplicationJSON, 415},
+		{"no media type", "", 415},
+	}
+
+	for _, test := range tests {
+		req := httptest.NewRequest("PATCH", "/book/00000000-0000-0000-0000-000000000000", strings.NewReader(`{"title": "New title"}`))
+		req.Header.Set("Content-Type", test.contentType)
+
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err, test.description)
+
+		// Media type is checked first, so patches without principal are unauthorized.
+		assert.Equalf(t, test.expectedCode, resp.StatusCode, test.description)
+		if test.expectedCode == 415 {
+			assert.Equalf(t, jsonpatch.MergePatchType+", "+jsonpatch.PatchType, resp.Header.Get(HeaderAcceptPatch), test.description)
+		}
+	}
+}
@@ -0,0 +1,245 @@
+package controllers
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/koddr/tutorial-go-fiber-rest-api/app/models"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/lockout"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/problem"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/utils"
+	"github.com/koddr/tutorial-go-fiber-rest-api/platform/database"
+)
+
+// GetSecurityEvents func gets the latest security events (admin only).
+// @Description Get the latest security events, like sign in successes and failures (admin only).
+// @Summary get the latest security events
+// @Tags Security
+// @Accept json
+// @Produce json
+// @Param username query string false "Only events of username"
+// @Param limit query integer false "Count of events (default 100)"
+// @Param offset query integer false "Count of events to skip"
+// @Success 200 {array} models.SecurityEvent
+// @Security ApiKeyAuth
+// @Router /v1/admin/security/events [get]
+func GetSecurityEvents(c *fiber.Ctx) error {
+	// Get principal of the current request.
+	principal, err := utils.GetPrincipal(c)
+	if err != nil {
+		// Return status 401 and unauthorized error message.
+		return problem.Unauthorized(err.Error())
+	}
+
+	// Checking, if principal is admin.
+	if !principal.IsAdmin() {
+		// Return status 403 and permission denied error.
+		return forbidden(c)
+	}
+
+	// Get page of events from URL.
+	limit, err := strconv.Atoi(c.Query("limit", "100"))
+	if err != nil || limit <= 0 || limit > 1000 {
+		limit = 100
+	}
+	offset, err := strconv.Atoi(c.Query("offset", "0"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	// Create database connection.
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		// Return status 500 and database connection error.
+		return problem.Internal(err)
+	}
+
+	// Get security events.
+	events, err := db.GetSecurityEvents(c.Query("username"), limit, offset)
+	if err != nil {
+		// Return status 500 and database query error.
+		return problem.Internal(err)
+	}
+
+	// Return status 200 OK.
+	return c.JSON(fiber.Map{
+		"error":  false,
+		"msg":    nil,
+		"count":  len(events),
+		"events": events,
+	})
+}
+
+// GetLockedLogins func gets all usernames and IPs, which are locked now (admin only).
+// @Description Get all usernames and IPs with sign in locked after failed attempts (admin only).
+// @Summary get locked usernames and IPs
+// @Tags Security
+// @Accept json
+// @Produce json
+// @Success 200 {array} models.LoginAttempt
+// @Security ApiKeyAuth
+// @Router /v1/admin/security/lockouts [get]
+func GetLockedLogins(c *fiber.Ctx) error {
+	// Get principal of the current request.
+	principal, err := utils.GetPrincipal(c)
+	if err != nil {
+		// Return status 401 and unauthorized error message.
+		return problem.Unauthorized(err.Error())
+	}
+
+	// Checking, if principal is admin.
+	if !principal.IsAdmin() {
+		// Return status 403 and permission denied error.
+		return forbidden(c)
+	}
+
+	// Create database connection.
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		// Return status 500 and database connection error.
+		return problem.Internal(err)
+	}
+
+	// Get locked usernames and IPs.
+	lockouts, err := db.GetLockedLoginAttempts()
+	if err != nil {
+		// Return status 500 and database query error.
+		return problem.Internal(err)
+	}
+
+	// Return status 200 OK.
+	return c.JSON(fiber.Map{
+		"error":    false,
+		"msg":      nil,
+		"count":    len(lockouts),
+		"lockouts": lockouts,
+	})
+}
+
+// UnlockUser func for unlock sign in of username after failed attempts (admin only).
+// @Description Unlock sign in of username after failed attempts (admin only).
+// @Summary unlock sign in of username
+// @Tags Security
+// @Accept json
+// @Produce json
+// @Param username body string true "Username"
+// @Success 204 {string} status "ok"
+// @Security ApiKeyAuth
+// @Router /v1/admin/security/unlock [post]
+func UnlockUser(c *fiber.Ctx) error {
+	// Get principal of the current request.
+	principal, err := utils.GetPrincipal(c)
+	if err != nil {
+		// Return status 401 and unauthorized error message.
+		return problem.Unauthorized(err.Error())
+	}
+
+	// Checking, if principal is admin.
+	if !principal.IsAdmin() {
+		// Return status 403 and permission denied error.
+		return forbidden(c)
+	}
+
+	// Create a new unlock struct.
+	unlock := &models.Unlock{}
+
+	// Checking received data from JSON body.
+	if err := c.BodyParser(unlock); err != nil {
+		// Return status 400 and error message.
+		return problem.BadRequest(err.Error())
+	}
+
+	// Validate unlock fields.
+	if err := utils.NewValidator().Struct(unlock); err != nil {
+		// Return, if some fields are not valid.
+		return problem.Validation(err)
+	}
+
+	// Create database connection.
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		// Return status 500 and database connection error.
+		return problem.Internal(err)
+	}
+
+	// Forget failed attempts of username.
+	if err := db.ResetLoginAttempts(userLoginKey(unlock.Username)); err != nil {
+		// Return status 500 and database query error.
+		return problem.Internal(err)
+	}
+
+	recordSecurityEvent(db, c, models.AccountUnlockedEvent, nil, unlock.Username)
+
+	// Return status 204 no content.
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func userLoginKey(username string) string {
+	return "user:" + username
+}
+
+func ipLoginKey(ip string) string {
+	return "ip:" + ip
+}
+
+// loginLockedFor func for get time, until sign in of username or IP is locked.
+func loginLockedFor(db *database.Queries, username, ip string) time.Duration {
+	wait := time.Duration(0)
+	for _, key := range []string{userLoginKey(username), ipLoginKey(ip)} {
+		attempt, err := db.GetLoginAttempt(key)
+		if err == nil && attempt.LockedUntil != nil {
+			if left := time.Until(*attempt.LockedUntil); left > wait {
+				wait = left
+			}
+		}
+	}
+
+	return wait
+}
+
+// recordLoginFailure func for count failed sign in of username and IP,
+// and lock them with exponential backoff by their policies.
+func recordLoginFailure(db *database.Queries, username, ip string) error {
+	now := time.Now()
+	for key, policy := range map[string]lockout.Policy{
+		userLoginKey(username): lockout.UserPolicy(),
+		ipLoginKey(ip):         lockout.IPPolicy(),
+	} {
+		failures, err := db.RecordLoginFailure(key, now, now.Add(-policy.Window))
+		if err != nil {
+			return err
+		}
+
+		if delay := policy.Delay(failures); delay > 0 {
+			if err := db.LockLogin(key, now.Add(delay)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// recordSecurityEvent func for store security event of the current request.
+// Failure of audit must not fail sign in, so error is ignored.
+func recordSecurityEvent(db *database.Queries, c *fiber.Ctx, eventType string, userID *uuid.UUID, username string) {
+	_ = db.CreateSecurityEvent(&models.SecurityEvent{
+		ID:        uuid.New(),
+		CreatedAt: time.Now(),
+		EventType: eventType,
+		UserID:    userID,
+		Username:  username,
+		IP:        c.IP(),
+		UserAgent: truncate(c.Get(fiber.HeaderUserAgent), 512),
+	})
+}
+
+func truncate(s string, max int) string {
+	if len(s) > max {
+		return s[:max]
+	}
+
+	return s
+}
@@ -0,0 +1,150 @@
+package controllers
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/koddr/tutorial-go-fiber-rest-api/app/models"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/problem"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/session"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/utils"
+	"github.com/koddr/tutorial-go-fiber-rest-api/platform/database"
+)
+
+// GetSession func gets the current cookie session and its user.
+// @Description Get the current cookie session and its user.
+// @Summary get the current session
+// @Tags Session
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.Session
+// @Router /v1/session [get]
+func GetSession(c *fiber.Ctx) error {
+	// Get principal of the current request.
+	principal, err := utils.GetPrincipal(c)
+	if err != nil {
+		// Return status 401 and unauthorized error message.
+		return problem.Unauthorized(err.Error())
+	}
+
+	// Create database connection.
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		// Return status 500 and database connection error.
+		return problem.Internal(err)
+	}
+
+	// Requests with tokens have no cookie session.
+	s, err := db.GetSessionByHash(utils.HashToken(session.ConfigFromEnv().Token(c)))
+	if err != nil || s.ID.String() != principal.TokenID {
+		// Return status 404 and session not found error.
+		return problem.NotFound("session is not found")
+	}
+
+	// Get user of session.
+	user, err := db.GetUserByID(s.UserID)
+	if err != nil {
+		// Return status 404 and user not found error.
+		return problem.NotFound("user with the given ID is not found")
+	}
+
+	// Return status 200 OK.
+	return c.JSON(fiber.Map{
+		"error":   false,
+		"msg":     nil,
+		"session": s,
+		"user":    user,
+		"roles":   principal.Roles,
+	})
+}
+
+// DeleteSession func for sign out from the current cookie session.
+// @Description Revoke the current cookie session and delete its cookies. Request must have X-CSRF-Token header.
+// @Summary sign out from the current session
+// @Tags Session
+// @Accept json
+// @Produce json
+// @Param X-CSRF-Token header string true "CSRF token from cookie"
+// @Success 204 {string} status "ok"
+// @Router /v1/session [delete]
+func DeleteSession(c *fiber.Ctx) error {
+	// Get principal of the current request.
+	principal, err := utils.GetPrincipal(c)
+	if err != nil {
+		// Return status 401 and unauthorized error message.
+		return problem.Unauthorized(err.Error())
+	}
+
+	// Create database connection.
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		// Return status 500 and database connection error.
+		return problem.Internal(err)
+	}
+
+	// Requests with tokens have no cookie session.
+	cfg := session.ConfigFromEnv()
+	s, err := db.GetSessionByHash(utils.HashToken(cfg.Token(c)))
+	if err != nil || s.ID.String() != principal.TokenID {
+		// Return status 404 and session not found error.
+		return problem.NotFound("session is not found")
+	}
+
+	// Revoke session, its cookies are useless after that anyway.
+	if err := db.RevokeSession(s.ID); err != nil {
+		// Return status 500 and database query error.
+		return problem.Internal(err)
+	}
+	cfg.ClearCookies(c)
+
+	// Return status 204 no content.
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// startSession func for start cookie session of user instead of issue tokens.
+// Session token is kept in HttpOnly cookie, CSRF token is returned to be sent
+// in the X-CSRF-Token header of unsafe requests.
+func startSession(c *fiber.Ctx, db *database.Queries, user *models.User, twoFactor bool) error {
+	// Generate random tokens of session and CSRF.
+	token, tokenHash, err := utils.GenerateNewRefreshToken()
+	if err != nil {
+		// Return status 500 and token generation error.
+		return problem.Internal(err)
+	}
+	csrfToken, csrfTokenHash, err := utils.GenerateNewRefreshToken()
+	if err != nil {
+		// Return status 500 and token generation error.
+		return problem.Internal(err)
+	}
+
+	// Create a new session, it expires after absolute timeout anyway.
+	cfg := session.ConfigFromEnv()
+	now := time.Now()
+	s := &models.Session{
+		ID:            uuid.New(),
+		CreatedAt:     now,
+		LastSeenAt:    now,
+		ExpiresAt:     now.Add(cfg.AbsoluteTimeout),
+		UserID:        user.ID,
+		TokenHash:     tokenHash,
+		CSRFTokenHash: csrfTokenHash,
+		TwoFactor:     twoFactor,
+		IP:            c.IP(),
+		UserAgent:     truncate(c.Get(fiber.HeaderUserAgent), 255),
+	}
+	if err := db.CreateSession(s); err != nil {
+		// Return status 500 and database query error.
+		return problem.Internal(err)
+	}
+	cfg.SetCookies(c, token, csrfToken, s.ExpiresAt)
+
+	// Return status 200 OK. User is told, if some roles are not granted without the second factor.
+	return c.JSON(fiber.Map{
+		"error":                          false,
+		"msg":                            nil,
+		"csrf_token":                     csrfToken,
+		"expires_at":                     s.ExpiresAt,
+		"two_factor_enrollment_required": len(utils.EffectiveRoles(user.Roles, twoFactor)) < len(user.Roles),
+	})
+}
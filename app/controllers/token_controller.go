@@ -1,27 +1,44 @@
 package controllers
 
 import (
+	"time"
+
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/koddr/tutorial-go-fiber-rest-api/app/models"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/problem"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/repository"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/revocation"
 	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/utils"
+	"github.com/koddr/tutorial-go-fiber-rest-api/platform/database"
 )
 
-//   method for create a new access token.
-// @Description Create a new access token.
+// GetNewAccessToken method for create a new access token of user without auth.
+// Route is registered only in development mode (STAGE_STATUS="dev"). Token has
+// only user role, so admin tokens are issued only by sign in of admins.
+// @Description Create a new access token with user role (development mode only).
 // @Summary create a new access token
 // @Tags Token
 // @Accept json
 // @Produce json
+// @Param user_id query string false "User ID (random, if not set)"
 // @Success 200 {string} status "ok"
 // @Router /v1/token/new [get]
 func GetNewAccessToken(c *fiber.Ctx) error {
+	// Set identity of token from URL.
+	userID, err := uuid.Parse(c.Query("user_id"))
+	if err != nil {
+		userID = uuid.New()
+	}
+
 	// Generate a new Access token.
-	token, err := utils.GenerateNewAccessToken()
+	token, err := utils.GenerateNewAccessToken(&utils.Principal{
+		Subject: userID.String(),
+		Roles:   []string{repository.UserRoleName},
+	})
 	if err != nil {
 		// Return status 500 and token generation error.
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": true,
-			"msg":   err.Error(),
-		})
+		return problem.Internal(err)
 	}
 
 	return c.JSON(fiber.Map{
@@ -30,3 +47,182 @@ func GetNewAccessToken(c *fiber.Ctx) error {
 		"access_token": token,
 	})
 }
+
+// RenewTokens method for renew access and refresh tokens by a refresh token.
+// Refresh token is rotated on every use. If already rotated token is used again,
+// the whole token family is revoked.
+// @Description Renew access and refresh tokens.
+// @Summary renew access and refresh tokens
+// @Tags Token
+// @Accept json
+// @Produce json
+// @Param refresh_token body string true "Refresh token"
+// @Success 200 {string} status "ok"
+// @Router /v1/token/renew [post]
+func RenewTokens(c *fiber.Ctx) error {
+	// Create a new renew struct.
+	renew := &models.Renew{}
+
+	// Checking received data from JSON body.
+	if err := c.BodyParser(renew); err != nil {
+		// Return status 400 and error message.
+		return problem.BadRequest(err.Error())
+	}
+
+	// Create a new validator for a Renew model.
+	validate := utils.NewValidator()
+
+	// Validate renew fields.
+	if err := validate.Struct(renew); err != nil {
+		// Return, if some fields are not valid.
+		return problem.Validation(err)
+	}
+
+	// Create database connection.
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		// Return status 500 and database connection error.
+		return problem.Internal(err)
+	}
+
+	// Get refresh token by its hash.
+	foundedToken, err := db.GetRefreshTokenByHash(utils.HashToken(renew.RefreshToken))
+	if err != nil || foundedToken.RevokedAt.Valid || time.Now().After(foundedToken.ExpiresAt) {
+		// Return status 401, if token is not found, revoked or expired.
+		return problem.Unauthorized("unauthorized, refresh token is not valid")
+	}
+
+	// Mark refresh token as used, only one request can do it.
+	used, err := db.UseRefreshToken(foundedToken.ID)
+	if err != nil {
+		// Return status 500 and database query error.
+		return problem.Internal(err)
+	}
+	if !used {
+		// Token was already rotated, so it was stolen or replayed: revoke the family.
+		if err := db.RevokeRefreshTokenFamily(foundedToken.FamilyID); err != nil {
+			// Return status 500 and database query error.
+			return problem.Internal(err)
+		}
+
+		// Return status 401 and reuse error.
+		return problem.Unauthorized("unauthorized, refresh token was already used")
+	}
+
+	// Get owner of token with actual roles.
+	foundedUser, err := db.GetUserByID(foundedToken.UserID)
+	if err != nil {
+		// Return status 401, if user is not found.
+		return problem.Unauthorized("unauthorized, user is not found")
+	}
+
+	// Generate a new pair of tokens for user in the same family.
+	accessToken, refreshToken, err := issueTokens(db, &foundedUser, foundedToken.FamilyID, foundedToken.TwoFactor)
+	if err != nil {
+		// Return status 500 and token generation error.
+		return problem.Internal(err)
+	}
+
+	// Return status 200 OK.
+	return c.JSON(fiber.Map{
+		"error":         false,
+		"msg":           nil,
+		"access_token":  accessToken,
+		"refresh_token": refreshToken,
+	})
+}
+
+// RevokeCurrentToken method for revoke access token of the current request.
+// @Description Revoke access token of the current request before its expiration.
+// @Summary revoke the current access token
+// @Tags Token
+// @Accept json
+// @Produce json
+// @Success 204 {string} status "ok"
+// @Security ApiKeyAuth
+// @Router /v1/token/revoke [post]
+func RevokeCurrentToken(c *fiber.Ctx) error {
+	// Get principal of the current request.
+	principal, err := utils.GetPrincipal(c)
+	if err != nil {
+		// Return status 401 and unauthorized error message.
+		return problem.Unauthorized(err.Error())
+	}
+
+	// Revoke token of principal.
+	if err := revokePrincipalToken(principal); err != nil {
+		// Return status 500 and revocation error.
+		return problem.Internal(err)
+	}
+
+	// Return status 204 no content.
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// RevokeToken method for revoke any access token by its ID (admin only).
+// @Description Revoke access token by its ID (jti claim), for admins only.
+// @Summary revoke access token by its ID
+// @Tags Token
+// @Accept json
+// @Produce json
+// @Param jti body string true "Token ID"
+// @Success 204 {string} status "ok"
+// @Security ApiKeyAuth
+// @Router /v1/admin/token/revoke [post]
+func RevokeToken(c *fiber.Ctx) error {
+	// Get principal of the current request.
+	principal, err := utils.GetPrincipal(c)
+	if err != nil {
+		// Return status 401 and unauthorized error message.
+		return problem.Unauthorized(err.Error())
+	}
+
+	// Checking, if principal is admin.
+	if !principal.IsAdmin() {
+		// Return status 403 and permission denied error.
+		return forbidden(c)
+	}
+
+	// Create a new revocation struct.
+	revocationRequest := &models.Revocation{}
+
+	// Checking received data from JSON body.
+	if err := c.BodyParser(revocationRequest); err != nil {
+		// Return status 400 and error message.
+		return problem.BadRequest(err.Error())
+	}
+
+	// Validate revocation fields.
+	if err := utils.NewValidator().Struct(revocationRequest); err != nil {
+		// Return, if some fields are not valid.
+		return problem.Validation(err)
+	}
+
+	// Expiration of token is unknown, so it's revoked for the longest lifetime of tokens.
+	now := time.Now()
+	if err := revocation.CurrentDenylist().Revoke(&models.RevokedToken{
+		TokenID:   revocationRequest.TokenID,
+		RevokedAt: now,
+		ExpiresAt: utils.AccessTokenExpiration(now),
+	}); err != nil {
+		// Return status 500 and revocation error.
+		return problem.Internal(err)
+	}
+
+	// Return status 204 no content.
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// revokePrincipalToken func for revoke access token of principal until its expiration.
+func revokePrincipalToken(principal *utils.Principal) error {
+	revokedToken := &models.RevokedToken{
+		TokenID:   principal.TokenID,
+		RevokedAt: time.Now(),
+		ExpiresAt: time.Unix(principal.Expires, 0),
+	}
+	if principal.UserID != uuid.Nil {
+		revokedToken.UserID = &principal.UserID
+	}
+
+	return revocation.CurrentDenylist().Revoke(revokedToken)
+}
@@ -0,0 +1,402 @@
+package controllers
+
+import (
+	"math"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/koddr/tutorial-go-fiber-rest-api/app/models"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/problem"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/totp"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/utils"
+	"github.com/koddr/tutorial-go-fiber-rest-api/platform/database"
+)
+
+// recoveryCodesCount is a count of recovery codes, which are given to user.
+const recoveryCodesCount = 10
+
+// EnrollTwoFactor func for create a new TOTP secret of user, which must be confirmed.
+// @Description Create a new TOTP secret of user. Second factor is enabled after it is confirmed with a code.
+// @Summary start enrollment of the second factor
+// @Tags User
+// @Accept json
+// @Produce json
+// @Success 200 {string} status "ok"
+// @Security ApiKeyAuth
+// @Router /v1/user/2fa/enroll [post]
+func EnrollTwoFactor(c *fiber.Ctx) error {
+	// Get principal of the current request.
+	principal, err := utils.GetPrincipal(c)
+	if err != nil {
+		// Return status 401 and unauthorized error message.
+		return problem.Unauthorized(err.Error())
+	}
+
+	// Create database connection.
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		// Return status 500 and database connection error.
+		return problem.Internal(err)
+	}
+
+	// Get user of principal.
+	foundedUser, err := db.GetUserByID(principal.UserID)
+	if err != nil {
+		// Return status 404 and user not found error.
+		return problem.NotFound("user with the given ID is not found")
+	}
+
+	// Generate a new secret.
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		// Return status 500 and secret generation error.
+		return problem.Internal(err)
+	}
+
+	// Store secret, which is pending until confirmation.
+	saved, err := db.SavePendingTOTP(&models.UserTOTP{
+		UserID:    foundedUser.ID,
+		CreatedAt: time.Now(),
+		Secret:    secret,
+	})
+	if err != nil {
+		// Return status 500 and database query error.
+		return problem.Internal(err)
+	}
+	if !saved {
+		// Return status 409 and conflict error.
+		return problem.Conflict("second factor is already enabled, disable it first")
+	}
+
+	// Return status 200 OK.
+	return c.JSON(fiber.Map{
+		"error":       false,
+		"msg":         nil,
+		"secret":      secret,
+		"otpauth_uri": totp.URI(twoFactorIssuer(), foundedUser.Username, secret),
+	})
+}
+
+// GetTwoFactorQRCode func for get QR code of TOTP secret, which is not confirmed yet.
+// @Description Get QR code (PNG) of TOTP secret for authenticator app.
+// @Summary get QR code of the second factor
+// @Tags User
+// @Produce png
+// @Success 200 {file} file "QR code"
+// @Security ApiKeyAuth
+// @Router /v1/user/2fa/qr [get]
+func GetTwoFactorQRCode(c *fiber.Ctx) error {
+	// Get principal of the current request.
+	principal, err := utils.GetPrincipal(c)
+	if err != nil {
+		// Return status 401 and unauthorized error message.
+		return problem.Unauthorized(err.Error())
+	}
+
+	// Create database connection.
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		// Return status 500 and database connection error.
+		return problem.Internal(err)
+	}
+
+	// Get user and its pending secret.
+	foundedUser, err := db.GetUserByID(principal.UserID)
+	if err != nil {
+		// Return status 404 and user not found error.
+		return problem.NotFound("user with the given ID is not found")
+	}
+	userTOTP, err := db.GetUserTOTP(foundedUser.ID)
+	if err != nil || userTOTP.EnabledAt != nil {
+		// Return status 404, secret is shown only until it is confirmed.
+		return problem.NotFound("pending second factor is not found, enroll first")
+	}
+
+	// Render QR code of the secret.
+	png, err := totp.QRCodePNG(totp.URI(twoFactorIssuer(), foundedUser.Username, userTOTP.Secret), 256)
+	if err != nil {
+		// Return status 500 and QR code generation error.
+		return problem.Internal(err)
+	}
+
+	// Return status 200 OK, secret must not be cached.
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	c.Set(fiber.HeaderContentType, "image/png")
+	return c.Send(png)
+}
+
+// ConfirmTwoFactor func for enable the second factor with a code of authenticator app.
+// @Description Enable the second factor with a code of authenticator app. Recovery codes are returned only once.
+// @Summary confirm the second factor
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param code body string true "Code of authenticator app"
+// @Success 200 {string} status "ok"
+// @Security ApiKeyAuth
+// @Router /v1/user/2fa/confirm [post]
+func ConfirmTwoFactor(c *fiber.Ctx) error {
+	// Get principal of the current request.
+	principal, err := utils.GetPrincipal(c)
+	if err != nil {
+		// Return status 401 and unauthorized error message.
+		return problem.Unauthorized(err.Error())
+	}
+
+	// Create a new code struct.
+	code := &models.TwoFactorCode{}
+
+	// Checking received data from JSON body.
+	if err := c.BodyParser(code); err != nil {
+		// Return status 400 and error message.
+		return problem.BadRequest(err.Error())
+	}
+
+	// Validate code field.
+	if err := utils.NewValidator().Struct(code); err != nil {
+		// Return, if some fields are not valid.
+		return problem.Validation(err)
+	}
+
+	// Create database connection.
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		// Return status 500 and database connection error.
+		return problem.Internal(err)
+	}
+
+	// Get pending secret of user.
+	userTOTP, err := db.GetUserTOTP(principal.UserID)
+	if err != nil || userTOTP.EnabledAt != nil {
+		// Return status 404 and not found error.
+		return problem.NotFound("pending second factor is not found, enroll first")
+	}
+
+	// Checking, if code is valid for the secret.
+	step, ok := totp.Validate(userTOTP.Secret, code.Code, time.Now())
+	if !ok {
+		// Return status 400 and wrong code error.
+		return problem.BadRequest("code is not valid")
+	}
+
+	// Generate recovery codes, only their hashes are stored.
+	recoveryCodes, err := totp.GenerateRecoveryCodes(recoveryCodesCount)
+	if err != nil {
+		// Return status 500 and generation error.
+		return problem.Internal(err)
+	}
+	hashes := make([]string, len(recoveryCodes))
+	for i, recoveryCode := range recoveryCodes {
+		hashes[i] = utils.HashToken(totp.NormalizeRecoveryCode(recoveryCode))
+	}
+
+	// Enable the second factor.
+	if err := db.EnableTOTP(principal.UserID, step, hashes); err != nil {
+		// Return status 500 and database query error.
+		return problem.Internal(err)
+	}
+	recordSecurityEvent(db, c, models.TwoFactorEnabledEvent, &principal.UserID, "")
+
+	// Return status 200 OK.
+	return c.JSON(fiber.Map{
+		"error":          false,
+		"msg":            nil,
+		"recovery_codes": recoveryCodes,
+	})
+}
+
+// DisableTwoFactor func for disable the second factor of user.
+// @Description Disable the second factor with a code of authenticator app or recovery code. All sessions of user are revoked.
+// @Summary disable the second factor
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param code body string true "Code of authenticator app or recovery code"
+// @Success 204 {string} status "ok"
+// @Security ApiKeyAuth
+// @Router /v1/user/2fa/disable [post]
+func DisableTwoFactor(c *fiber.Ctx) error {
+	// Get principal of the current request.
+	principal, err := utils.GetPrincipal(c)
+	if err != nil {
+		// Return status 401 and unauthorized error message.
+		return problem.Unauthorized(err.Error())
+	}
+
+	// Create a new code struct.
+	code := &models.TwoFactorCode{}
+
+	// Checking received data from JSON body.
+	if err := c.BodyParser(code); err != nil {
+		// Return status 400 and error message.
+		return problem.BadRequest(err.Error())
+	}
+
+	// Validate code field.
+	if err := utils.NewValidator().Struct(code); err != nil {
+		// Return, if some fields are not valid.
+		return problem.Validation(err)
+	}
+
+	// Create database connection.
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		// Return status 500 and database connection error.
+		return problem.Internal(err)
+	}
+
+	// Get enabled secret of user.
+	userTOTP, err := db.GetUserTOTP(principal.UserID)
+	if err != nil || userTOTP.EnabledAt == nil {
+		// Return status 404 and not found error.
+		return problem.NotFound("second factor is not enabled")
+	}
+
+	// Checking, if code is valid.
+	verified, err := verifySecondFactor(db, principal.UserID, userTOTP.Secret, code.Code)
+	if err != nil {
+		// Return status 500 and database query error.
+		return problem.Internal(err)
+	}
+	if !verified {
+		recordSecurityEvent(db, c, models.TwoFactorFailedEvent, &principal.UserID, "")
+
+		// Return status 400 and wrong code error.
+		return problem.BadRequest("code is not valid")
+	}
+
+	// Disable the second factor and revoke sessions, which were created with it.
+	if err := db.DeleteTwoFactor(principal.UserID); err != nil {
+		// Return status 500 and database query error.
+		return problem.Internal(err)
+	}
+	if err := db.RevokeUserRefreshTokens(principal.UserID); err != nil {
+		// Return status 500 and database query error.
+		return problem.Internal(err)
+	}
+	if err := db.RevokeUserSessions(principal.UserID); err != nil {
+		// Return status 500 and database query error.
+		return problem.Internal(err)
+	}
+	if err := revokePrincipalToken(principal); err != nil {
+		// Return status 500 and revocation error.
+		return problem.Internal(err)
+	}
+	recordSecurityEvent(db, c, models.TwoFactorDisabledEvent, &principal.UserID, "")
+
+	// Return status 204 no content.
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// UserSignInTwoFactor method to finish sign in with the second factor and return access and refresh tokens.
+// @Description Finish sign in with code of authenticator app or recovery code and return access and refresh tokens.
+// @Summary finish sign in with the second factor
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param mfa_token body string true "Token of the first step of sign in"
+// @Param code body string true "Code of authenticator app or recovery code"
+// @Param session query string false "Set to \"cookie\" for cookie session instead of tokens"
+// @Success 200 {string} status "ok"
+// @Router /v1/user/sign/in/2fa [post]
+func UserSignInTwoFactor(c *fiber.Ctx) error {
+	// Create a new sign in struct.
+	signIn := &models.TwoFactorSignIn{}
+
+	// Checking received data from JSON body.
+	if err := c.BodyParser(signIn); err != nil {
+		// Return status 400 and error message.
+		return problem.BadRequest(err.Error())
+	}
+
+	// Validate sign in fields.
+	if err := utils.NewValidator().Struct(signIn); err != nil {
+		// Return, if some fields are not valid.
+		return problem.Validation(err)
+	}
+
+	// Get user, who passed the first step of sign in.
+	userID, err := utils.ParseTwoFactorToken(signIn.MFAToken)
+	if err != nil {
+		// Return status 401 and unauthorized error message.
+		return problem.Unauthorized(err.Error())
+	}
+
+	// Create database connection.
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		// Return status 500 and database connection error.
+		return problem.Internal(err)
+	}
+
+	// Get user and its secret.
+	foundedUser, err := db.GetUserByID(userID)
+	if err != nil {
+		// Return status 401, if user is not found.
+		return problem.Unauthorized("user with the given ID is not found")
+	}
+	userTOTP, err := db.GetUserTOTP(foundedUser.ID)
+	if err != nil || userTOTP.EnabledAt == nil {
+		// Return status 401, if second factor was disabled in the meantime.
+		return problem.Unauthorized("second factor is not enabled")
+	}
+
+	// Checking, if sign in of username or IP is locked after failed attempts.
+	if wait := loginLockedFor(db, foundedUser.Username, c.IP()); wait > 0 {
+		recordSecurityEvent(db, c, models.SignInBlockedEvent, &foundedUser.ID, foundedUser.Username)
+
+		// Return status 429 and lockout error.
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+		return problem.TooManyRequests("too many failed attempts, retry later")
+	}
+
+	// Checking, if code is valid.
+	verified, err := verifySecondFactor(db, foundedUser.ID, userTOTP.Secret, signIn.Code)
+	if err != nil {
+		// Return status 500 and database query error.
+		return problem.Internal(err)
+	}
+	if !verified {
+		// Count failure of username and IP, codes are guessed as passwords.
+		if err := recordLoginFailure(db, foundedUser.Username, c.IP()); err != nil {
+			// Return status 500 and database query error.
+			return problem.Internal(err)
+		}
+		recordSecurityEvent(db, c, models.TwoFactorFailedEvent, &foundedUser.ID, foundedUser.Username)
+
+		// Return status 401 and wrong code error.
+		return problem.Unauthorized("code is not valid")
+	}
+
+	return signInCompleted(c, db, &foundedUser, true)
+}
+
+// verifySecondFactor func for checking code of authenticator app or recovery code of user.
+// Every code is accepted only once.
+func verifySecondFactor(db *database.Queries, userID uuid.UUID, secret, code string) (bool, error) {
+	// Codes of authenticator app have only digits.
+	if len(code) == totp.Digits {
+		if _, err := strconv.Atoi(code); err == nil {
+			step, ok := totp.Validate(secret, code, time.Now())
+			if !ok {
+				return false, nil
+			}
+
+			return db.UseTOTPStep(userID, step)
+		}
+	}
+
+	return db.UseRecoveryCode(userID, utils.HashToken(totp.NormalizeRecoveryCode(code)))
+}
+
+// twoFactorIssuer func for get name of issuer, which is shown in authenticator app.
+func twoFactorIssuer() string {
+	if issuer := os.Getenv("JWT_ISSUER"); issuer != "" {
+		return issuer
+	}
+
+	return "API"
+}
@@ -0,0 +1,382 @@
+package controllers
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/koddr/tutorial-go-fiber-rest-api/app/models"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/problem"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/utils"
+	"github.com/koddr/tutorial-go-fiber-rest-api/platform/database"
+)
+
+// GetUsers func gets page of users by filter and sort orders of admin-ui.
+// @Description Get page of users. Callers, who can read only own user by grants, get only themselves.
+// @Summary get page of users
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param where query string false "UserWhereInput as JSON, like {\"username\":{\"contains\":\"jo\"}}"
+// @Param orderBy query string false "Array of UserOrderByInput as JSON, like [{\"createdAt\":\"desc\"}]"
+// @Param skip query integer false "Count of users to skip"
+// @Param take query integer false "Count of users (default 100)"
+// @Success 200 {array} models.User
+// @Security ApiKeyAuth
+// @Router /v1/users [get]
+func GetUsers(c *fiber.Ctx) error {
+	// Get owner of users, callers with "read:own" grant get only themselves.
+	userID, err := requestedOwner(c)
+	if err != nil {
+		// Return status 401 and unauthorized error message.
+		return problem.Unauthorized(err.Error())
+	}
+
+	// Get filter, sort orders and page from URL.
+	args, err := userFindManyArgs(c)
+	if err != nil {
+		// Return status 400 and error message.
+		return problem.BadRequest(err.Error())
+	}
+
+	// Validate filter fields.
+	if err := utils.NewValidator().Struct(args); err != nil {
+		// Return, if some fields are not valid.
+		return problem.Validation(err)
+	}
+
+	// Create database connection.
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		// Return status 500 and database connection error.
+		return problem.Internal(err)
+	}
+
+	// Get page of users.
+	users, total, err := db.GetUsers(args, userID)
+	if err != nil {
+		// Return status 500 and database query error.
+		return problem.Internal(err)
+	}
+
+	// Return status 200 OK.
+	return c.JSON(fiber.Map{
+		"error": false,
+		"msg":   nil,
+		"count": len(users),
+		"total": total,
+		"users": users,
+	})
+}
+
+// GetUser func gets user by given ID or 404 error.
+// @Description Get user by given ID.
+// @Summary get user by given ID
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} models.User
+// @Security ApiKeyAuth
+// @Router /v1/users/{id} [get]
+func GetUser(c *fiber.Ctx) error {
+	// Catch user ID from URL.
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		// Return status 400 and error message.
+		return problem.BadRequest(err.Error())
+	}
+
+	// Callers with "read:own" grant get only themselves.
+	userID, err := requestedOwner(c)
+	if err != nil {
+		// Return status 401 and unauthorized error message.
+		return problem.Unauthorized(err.Error())
+	}
+	if userID != uuid.Nil && userID != id {
+		// Return status 403 and permission denied error.
+		return forbidden(c)
+	}
+
+	// Create database connection.
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		// Return status 500 and database connection error.
+		return problem.Internal(err)
+	}
+
+	// Get user by ID.
+	user, err := db.GetUserByID(id)
+	if err != nil {
+		// Return, if user not found.
+		return problem.NotFound("user with the given ID is not found")
+	}
+
+	// Return status 200 OK.
+	return c.JSON(fiber.Map{
+		"error": false,
+		"msg":   nil,
+		"user":  user,
+	})
+}
+
+// CreateUser func for creates a new user (admin only).
+// @Description Create a new user with the given password and roles (admin only).
+// @Summary create a new user
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param data body models.UserCreateInput true "New user"
+// @Success 200 {object} models.User
+// @Security ApiKeyAuth
+// @Router /v1/users [post]
+func CreateUser(c *fiber.Ctx) error {
+	// Create a new user input struct.
+	input := &models.UserCreateInput{}
+
+	// Checking received data from JSON body.
+	if err := c.BodyParser(input); err != nil {
+		// Return status 400 and error message.
+		return problem.BadRequest(err.Error())
+	}
+
+	// Validate user fields.
+	if err := utils.NewValidator().Struct(input); err != nil {
+		// Return, if some fields are not valid.
+		return problem.Validation(err)
+	}
+
+	// Create database connection.
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		// Return status 500 and database connection error.
+		return problem.Internal(err)
+	}
+
+	// Checking, if username and email are not taken.
+	if _, err := db.GetUserByUsername(input.Username); err == nil {
+		// Return status 409 and conflict error.
+		return problem.Conflict("user with the given username already exists")
+	}
+	if input.Email != "" {
+		if _, err := db.GetUserByEmail(input.Email); err == nil {
+			// Return status 409 and conflict error.
+			return problem.Conflict("user with the given email already exists")
+		}
+	}
+
+	// Make hash from the given password.
+	passwordHash, err := utils.GeneratePassword(input.Password)
+	if err != nil {
+		// Return status 500 and password hashing error.
+		return problem.Internal(err)
+	}
+
+	// Create a new user struct.
+	user := &models.User{
+		ID:           uuid.New(),
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
+		Username:     input.Username,
+		FirstName:    input.FirstName,
+		LastName:     input.LastName,
+		Roles:        input.Roles,
+		PasswordHash: passwordHash,
+	}
+	if input.Email != "" {
+		email := strings.ToLower(input.Email)
+		user.Email = &email
+	}
+
+	// Create a new user.
+	if err := db.CreateUser(user); err != nil {
+		// Return status 500 and create user process error.
+		return problem.Internal(err)
+	}
+
+	// Return status 200 OK.
+	return c.JSON(fiber.Map{
+		"error": false,
+		"msg":   nil,
+		"user":  user,
+	})
+}
+
+// UpdateUser func for updates user by given ID (admin only).
+// @Description Update only given fields of user (admin only). A new password is hashed and all sessions of user are revoked.
+// @Summary update user
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param data body models.UserUpdateInput true "Changed fields of user"
+// @Success 200 {object} models.User
+// @Security ApiKeyAuth
+// @Router /v1/users/{id} [put]
+func UpdateUser(c *fiber.Ctx) error {
+	// Catch user ID from URL.
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		// Return status 400 and error message.
+		return problem.BadRequest(err.Error())
+	}
+
+	// Create a new user input struct.
+	input := &models.UserUpdateInput{}
+
+	// Checking received data from JSON body.
+	if err := c.BodyParser(input); err != nil {
+		// Return status 400 and error message.
+		return problem.BadRequest(err.Error())
+	}
+
+	// Validate user fields.
+	if err := utils.NewValidator().Struct(input); err != nil {
+		// Return, if some fields are not valid.
+		return problem.Validation(err)
+	}
+
+	// Create database connection.
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		// Return status 500 and database connection error.
+		return problem.Internal(err)
+	}
+
+	// Checking, if user with given ID is exists.
+	user, err := db.GetUserByID(id)
+	if err != nil {
+		// Return status 404 and user not found error.
+		return problem.NotFound("user with the given ID is not found")
+	}
+
+	// Set only given fields of user.
+	if input.Username != nil && *input.Username != user.Username {
+		if _, err := db.GetUserByUsername(*input.Username); err == nil {
+			// Return status 409 and conflict error.
+			return problem.Conflict("user with the given username already exists")
+		}
+		user.Username = *input.Username
+	}
+	if input.FirstName != nil {
+		user.FirstName = *input.FirstName
+	}
+	if input.LastName != nil {
+		user.LastName = *input.LastName
+	}
+	if input.Roles != nil {
+		user.Roles = *input.Roles
+	}
+	if input.Password != nil {
+		// Make hash from the given password.
+		if user.PasswordHash, err = utils.GeneratePassword(*input.Password); err != nil {
+			// Return status 500 and password hashing error.
+			return problem.Internal(err)
+		}
+	}
+	user.UpdatedAt = time.Now()
+
+	// Update user.
+	if err := db.UpdateUser(&user); err != nil {
+		// Return status 500 and database query error.
+		return problem.Internal(err)
+	}
+
+	// Sessions, which were signed in with the old password, are revoked.
+	if input.Password != nil {
+		if err := db.RevokeUserRefreshTokens(user.ID); err != nil {
+			// Return status 500 and database query error.
+			return problem.Internal(err)
+		}
+		if err := db.RevokeUserSessions(user.ID); err != nil {
+			// Return status 500 and database query error.
+			return problem.Internal(err)
+		}
+		recordSecurityEvent(db, c, models.PasswordChangedEvent, &user.ID, user.Username)
+	}
+
+	// Return status 200 OK.
+	return c.JSON(fiber.Map{
+		"error": false,
+		"msg":   nil,
+		"user":  user,
+	})
+}
+
+// DeleteUser func for deletes user by given ID (admin only).
+// @Description Delete user by given ID (admin only). Personal organization of user is deleted too.
+// @Summary delete user by given ID
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 204 {string} status "ok"
+// @Security ApiKeyAuth
+// @Router /v1/users/{id} [delete]
+func DeleteUser(c *fiber.Ctx) error {
+	// Catch user ID from URL.
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		// Return status 400 and error message.
+		return problem.BadRequest(err.Error())
+	}
+
+	// Create database connection.
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		// Return status 500 and database connection error.
+		return problem.Internal(err)
+	}
+
+	// Checking, if user with given ID is exists.
+	if _, err := db.GetUserByID(id); err != nil {
+		// Return status 404 and user not found error.
+		return problem.NotFound("user with the given ID is not found")
+	}
+
+	// Delete user by given ID.
+	if err := db.DeleteUser(id); err != nil {
+		// Return status 500 and database query error.
+		return problem.Internal(err)
+	}
+
+	// Return status 204 no content.
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// userFindManyArgs func for get UserFindManyArgs from URL. Filter and sort
+// orders are JSON, like in admin-ui; a single sort order may be given without array.
+func userFindManyArgs(c *fiber.Ctx) (*models.UserFindManyArgs, error) {
+	args := &models.UserFindManyArgs{Take: 100}
+
+	if where := c.Query("where"); where != "" {
+		if err := json.Unmarshal([]byte(where), &args.Where); err != nil {
+			return nil, err
+		}
+	}
+	if orderBy := strings.TrimSpace(c.Query("orderBy")); orderBy != "" {
+		if strings.HasPrefix(orderBy, "{") {
+			orderBy = "[" + orderBy + "]"
+		}
+		if err := json.Unmarshal([]byte(orderBy), &args.OrderBy); err != nil {
+			return nil, err
+		}
+	}
+
+	var err error
+	if skip := c.Query("skip"); skip != "" {
+		if args.Skip, err = strconv.Atoi(skip); err != nil {
+			return nil, err
+		}
+	}
+	if take := c.Query("take"); take != "" {
+		if args.Take, err = strconv.Atoi(take); err != nil {
+			return nil, err
+		}
+	}
+
+	return args, nil
+}
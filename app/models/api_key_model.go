@@ -0,0 +1,58 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// APIKey struct to describe API key of user for machine clients.
+// Only hash of key is stored, the key itself is shown once on creation.
+type APIKey struct {
+	ID         uuid.UUID  `db:"id" json:"id"`
+	CreatedAt  time.Time  `db:"created_at" json:"created_at"`
+	ExpiresAt  *time.Time `db:"expires_at" json:"expires_at"`
+	LastUsedAt *time.Time `db:"last_used_at" json:"last_used_at"`
+	RevokedAt  *time.Time `db:"revoked_at" json:"revoked_at"`
+	UserID     uuid.UUID  `db:"user_id" json:"user_id"`
+	Name       string     `db:"name" json:"name"`
+	Prefix     string     `db:"prefix" json:"prefix"` // first characters of key, to recognize it
+	KeyHash    string     `db:"key_hash" json:"-"`
+	Scopes     StringList `db:"scopes" json:"scopes"`
+	AllowedIPs StringList `db:"allowed_ips" json:"allowed_ips"` // IPs or CIDRs, any IP if empty
+}
+
+// NewAPIKey struct to describe request for create API key.
+type NewAPIKey struct {
+	Name       string     `json:"name" validate:"required,lte=255"`
+	Scopes     []string   `json:"scopes" validate:"required,min=1,dive,scope"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	AllowedIPs []string   `json:"allowed_ips" validate:"dive,ip|cidr"`
+}
+
+// StringList struct to describe list of strings stored as JSON.
+type StringList []string
+
+// Value make the StringList struct implement the driver.Valuer interface.
+// This method simply returns the JSON-encoded representation of the struct.
+func (s StringList) Value() (driver.Value, error) {
+	if s == nil {
+		return []byte("[]"), nil
+	}
+
+	return json.Marshal([]string(s))
+}
+
+// Scan make the StringList struct implement the sql.Scanner interface.
+// This method simply decodes a JSON-encoded value into the struct fields.
+func (s *StringList) Scan(value interface{}) error {
+	j, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+
+	return json.Unmarshal(j, &s)
+}
@@ -4,17 +4,11 @@ import (
 	"database/sql/driver"
 	"encoding/json"
 	"errors"
-	"time"
-
-	"github.com/google/uuid"
 )
 
 // Book struct to describe book object.
 type Book struct {
-	ID         uuid.UUID `db:"id" json:"id" validate:"required,uuid"`
-	CreatedAt  time.Time `db:"created_at" json:"created_at"`
-	UpdatedAt  time.Time `db:"updated_at" json:"updated_at"`
-	UserID     uuid.UUID `db:"user_id" json:"user_id" validate:"required,uuid"`
+	Record
 	Title      string    `db:"title" json:"title" validate:"required,lte=255"`
 	Author     string    `db:"author" json:"author" validate:"required,lte=255"`
 	BookStatus int       `db:"book_status" json:"book_status" validate:"required,len=1"`
@@ -44,3 +38,38 @@ func (b *BookAttrs) Scan(value interface{}) error {
 
 	return json.Unmarshal(j, &b)
 }
+
+// BookWhereInput struct to describe filter of books in GraphQL.
+type BookWhereInput struct {
+	ID         *StringFilter `json:"id"`
+	UserID     *StringFilter `json:"userId"`
+	Title      *StringFilter `json:"title"`
+	Author     *StringFilter `json:"author"`
+	BookStatus *IntFilter    `json:"bookStatus"`
+}
+
+// BookOrderByInput struct to describe sort of books in GraphQL.
+type BookOrderByInput struct {
+	CreatedAt  string `json:"createdAt" validate:"omitempty,oneof=asc desc"`
+	UpdatedAt  string `json:"updatedAt" validate:"omitempty,oneof=asc desc"`
+	ID         string `json:"id" validate:"omitempty,oneof=asc desc"`
+	Title      string `json:"title" validate:"omitempty,oneof=asc desc"`
+	Author     string `json:"author" validate:"omitempty,oneof=asc desc"`
+	BookStatus string `json:"bookStatus" validate:"omitempty,oneof=asc desc"`
+}
+
+// BookFindManyArgs struct to describe page of books in GraphQL.
+type BookFindManyArgs struct {
+	Where   BookWhereInput     `json:"where"`
+	OrderBy []BookOrderByInput `json:"orderBy" validate:"dive"`
+	Skip    int                `json:"skip" validate:"min=0"`
+	Take    int                `json:"take" validate:"min=0,max=1000"`
+}
+
+// BookInput struct to describe fields of book in GraphQL, only given fields are set.
+type BookInput struct {
+	Title      *string    `json:"title"`
+	Author     *string    `json:"author"`
+	BookStatus *int       `json:"bookStatus"`
+	BookAttrs  *BookAttrs `json:"bookAttrs"`
+}
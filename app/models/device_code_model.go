@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Statuses of device authorization request.
+const (
+	DeviceCodePending  = "pending"
+	DeviceCodeApproved = "approved"
+	DeviceCodeDenied   = "denied"
+	DeviceCodeConsumed = "consumed"
+)
+
+// DeviceCode struct to describe device authorization request (RFC 8628).
+// Only hash of device code is stored, user code is typed by user, who approves it.
+type DeviceCode struct {
+	ID             uuid.UUID  `db:"id" json:"-"`
+	CreatedAt      time.Time  `db:"created_at" json:"created_at"`
+	ExpiresAt      time.Time  `db:"expires_at" json:"expires_at"`
+	DeviceCodeHash string     `db:"device_code_hash" json:"-"`
+	UserCode       string     `db:"user_code" json:"user_code"`
+	ClientID       string     `db:"client_id" json:"client_id"`
+	Status         string     `db:"status" json:"status"`
+	UserID         *uuid.UUID `db:"user_id" json:"-"`
+	TwoFactor      bool       `db:"two_factor" json:"-"`
+	PollInterval   int        `db:"poll_interval" json:"-"` // seconds between polls of device
+	LastPolledAt   *time.Time `db:"last_polled_at" json:"-"`
+}
+
+// DeviceVerification struct to describe request of user, who approves or denies device.
+type DeviceVerification struct {
+	UserCode string `json:"user_code" validate:"required,lte=16"`
+}
@@ -0,0 +1,40 @@
+package models
+
+// Sort orders of OrderByInput types (same as in admin-ui/src/util/SortOrder.ts).
+const (
+	SortAsc  = "asc"
+	SortDesc = "desc"
+)
+
+// InsensitiveMode const for case-insensitive mode of StringFilter.
+const InsensitiveMode = "insensitive"
+
+// StringFilter struct to describe filter of string field in WhereInput types
+// (same as in admin-ui/src/util/StringFilter.ts). All conditions must be true.
+type StringFilter struct {
+	Equals     *string  `json:"equals"`
+	In         []string `json:"in"`
+	NotIn      []string `json:"notIn"`
+	Lt         *string  `json:"lt"`
+	Lte        *string  `json:"lte"`
+	Gt         *string  `json:"gt"`
+	Gte        *string  `json:"gte"`
+	Contains   *string  `json:"contains"`
+	StartsWith *string  `json:"startsWith"`
+	EndsWith   *string  `json:"endsWith"`
+	Mode       string   `json:"mode" validate:"omitempty,oneof=default insensitive"`
+	Not        *string  `json:"not"`
+}
+
+// IntFilter struct to describe filter of integer field in WhereInput types
+// (same as in admin-ui/src/util/IntFilter.ts). All conditions must be true.
+type IntFilter struct {
+	Equals *int  `json:"equals"`
+	In     []int `json:"in"`
+	NotIn  []int `json:"notIn"`
+	Lt     *int  `json:"lt"`
+	Lte    *int  `json:"lte"`
+	Gt     *int  `json:"gt"`
+	Gte    *int  `json:"gte"`
+	Not    *int  `json:"not"`
+}
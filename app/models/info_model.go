@@ -4,21 +4,15 @@ import (
 	"database/sql/driver"
 	"encoding/json"
 	"errors"
-	"time"
-
-	"github.com/google/uuid"
 )
 
 // Info struct to describe Info object.
 type Info struct {
-	ID         uuid.UUID `db:"id" json:"id" validate:"required,uuid"`
-	CreatedAt  time.Time `db:"created_at" json:"created_at"`
-	UpdatedAt  time.Time `db:"updated_at" json:"updated_at"`
-	UserID     uuid.UUID `db:"user_id" json:"user_id" validate:"required,uuid"`
+	Record
 	Name       string    `db:"name" json:"name" validate:"required,lte=255"`
 	Portfolio  string    `db:"portfolio" json:"portfolio" validate:"required,lte=255"`
-	InfoStatus int       `db:"Info_status" json:"Info_status" validate:"required,len=1"`
-	InfoAttrs  InfoAttrs `db:"Info_attrs" json:"Info_attrs" validate:"required,dive"`
+	InfoStatus int       `db:"info_status" json:"Info_status" validate:"required,len=1"`
+	InfoAttrs  InfoAttrs `db:"info_attrs" json:"Info_attrs" validate:"required,dive"`
 }
 
 // InfoAttrs struct to describe Info attributes.
@@ -43,3 +37,38 @@ func (b *InfoAttrs) Scan(value interface{}) error {
 
 	return json.Unmarshal(j, &b)
 }
+
+// InfoWhereInput struct to describe filter of Info in GraphQL.
+type InfoWhereInput struct {
+	ID         *StringFilter `json:"id"`
+	UserID     *StringFilter `json:"userId"`
+	Name       *StringFilter `json:"name"`
+	Portfolio  *StringFilter `json:"portfolio"`
+	InfoStatus *IntFilter    `json:"infoStatus"`
+}
+
+// InfoOrderByInput struct to describe sort of Info in GraphQL.
+type InfoOrderByInput struct {
+	CreatedAt  string `json:"createdAt" validate:"omitempty,oneof=asc desc"`
+	UpdatedAt  string `json:"updatedAt" validate:"omitempty,oneof=asc desc"`
+	ID         string `json:"id" validate:"omitempty,oneof=asc desc"`
+	Name       string `json:"name" validate:"omitempty,oneof=asc desc"`
+	Portfolio  string `json:"portfolio" validate:"omitempty,oneof=asc desc"`
+	InfoStatus string `json:"infoStatus" validate:"omitempty,oneof=asc desc"`
+}
+
+// InfoFindManyArgs struct to describe page of Info in GraphQL.
+type InfoFindManyArgs struct {
+	Where   InfoWhereInput     `json:"where"`
+	OrderBy []InfoOrderByInput `json:"orderBy" validate:"dive"`
+	Skip    int                `json:"skip" validate:"min=0"`
+	Take    int                `json:"take" validate:"min=0,max=1000"`
+}
+
+// InfoInput struct to describe fields of Info in GraphQL, only given fields are set.
+type InfoInput struct {
+	Name       *string    `json:"name"`
+	Portfolio  *string    `json:"portfolio"`
+	InfoStatus *int       `json:"infoStatus"`
+	InfoAttrs  *InfoAttrs `json:"infoAttrs"`
+}
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OAuthClient struct to describe client of service-to-service calls, which gets
+// access tokens by client credentials. Only hash of secret is stored.
+type OAuthClient struct {
+	ID         uuid.UUID  `db:"id" json:"id"`
+	CreatedAt  time.Time  `db:"created_at" json:"created_at"`
+	RevokedAt  *time.Time `db:"revoked_at" json:"revoked_at"`
+	ClientID   string     `db:"client_id" json:"client_id"`
+	SecretHash string     `db:"secret_hash" json:"-"`
+	Name       string     `db:"name" json:"name"`
+	Scopes     StringList `db:"scopes" json:"scopes"`   // scopes, which client is allowed to request
+	Roles      StringList `db:"roles" json:"roles"`     // roles of access tokens of client
+	UserID     *uuid.UUID `db:"user_id" json:"user_id"` // service account, which owns records of client
+}
+
+// NewOAuthClient struct to describe request for register OAuth client.
+type NewOAuthClient struct {
+	Name   string   `json:"name" validate:"required,lte=255"`
+	Scopes []string `json:"scopes" validate:"required,min=1,dive,scope"`
+	Roles  []string `json:"roles" validate:"dive,required,lte=64"`
+
+	// Organization of service account of client and its role there, service
+	// account gets a personal organization without it.
+	OrganizationID   uuid.UUID `json:"organization_id"`
+	OrganizationRole string    `json:"organization_role" validate:"omitempty,oneof=admin member viewer"`
+}
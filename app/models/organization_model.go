@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Organization struct to describe organization, which owns books, servers and Info.
+// Every user has a personal organization with ID of user.
+type Organization struct {
+	ID        uuid.UUID  `db:"id" json:"id"`
+	CreatedAt time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt *time.Time `db:"updated_at" json:"updated_at"`
+	Name      string     `db:"name" json:"name" validate:"required,lte=255"`
+	Role      string     `db:"role" json:"role,omitempty"` // role of the current user, in list of organizations
+}
+
+// Membership struct to describe role of user in organization.
+type Membership struct {
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	UserID         uuid.UUID `db:"user_id" json:"user_id"`
+	Role           string    `db:"role" json:"role"`
+	CreatedAt      time.Time `db:"created_at" json:"created_at"`
+	Username       string    `db:"username" json:"username,omitempty"` // in list of members
+}
+
+// UpdateMembership struct to describe request of a new role of member.
+type UpdateMembership struct {
+	UserID uuid.UUID `json:"user_id" validate:"required,uuid"`
+	Role   string    `json:"role" validate:"required,oneof=owner admin member viewer"`
+}
+
+// DeleteMembership struct to describe request of removing member.
+type DeleteMembership struct {
+	UserID uuid.UUID `json:"user_id" validate:"required,uuid"`
+}
+
+// CreateInvitation struct to describe request of invitation to organization.
+type CreateInvitation struct {
+	Email string `json:"email" validate:"required,email,lte=255"`
+	Role  string `json:"role" validate:"required,oneof=owner admin member viewer"`
+}
+
+// AcceptInvitation struct to describe request of accepting invitation by token from link.
+type AcceptInvitation struct {
+	Token string `json:"token" validate:"required"`
+}
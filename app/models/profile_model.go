@@ -0,0 +1,51 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/normalize"
+)
+
+// Profile struct to describe a named normalization profile of user.
+type Profile struct {
+	ID             uuid.UUID      `db:"id" json:"id" validate:"required,uuid"`
+	CreatedAt      time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time      `db:"updated_at" json:"updated_at"`
+	UserID         uuid.UUID      `db:"user_id" json:"user_id" validate:"required,uuid"`
+	Name           string         `db:"name" json:"name" validate:"required,lte=255"`
+	ProfileOptions ProfileOptions `db:"profile_options" json:"profile_options" validate:"required"`
+}
+
+// ProfileOptions struct to describe normalization options of profile.
+type ProfileOptions struct {
+	normalize.Options
+}
+
+// Value make the ProfileOptions struct implement the driver.Valuer interface.
+// This method simply returns the JSON-encoded representation of the struct.
+func (p ProfileOptions) Value() (driver.Value, error) {
+	return json.Marshal(p)
+}
+
+// Scan make the ProfileOptions struct implement the sql.Scanner interface.
+// This method simply decodes a JSON-encoded value into the struct fields.
+func (p *ProfileOptions) Scan(value interface{}) error {
+	j, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+
+	return json.Unmarshal(j, &p)
+}
+
+// Normalization struct to describe a request for normalize a JSON document.
+type Normalization struct {
+	Document  json.RawMessage    `json:"document" validate:"required"`
+	ProfileID *uuid.UUID         `json:"profile_id"`
+	Options   *normalize.Options `json:"options"`
+	Algorithm string             `json:"algorithm" validate:"omitempty,oneof=sha256 sha384 sha512"`
+}
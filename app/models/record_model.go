@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Record struct to describe fields, which all records of organization have,
+// like books. Models of records embed it.
+type Record struct {
+	ID        uuid.UUID `db:"id" json:"id" validate:"required,uuid"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+	UserID    uuid.UUID `db:"user_id" json:"user_id" validate:"required,uuid"`
+	OrgID     uuid.UUID `db:"org_id" json:"org_id" validate:"required,uuid"`
+}
+
+// Base method for get fields of record, which all records have.
+func (r *Record) Base() *Record {
+	return r
+}
@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RevokedToken struct to describe access token revoked before its expiration.
+// It's kept until expiration of token, then it's pruned.
+type RevokedToken struct {
+	TokenID   string     `db:"token_id" json:"jti"`
+	RevokedAt time.Time  `db:"revoked_at" json:"revoked_at"`
+	ExpiresAt time.Time  `db:"expires_at" json:"expires_at"`
+	UserID    *uuid.UUID `db:"user_id" json:"user_id"`
+}
+
+// Revocation struct to describe request for revoke access token by its ID.
+type Revocation struct {
+	TokenID string `json:"jti" validate:"required,lte=255"`
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Types of security events.
+const (
+	SignInSucceededEvent   = "sign_in_succeeded"
+	SignInFailedEvent      = "sign_in_failed"
+	SignInBlockedEvent     = "sign_in_blocked"
+	AccountUnlockedEvent   = "account_unlocked"
+	TwoFactorFailedEvent   = "two_factor_failed"
+	TwoFactorEnabledEvent  = "two_factor_enabled"
+	TwoFactorDisabledEvent = "two_factor_disabled"
+	PasswordResetEvent     = "password_reset"
+	PasswordChangedEvent   = "password_changed"
+	EmailVerifiedEvent     = "email_verified"
+)
+
+// LoginAttempt struct to describe failed sign in attempts of username or IP.
+type LoginAttempt struct {
+	Key           string     `db:"key" json:"key"` // "user:<username>" or "ip:<IP>"
+	Failures      int        `db:"failures" json:"failures"`
+	LastFailureAt time.Time  `db:"last_failure_at" json:"last_failure_at"`
+	LockedUntil   *time.Time `db:"locked_until" json:"locked_until"`
+}
+
+// SecurityEvent struct to describe event of sign in or account security.
+type SecurityEvent struct {
+	ID        uuid.UUID  `db:"id" json:"id"`
+	CreatedAt time.Time  `db:"created_at" json:"created_at"`
+	EventType string     `db:"event_type" json:"event_type"`
+	UserID    *uuid.UUID `db:"user_id" json:"user_id"`
+	Username  string     `db:"username" json:"username"`
+	IP        string     `db:"ip" json:"ip"`
+	UserAgent string     `db:"user_agent" json:"user_agent"`
+}
+
+// Unlock struct to describe request for unlock sign in of username.
+type Unlock struct {
+	Username string `json:"username" validate:"required,lte=255"`
+}
@@ -4,21 +4,15 @@ import (
 	"database/sql/driver"
 	"encoding/json"
 	"errors"
-	"time"
-
-	"github.com/google/uuid"
 )
 
 // Server struct to describe server object.
 type Server struct {
-	ID           uuid.UUID `db:"id" json:"id" validate:"required,uuid"`
-	CreatedAt    time.Time `db:"created_at" json:"created_at"`
-	UpdatedAt    time.Time `db:"updated_at" json:"updated_at"`
-	UserID       uuid.UUID `db:"user_id" json:"user_id" validate:"required,uuid"`
-	Title        string    `db:"title" json:"title" validate:"required,lte=255"`
-	Author       string    `db:"author" json:"author" validate:"required,lte=255"`
-	ServerStatus int       `db:"server_status" json:"server_status" validate:"required,len=1"`
-	ServerAttrs    ServerAttrs `db:"server_attrs" json:"server_attrs" validate:"required,dive"`
+	Record
+	Title        string      `db:"title" json:"title" validate:"required,lte=255"`
+	Author       string      `db:"author" json:"author" validate:"required,lte=255"`
+	ServerStatus int         `db:"server_status" json:"server_status" validate:"required,len=1"`
+	ServerAttrs  ServerAttrs `db:"server_attrs" json:"server_attrs" validate:"required,dive"`
 }
 
 // ServerAttrs struct to describe server attributes.
@@ -44,3 +38,38 @@ func (b *ServerAttrs) Scan(value interface{}) error {
 
 	return json.Unmarshal(j, &b)
 }
+
+// ServerWhereInput struct to describe filter of servers in GraphQL.
+type ServerWhereInput struct {
+	ID           *StringFilter `json:"id"`
+	UserID       *StringFilter `json:"userId"`
+	Title        *StringFilter `json:"title"`
+	Author       *StringFilter `json:"author"`
+	ServerStatus *IntFilter    `json:"serverStatus"`
+}
+
+// ServerOrderByInput struct to describe sort of servers in GraphQL.
+type ServerOrderByInput struct {
+	CreatedAt    string `json:"createdAt" validate:"omitempty,oneof=asc desc"`
+	UpdatedAt    string `json:"updatedAt" validate:"omitempty,oneof=asc desc"`
+	ID           string `json:"id" validate:"omitempty,oneof=asc desc"`
+	Title        string `json:"title" validate:"omitempty,oneof=asc desc"`
+	Author       string `json:"author" validate:"omitempty,oneof=asc desc"`
+	ServerStatus string `json:"serverStatus" validate:"omitempty,oneof=asc desc"`
+}
+
+// ServerFindManyArgs struct to describe page of servers in GraphQL.
+type ServerFindManyArgs struct {
+	Where   ServerWhereInput     `json:"where"`
+	OrderBy []ServerOrderByInput `json:"orderBy" validate:"dive"`
+	Skip    int                  `json:"skip" validate:"min=0"`
+	Take    int                  `json:"take" validate:"min=0,max=1000"`
+}
+
+// ServerInput struct to describe fields of server in GraphQL, only given fields are set.
+type ServerInput struct {
+	Title        *string      `json:"title"`
+	Author       *string      `json:"author"`
+	ServerStatus *int         `json:"serverStatus"`
+	ServerAttrs  *ServerAttrs `json:"serverAttrs"`
+}
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ServiceAccount struct to describe user of OAuth client or client certificate.
+// Service account owns records and is a member of organizations, like users,
+// but it can't sign in.
+type ServiceAccount struct {
+	UserID    uuid.UUID `db:"user_id" json:"user_id"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	Identity  string    `db:"identity" json:"identity"` // like "oauth:<client ID>" or "cert:<identity of certificate>"
+}
+
+// Prefixes of identities of service accounts.
+const (
+	OAuthClientIdentityPrefix = "oauth:" // followed by client ID
+	ClientCertIdentityPrefix  = "cert:"  // followed by identity of certificate
+)
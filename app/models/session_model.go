@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Session struct to describe cookie session of browser. Only hashes of session
+// and CSRF tokens are stored, tokens are kept by browser in cookies.
+type Session struct {
+	ID            uuid.UUID  `db:"id" json:"id"`
+	CreatedAt     time.Time  `db:"created_at" json:"created_at"`
+	LastSeenAt    time.Time  `db:"last_seen_at" json:"last_seen_at"`
+	ExpiresAt     time.Time  `db:"expires_at" json:"expires_at"` // absolute timeout
+	RevokedAt     *time.Time `db:"revoked_at" json:"revoked_at"`
+	UserID        uuid.UUID  `db:"user_id" json:"user_id"`
+	TokenHash     string     `db:"token_hash" json:"-"`
+	CSRFTokenHash string     `db:"csrf_token_hash" json:"-"`
+	TwoFactor     bool       `db:"two_factor" json:"two_factor"` // session was started with the second factor
+	IP            string     `db:"ip" json:"ip"`
+	UserAgent     string     `db:"user_agent" json:"user_agent"`
+}
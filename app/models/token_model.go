@@ -0,0 +1,27 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RefreshToken struct to describe stored refresh token. Only hash of token is stored,
+// every use rotates token to a new one of the same family.
+type RefreshToken struct {
+	ID        uuid.UUID    `db:"id" json:"id"`
+	CreatedAt time.Time    `db:"created_at" json:"created_at"`
+	ExpiresAt time.Time    `db:"expires_at" json:"expires_at"`
+	UsedAt    sql.NullTime `db:"used_at" json:"-"`
+	RevokedAt sql.NullTime `db:"revoked_at" json:"-"`
+	FamilyID  uuid.UUID    `db:"family_id" json:"family_id"`
+	UserID    uuid.UUID    `db:"user_id" json:"user_id"`
+	TokenHash string       `db:"token_hash" json:"-"`
+	TwoFactor bool         `db:"two_factor" json:"two_factor"` // session was started with the second factor
+}
+
+// Renew struct to describe refresh token in request.
+type Renew struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
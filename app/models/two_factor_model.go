@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserTOTP struct to describe TOTP secret of user. Second factor is enabled
+// only after user confirmed it with a valid code.
+type UserTOTP struct {
+	UserID       uuid.UUID  `db:"user_id" json:"user_id"`
+	CreatedAt    time.Time  `db:"created_at" json:"created_at"`
+	EnabledAt    *time.Time `db:"enabled_at" json:"enabled_at"`
+	Secret       string     `db:"secret" json:"-"`
+	LastUsedStep int64      `db:"last_used_step" json:"-"` // time step of the last code, it can't be used again
+}
+
+// TwoFactorCode struct to describe code of authenticator app or recovery code.
+type TwoFactorCode struct {
+	Code string `json:"code" validate:"required,lte=32"`
+}
+
+// TwoFactorSignIn struct to describe the second step of sign in.
+type TwoFactorSignIn struct {
+	MFAToken string `json:"mfa_token" validate:"required"`
+	Code     string `json:"code" validate:"required,lte=32"`
+}
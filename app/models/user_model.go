@@ -0,0 +1,125 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// User struct to describe User object.
+type User struct {
+	ID              uuid.UUID  `db:"id" json:"id" validate:"required,uuid"`
+	CreatedAt       time.Time  `db:"created_at" json:"createdAt"`
+	UpdatedAt       time.Time  `db:"updated_at" json:"updatedAt"`
+	Username        string     `db:"username" json:"username" validate:"required,lte=255"`
+	FirstName       string     `db:"first_name" json:"firstName" validate:"lte=255"`
+	LastName        string     `db:"last_name" json:"lastName" validate:"lte=255"`
+	Roles           Roles      `db:"roles" json:"roles" validate:"required,min=1"`
+	PasswordHash    string     `db:"password_hash" json:"-" validate:"required"`
+	Email           *string    `db:"email" json:"email" validate:"omitempty,email,lte=255"`
+	EmailVerifiedAt *time.Time `db:"email_verified_at" json:"emailVerifiedAt"`
+}
+
+// UserIdentity struct to describe link of user to account at OpenID Connect provider.
+type UserIdentity struct {
+	ID        uuid.UUID `db:"id" json:"id"`
+	CreatedAt time.Time `db:"created_at" json:"createdAt"`
+	UserID    uuid.UUID `db:"user_id" json:"userId"`
+	Issuer    string    `db:"issuer" json:"issuer"`
+	Subject   string    `db:"subject" json:"subject"`
+}
+
+// Roles struct to describe roles of user.
+type Roles []string
+
+// Value make the Roles struct implement the driver.Valuer interface.
+// This method simply returns the JSON-encoded representation of the struct.
+func (r Roles) Value() (driver.Value, error) {
+	return json.Marshal(r)
+}
+
+// Scan make the Roles struct implement the sql.Scanner interface.
+// This method simply decodes a JSON-encoded value into the struct fields.
+func (r *Roles) Scan(value interface{}) error {
+	j, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+
+	return json.Unmarshal(j, &r)
+}
+
+// Has method for checking, if roles contain the given role.
+func (r Roles) Has(role string) bool {
+	for _, name := range r {
+		if name == role {
+			return true
+		}
+	}
+	return false
+}
+
+// SignUp struct to describe register a new user.
+type SignUp struct {
+	Username  string `json:"username" validate:"required,lte=255"`
+	Password  string `json:"password" validate:"required,min=8,max=72"`
+	FirstName string `json:"firstName" validate:"lte=255"`
+	LastName  string `json:"lastName" validate:"lte=255"`
+	Email     string `json:"email" validate:"omitempty,email,lte=255"`
+}
+
+// SignIn struct to describe login user.
+type SignIn struct {
+	Username string `json:"username" validate:"required,lte=255"`
+	Password string `json:"password" validate:"required,lte=255"`
+}
+
+// UserWhereInput struct to describe filter of users (admin-ui/src/api/user/UserWhereInput.ts).
+type UserWhereInput struct {
+	ID        *StringFilter `json:"id"`
+	Username  *StringFilter `json:"username"`
+	FirstName *StringFilter `json:"firstName"`
+	LastName  *StringFilter `json:"lastName"`
+}
+
+// UserOrderByInput struct to describe sort of users (admin-ui/src/api/user/UserOrderByInput.ts).
+// Users are not sorted by password, its hash must not leak by order.
+type UserOrderByInput struct {
+	CreatedAt string `json:"createdAt" validate:"omitempty,oneof=asc desc"`
+	FirstName string `json:"firstName" validate:"omitempty,oneof=asc desc"`
+	ID        string `json:"id" validate:"omitempty,oneof=asc desc"`
+	LastName  string `json:"lastName" validate:"omitempty,oneof=asc desc"`
+	Roles     string `json:"roles" validate:"omitempty,oneof=asc desc"`
+	UpdatedAt string `json:"updatedAt" validate:"omitempty,oneof=asc desc"`
+	Username  string `json:"username" validate:"omitempty,oneof=asc desc"`
+}
+
+// UserFindManyArgs struct to describe page of users (admin-ui/src/api/user/UserFindManyArgs.ts).
+type UserFindManyArgs struct {
+	Where   UserWhereInput     `json:"where"`
+	OrderBy []UserOrderByInput `json:"orderBy" validate:"dive"`
+	Skip    int                `json:"skip" validate:"min=0"`
+	Take    int                `json:"take" validate:"min=0,max=1000"`
+}
+
+// UserCreateInput struct to describe a new user created by admin.
+type UserCreateInput struct {
+	Username  string `json:"username" validate:"required,lte=255"`
+	Password  string `json:"password" validate:"required,min=8,max=72"`
+	FirstName string `json:"firstName" validate:"lte=255"`
+	LastName  string `json:"lastName" validate:"lte=255"`
+	Roles     Roles  `json:"roles" validate:"required,min=1,dive,oneof=admin user"`
+	Email     string `json:"email" validate:"omitempty,email,lte=255"`
+}
+
+// UserUpdateInput struct to describe changes of user by admin, only given fields are changed.
+type UserUpdateInput struct {
+	Username  *string `json:"username" validate:"omitempty,min=1,lte=255"`
+	Password  *string `json:"password" validate:"omitempty,min=8,max=72"`
+	FirstName *string `json:"firstName" validate:"omitempty,lte=255"`
+	LastName  *string `json:"lastName" validate:"omitempty,lte=255"`
+	Roles     *Roles  `json:"roles" validate:"omitempty,min=1,dive,oneof=admin user"`
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Purposes of user tokens.
+const (
+	PasswordResetPurpose     = "password_reset"
+	EmailVerificationPurpose = "email_verification"
+)
+
+// UserToken struct to describe single-use token, which is sent to email of user
+// in link of password reset or email verification. Only hash of token is stored.
+type UserToken struct {
+	ID        uuid.UUID  `db:"id" json:"id"`
+	CreatedAt time.Time  `db:"created_at" json:"created_at"`
+	ExpiresAt time.Time  `db:"expires_at" json:"expires_at"`
+	UsedAt    *time.Time `db:"used_at" json:"used_at"`
+	UserID    uuid.UUID  `db:"user_id" json:"user_id"`
+	Purpose   string     `db:"purpose" json:"purpose"`
+	TokenHash string     `db:"token_hash" json:"-"`
+	Email     string     `db:"email" json:"email"` // address, which the token was sent to
+}
+
+// ForgotPassword struct to describe request of password reset link.
+type ForgotPassword struct {
+	Email string `json:"email" validate:"required,email,lte=255"`
+}
+
+// ResetPassword struct to describe reset of password by token from link.
+type ResetPassword struct {
+	Token    string `json:"token" validate:"required,lte=255"`
+	Password string `json:"password" validate:"required,min=8,max=72"`
+}
+
+// VerifyEmail struct to describe verification of email by token from link.
+type VerifyEmail struct {
+	Token string `json:"token" validate:"required,lte=255"`
+}
+
+// ChangeEmail struct to describe change of email of user.
+type ChangeEmail struct {
+	Email string `json:"email" validate:"required,email,lte=255"`
+}
@@ -0,0 +1,118 @@
+package queries
+
+import (
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/koddr/tutorial-go-fiber-rest-api/app/models"
+)
+
+// APIKeyQueries struct for queries from APIKey model.
+type APIKeyQueries struct {
+	*sqlx.DB
+}
+
+// GetAPIKeysByUser method for getting all API keys of given user.
+func (q *APIKeyQueries) GetAPIKeysByUser(userID uuid.UUID) ([]models.APIKey, error) {
+	// Define API keys variable.
+	keys := []models.APIKey{}
+
+	// Define query string.
+	query := `SELECT * FROM api_keys WHERE user_id = $1 ORDER BY created_at DESC`
+
+	// Send query to database.
+	err := q.Select(&keys, query, userID)
+	if err != nil {
+		// Return empty object and error.
+		return keys, err
+	}
+
+	// Return query result.
+	return keys, nil
+}
+
+// GetAPIKey method for getting one API key by given ID.
+func (q *APIKeyQueries) GetAPIKey(id uuid.UUID) (models.APIKey, error) {
+	// Define API key variable.
+	key := models.APIKey{}
+
+	// Define query string.
+	query := `SELECT * FROM api_keys WHERE id = $1`
+
+	// Send query to database.
+	err := q.Get(&key, query, id)
+	if err != nil {
+		// Return empty object and error.
+		return key, err
+	}
+
+	// Return query result.
+	return key, nil
+}
+
+// GetAPIKeyByHash method for getting one API key by given hash.
+func (q *APIKeyQueries) GetAPIKeyByHash(hash string) (models.APIKey, error) {
+	// Define API key variable.
+	key := models.APIKey{}
+
+	// Define query string.
+	query := `SELECT * FROM api_keys WHERE key_hash = $1`
+
+	// Send query to database.
+	err := q.Get(&key, query, hash)
+	if err != nil {
+		// Return empty object and error.
+		return key, err
+	}
+
+	// Return query result.
+	return key, nil
+}
+
+// CreateAPIKey method for creating API key by given APIKey object.
+func (q *APIKeyQueries) CreateAPIKey(k *models.APIKey) error {
+	// Define query string.
+	query := `INSERT INTO api_keys (id, created_at, expires_at, user_id, name, prefix, key_hash, scopes, allowed_ips) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+
+	// Send query to database.
+	_, err := q.Exec(query, k.ID, k.CreatedAt, k.ExpiresAt, k.UserID, k.Name, k.Prefix, k.KeyHash, k.Scopes, k.AllowedIPs)
+	if err != nil {
+		// Return only error.
+		return err
+	}
+
+	// This query returns nothing.
+	return nil
+}
+
+// TouchAPIKey method for update last used time of API key. Time is updated
+// not often than once a minute, so busy keys don't write on every request.
+func (q *APIKeyQueries) TouchAPIKey(id uuid.UUID) error {
+	// Define query string.
+	query := `UPDATE api_keys SET last_used_at = NOW () WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < NOW () - INTERVAL '1 minute')`
+
+	// Send query to database.
+	_, err := q.Exec(query, id)
+	if err != nil {
+		// Return only error.
+		return err
+	}
+
+	// This query returns nothing.
+	return nil
+}
+
+// RevokeAPIKey method for revoke API key by given ID.
+func (q *APIKeyQueries) RevokeAPIKey(id uuid.UUID) error {
+	// Define query string.
+	query := `UPDATE api_keys SET revoked_at = NOW () WHERE id = $1 AND revoked_at IS NULL`
+
+	// Send query to database.
+	_, err := q.Exec(query, id)
+	if err != nil {
+		// Return only error.
+		return err
+	}
+
+	// This query returns nothing.
+	return nil
+}
@@ -2,25 +2,24 @@ package queries
 
 import (
 	"github.com/google/uuid"
-	"github.com/jmoiron/sqlx"
 	"github.com/koddr/tutorial-go-fiber-rest-api/app/models"
 )
 
 // BookQueries struct for queries from Book model.
 type BookQueries struct {
-	*sqlx.DB
+	Executor
 }
 
-// GetBooks method for getting all books.
-func (q *BookQueries) GetBooks() ([]models.Book, error) {
+// GetBooks method for getting all books of the given organization.
+func (q *BookQueries) GetBooks(orgID uuid.UUID) ([]models.Book, error) {
 	// Define books variable.
 	books := []models.Book{}
 
 	// Define query string.
-	query := `SELECT * FROM books`
+	query := `SELECT * FROM books WHERE org_id = $1`
 
 	// Send query to database.
-	err := q.Get(&books, query)
+	err := q.Select(&books, query, orgID)
 	if err != nil {
 		// Return empty object and error.
 		return books, err
@@ -30,16 +29,55 @@ func (q *BookQueries) GetBooks() ([]models.Book, error) {
 	return books, nil
 }
 
-// GetBook method for getting one book by given ID.
-func (q *BookQueries) GetBook(id uuid.UUID) (models.Book, error) {
+// GetBooksByUser method for getting all books of the given user in organization.
+func (q *BookQueries) GetBooksByUser(orgID, userID uuid.UUID) ([]models.Book, error) {
+	// Define books variable.
+	books := []models.Book{}
+
+	// Define query string.
+	query := `SELECT * FROM books WHERE org_id = $1 AND user_id = $2`
+
+	// Send query to database.
+	err := q.Select(&books, query, orgID, userID)
+	if err != nil {
+		// Return empty object and error.
+		return books, err
+	}
+
+	// Return query result.
+	return books, nil
+}
+
+// GetBook method for getting one book of organization by given ID.
+func (q *BookQueries) GetBook(orgID, id uuid.UUID) (models.Book, error) {
 	// Define book variable.
 	book := models.Book{}
 
 	// Define query string.
-	query := `SELECT * FROM books WHERE id = $1`
+	query := `SELECT * FROM books WHERE org_id = $1 AND id = $2`
 
 	// Send query to database.
-	err := q.Get(&book, query, id)
+	err := q.Get(&book, query, orgID, id)
+	if err != nil {
+		// Return empty object and error.
+		return book, err
+	}
+
+	// Return query result.
+	return book, nil
+}
+
+// GetBookForUpdate method for getting one book of organization by given ID
+// and locking it until the end of transaction, so it's changed by one request at a time.
+func (q *BookQueries) GetBookForUpdate(orgID, id uuid.UUID) (models.Book, error) {
+	// Define book variable.
+	book := models.Book{}
+
+	// Define query string.
+	query := `SELECT * FROM books WHERE org_id = $1 AND id = $2 FOR UPDATE`
+
+	// Send query to database.
+	err := q.Get(&book, query, orgID, id)
 	if err != nil {
 		// Return empty object and error.
 		return book, err
@@ -52,10 +90,10 @@ func (q *BookQueries) GetBook(id uuid.UUID) (models.Book, error) {
 // CreateBook method for creating book by given Book object.
 func (q *BookQueries) CreateBook(b *models.Book) error {
 	// Define query string.
-	query := `INSERT INTO books VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+	query := `INSERT INTO books (id, created_at, updated_at, user_id, org_id, title, author, book_status, book_attrs) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
 
 	// Send query to database.
-	_, err := q.Exec(query, b.ID, b.CreatedAt, b.UpdatedAt, b.UserID, b.Title, b.Author, b.BookStatus, b.BookAttrs)
+	_, err := q.Exec(query, b.ID, b.CreatedAt, b.UpdatedAt, b.UserID, b.OrgID, b.Title, b.Author, b.BookStatus, b.BookAttrs)
 	if err != nil {
 		// Return only error.
 		return err
@@ -65,13 +103,13 @@ func (q *BookQueries) CreateBook(b *models.Book) error {
 	return nil
 }
 
-// UpdateBook method for updating book by given Book object.
+// UpdateBook method for updating book of its organization by given Book object.
 func (q *BookQueries) UpdateBook(id uuid.UUID, b *models.Book) error {
 	// Define query string.
-	query := `UPDATE books SET updated_at = $2, title = $3, author = $4, book_status = $5, book_attrs = $6 WHERE id = $1`
+	query := `UPDATE books SET updated_at = $2, title = $3, author = $4, book_status = $5, book_attrs = $6 WHERE id = $1 AND org_id = $7`
 
 	// Send query to database.
-	_, err := q.Exec(query, id, b.UpdatedAt, b.Title, b.Author, b.BookStatus, b.BookAttrs)
+	_, err := q.Exec(query, id, b.UpdatedAt, b.Title, b.Author, b.BookStatus, b.BookAttrs, b.OrgID)
 	if err != nil {
 		// Return only error.
 		return err
@@ -81,13 +119,13 @@ func (q *BookQueries) UpdateBook(id uuid.UUID, b *models.Book) error {
 	return nil
 }
 
-// DeleteBook method for delete book by given ID.
-func (q *BookQueries) DeleteBook(id uuid.UUID) error {
+// DeleteBook method for delete book of organization by given ID.
+func (q *BookQueries) DeleteBook(orgID, id uuid.UUID) error {
 	// Define query string.
-	query := `DELETE FROM books WHERE id = $1`
+	query := `DELETE FROM books WHERE org_id = $1 AND id = $2`
 
 	// Send query to database.
-	_, err := q.Exec(query, id)
+	_, err := q.Exec(query, orgID, id)
 	if err != nil {
 		// Return only error.
 		return err
@@ -96,3 +134,79 @@ func (q *BookQueries) DeleteBook(id uuid.UUID) error {
 	// This query returns nothing.
 	return nil
 }
+
+// FindBooks method for getting page of books of the given organization by given
+// filter, sort orders, skip and take, and count of all books of filter.
+func (q *BookQueries) FindBooks(orgID uuid.UUID, args *models.BookFindManyArgs) ([]models.Book, int, error) {
+	// Define books variable.
+	books := []models.Book{}
+
+	// Define filter of books.
+	where := &whereBuilder{}
+	where.add("org_id = " + where.arg(orgID))
+	where.stringFilter("id::TEXT", args.Where.ID)
+	where.stringFilter("user_id::TEXT", args.Where.UserID)
+	where.stringFilter("title", args.Where.Title)
+	where.stringFilter("author", args.Where.Author)
+	where.intFilter("book_status", args.Where.BookStatus)
+
+	// Define sort orders of books.
+	order := &orderBuilder{}
+	for _, o := range args.OrderBy {
+		order.add("created_at", o.CreatedAt)
+		order.add("updated_at", o.UpdatedAt)
+		order.add("id", o.ID)
+		order.add("title", o.Title)
+		order.add("author", o.Author)
+		order.add("book_status", o.BookStatus)
+	}
+
+	// Send queries to database.
+	count, err := findMany(q, &books, "books", where, order, args.Skip, args.Take)
+	if err != nil {
+		// Return empty objects and error.
+		return []models.Book{}, 0, err
+	}
+
+	// Return query result.
+	return books, count, nil
+}
+
+// BookRepository struct for queries from Book model as repository of records.
+type BookRepository struct {
+	*BookQueries
+}
+
+// List method for getting all books of organization, or only books of the given user.
+func (r BookRepository) List(orgID, userID uuid.UUID) ([]models.Book, error) {
+	if userID != uuid.Nil {
+		return r.GetBooksByUser(orgID, userID)
+	}
+
+	return r.GetBooks(orgID)
+}
+
+// Get method for getting one book of organization by given ID.
+func (r BookRepository) Get(orgID, id uuid.UUID) (models.Book, error) {
+	return r.GetBook(orgID, id)
+}
+
+// GetForUpdate method for getting one book of organization by given ID and locking it.
+func (r BookRepository) GetForUpdate(orgID, id uuid.UUID) (models.Book, error) {
+	return r.GetBookForUpdate(orgID, id)
+}
+
+// Create method for creating book by given Book object.
+func (r BookRepository) Create(b *models.Book) error {
+	return r.CreateBook(b)
+}
+
+// Update method for updating book of its organization by given Book object.
+func (r BookRepository) Update(id uuid.UUID, b *models.Book) error {
+	return r.UpdateBook(id, b)
+}
+
+// Delete method for delete book of organization by given ID.
+func (r BookRepository) Delete(orgID, id uuid.UUID) error {
+	return r.DeleteBook(orgID, id)
+}
@@ -0,0 +1,157 @@
+package queries
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/koddr/tutorial-go-fiber-rest-api/app/models"
+)
+
+// DeviceCodeQueries struct for queries from DeviceCode model.
+type DeviceCodeQueries struct {
+	*sqlx.DB
+}
+
+// GetDeviceCodeByHash method for getting one device authorization request by hash of device code.
+func (q *DeviceCodeQueries) GetDeviceCodeByHash(hash string) (models.DeviceCode, error) {
+	// Define device code variable.
+	code := models.DeviceCode{}
+
+	// Define query string.
+	query := `SELECT * FROM device_codes WHERE device_code_hash = $1`
+
+	// Send query to database.
+	err := q.Get(&code, query, hash)
+	if err != nil {
+		// Return empty object and error.
+		return code, err
+	}
+
+	// Return query result.
+	return code, nil
+}
+
+// GetDeviceCodeByUserCode method for getting one device authorization request by user code.
+func (q *DeviceCodeQueries) GetDeviceCodeByUserCode(userCode string) (models.DeviceCode, error) {
+	// Define device code variable.
+	code := models.DeviceCode{}
+
+	// Define query string.
+	query := `SELECT * FROM device_codes WHERE user_code = $1`
+
+	// Send query to database.
+	err := q.Get(&code, query, userCode)
+	if err != nil {
+		// Return empty object and error.
+		return code, err
+	}
+
+	// Return query result.
+	return code, nil
+}
+
+// CreateDeviceCode method for creating device authorization request by given DeviceCode object.
+// Expired requests are deleted, so their user codes can be used again.
+func (q *DeviceCodeQueries) CreateDeviceCode(d *models.DeviceCode) error {
+	// Send queries to database.
+	if _, err := q.Exec(`DELETE FROM device_codes WHERE expires_at < $1`, d.CreatedAt); err != nil {
+		return err
+	}
+
+	// Define query string.
+	query := `INSERT INTO device_codes (id, created_at, expires_at, device_code_hash, user_code, client_id, status, poll_interval)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+
+	// Send query to database.
+	_, err := q.Exec(query, d.ID, d.CreatedAt, d.ExpiresAt, d.DeviceCodeHash, d.UserCode, d.ClientID, d.Status, d.PollInterval)
+	if err != nil {
+		// Return only error.
+		return err
+	}
+
+	// This query returns nothing.
+	return nil
+}
+
+// PollDeviceCode method for store time of the last poll of device and its interval.
+func (q *DeviceCodeQueries) PollDeviceCode(id uuid.UUID, polledAt time.Time, interval int) error {
+	// Define query string.
+	query := `UPDATE device_codes SET last_polled_at = $2, poll_interval = $3 WHERE id = $1`
+
+	// Send query to database.
+	_, err := q.Exec(query, id, polledAt, interval)
+	if err != nil {
+		// Return only error.
+		return err
+	}
+
+	// This query returns nothing.
+	return nil
+}
+
+// ApproveDeviceCode method for approve pending request by given user.
+// It returns false, if request is not pending anymore.
+func (q *DeviceCodeQueries) ApproveDeviceCode(id, userID uuid.UUID, twoFactor bool) (bool, error) {
+	// Define query string.
+	query := `UPDATE device_codes SET status = $2, user_id = $3, two_factor = $4 WHERE id = $1 AND status = $5`
+
+	// Send query to database.
+	result, err := q.Exec(query, id, models.DeviceCodeApproved, userID, twoFactor, models.DeviceCodePending)
+	if err != nil {
+		// Return only error.
+		return false, err
+	}
+
+	// Checking, if request was approved by this query.
+	count, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return count == 1, nil
+}
+
+// DenyDeviceCode method for deny pending request.
+// It returns false, if request is not pending anymore.
+func (q *DeviceCodeQueries) DenyDeviceCode(id uuid.UUID) (bool, error) {
+	// Define query string.
+	query := `UPDATE device_codes SET status = $2 WHERE id = $1 AND status = $3`
+
+	// Send query to database.
+	result, err := q.Exec(query, id, models.DeviceCodeDenied, models.DeviceCodePending)
+	if err != nil {
+		// Return only error.
+		return false, err
+	}
+
+	// Checking, if request was denied by this query.
+	count, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return count == 1, nil
+}
+
+// ConsumeDeviceCode method for mark approved request as used, so tokens are issued only once.
+// It returns false, if request was already used.
+func (q *DeviceCodeQueries) ConsumeDeviceCode(id uuid.UUID) (bool, error) {
+	// Define query string.
+	query := `UPDATE device_codes SET status = $2 WHERE id = $1 AND status = $3`
+
+	// Send query to database.
+	result, err := q.Exec(query, id, models.DeviceCodeConsumed, models.DeviceCodeApproved)
+	if err != nil {
+		// Return only error.
+		return false, err
+	}
+
+	// Checking, if request was used by this query.
+	count, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return count == 1, nil
+}
@@ -0,0 +1,12 @@
+package queries
+
+import "database/sql"
+
+// Executor interface to describe connection to database, which queries of
+// tenant records are sent to: a pool of connections, a transaction, or
+// a connection with row-level security of one user (see database.TenantExecutor).
+type Executor interface {
+	Get(dest interface{}, query string, args ...interface{}) error
+	Select(dest interface{}, query string, args ...interface{}) error
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
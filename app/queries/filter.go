@@ -0,0 +1,197 @@
+package queries
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/koddr/tutorial-go-fiber-rest-api/app/models"
+)
+
+// whereBuilder struct for build WHERE clause of query from filters of WhereInput
+// types. Values are always passed as numbered arguments, columns are given by code.
+type whereBuilder struct {
+	conditions []string
+	args       []interface{}
+}
+
+// arg method for add argument of query and get its placeholder.
+func (w *whereBuilder) arg(value interface{}) string {
+	w.args = append(w.args, value)
+	return "$" + strconv.Itoa(len(w.args))
+}
+
+// add method for add condition, all conditions must be true.
+func (w *whereBuilder) add(condition string) {
+	w.conditions = append(w.conditions, condition)
+}
+
+// stringFilter method for add conditions of StringFilter for the given column.
+func (w *whereBuilder) stringFilter(column string, f *models.StringFilter) {
+	if f == nil {
+		return
+	}
+
+	// Insensitive mode compares lower case of column and values.
+	like := "LIKE"
+	value := w.arg
+	if f.Mode == models.InsensitiveMode {
+		column = "LOWER (" + column + ")"
+		like = "ILIKE"
+		value = func(v interface{}) string { return "LOWER (" + w.arg(v) + ")" }
+	}
+
+	if f.Equals != nil {
+		w.add(column + " = " + value(*f.Equals))
+	}
+	if f.In != nil {
+		w.add(column + " IN (" + w.list(strs(f.In), value) + ")")
+	}
+	if len(f.NotIn) > 0 {
+		w.add(column + " NOT IN (" + w.list(strs(f.NotIn), value) + ")")
+	}
+	if f.Lt != nil {
+		w.add(column + " < " + value(*f.Lt))
+	}
+	if f.Lte != nil {
+		w.add(column + " <= " + value(*f.Lte))
+	}
+	if f.Gt != nil {
+		w.add(column + " > " + value(*f.Gt))
+	}
+	if f.Gte != nil {
+		w.add(column + " >= " + value(*f.Gte))
+	}
+	if f.Contains != nil {
+		w.add(column + " " + like + " " + w.arg("%"+escapeLike(*f.Contains)+"%"))
+	}
+	if f.StartsWith != nil {
+		w.add(column + " " + like + " " + w.arg(escapeLike(*f.StartsWith)+"%"))
+	}
+	if f.EndsWith != nil {
+		w.add(column + " " + like + " " + w.arg("%"+escapeLike(*f.EndsWith)))
+	}
+	if f.Not != nil {
+		w.add(column + " <> " + value(*f.Not))
+	}
+}
+
+// intFilter method for add conditions of IntFilter for the given column.
+func (w *whereBuilder) intFilter(column string, f *models.IntFilter) {
+	if f == nil {
+		return
+	}
+
+	if f.Equals != nil {
+		w.add(column + " = " + w.arg(*f.Equals))
+	}
+	if f.In != nil {
+		w.add(column + " IN (" + w.list(ints(f.In), w.arg) + ")")
+	}
+	if len(f.NotIn) > 0 {
+		w.add(column + " NOT IN (" + w.list(ints(f.NotIn), w.arg) + ")")
+	}
+	if f.Lt != nil {
+		w.add(column + " < " + w.arg(*f.Lt))
+	}
+	if f.Lte != nil {
+		w.add(column + " <= " + w.arg(*f.Lte))
+	}
+	if f.Gt != nil {
+		w.add(column + " > " + w.arg(*f.Gt))
+	}
+	if f.Gte != nil {
+		w.add(column + " >= " + w.arg(*f.Gte))
+	}
+	if f.Not != nil {
+		w.add(column + " <> " + w.arg(*f.Not))
+	}
+}
+
+// list method for get placeholders of the given values, "NULL" for no values
+// (nothing is IN empty list).
+func (w *whereBuilder) list(values []interface{}, value func(interface{}) string) string {
+	if len(values) == 0 {
+		return "NULL"
+	}
+
+	placeholders := make([]string, len(values))
+	for i, v := range values {
+		placeholders[i] = value(v)
+	}
+
+	return strings.Join(placeholders, ", ")
+}
+
+// String method for get WHERE clause, or empty string without conditions.
+func (w *whereBuilder) String() string {
+	if len(w.conditions) == 0 {
+		return ""
+	}
+
+	return " WHERE " + strings.Join(w.conditions, " AND ")
+}
+
+// orderBuilder struct for build ORDER BY clause from OrderByInput types.
+type orderBuilder struct {
+	terms []string
+}
+
+// add method for sort by the given column, if sort order is given.
+func (o *orderBuilder) add(column, order string) {
+	switch order {
+	case models.SortAsc:
+		o.terms = append(o.terms, column)
+	case models.SortDesc:
+		o.terms = append(o.terms, column+" DESC")
+	}
+}
+
+// String method for get ORDER BY clause. Rows are sorted by the given unique
+// column at last, so pages don't overlap.
+func (o *orderBuilder) String(unique string) string {
+	return " ORDER BY " + strings.Join(append(o.terms, unique), ", ")
+}
+
+// findMany func for select page of rows of table to dest by the given filter
+// and sort orders, and count all rows of filter.
+func findMany(q Executor, dest interface{}, table string, where *whereBuilder, order *orderBuilder, skip, take int) (int, error) {
+	// Define query strings.
+	countQuery := `SELECT COUNT (*) FROM ` + table + where.String()
+	countArgs := where.args
+	query := `SELECT * FROM ` + table + where.String() + order.String("id") +
+		` LIMIT ` + where.arg(take) + ` OFFSET ` + where.arg(skip)
+
+	// Send queries to database.
+	count := 0
+	if err := q.Get(&count, countQuery, countArgs...); err != nil {
+		return 0, err
+	}
+	if err := q.Select(dest, query, where.args...); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func strs(values []string) []interface{} {
+	result := make([]interface{}, len(values))
+	for i, v := range values {
+		result[i] = v
+	}
+
+	return result
+}
+
+func ints(values []int) []interface{} {
+	result := make([]interface{}, len(values))
+	for i, v := range values {
+		result[i] = v
+	}
+
+	return result
+}
+
+// escapeLike func for escape wildcards of LIKE pattern in the given value.
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
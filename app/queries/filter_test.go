@@ -0,0 +1,38 @@
+package queries
+
+import (
+	"testing"
+
+	"github.com/koddr/tutorial-go-fiber-rest-api/app/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWhereBuilder(t *testing.T) {
+	value := func(s string) *string { return &s }
+
+	where := &whereBuilder{}
+	assert.Equal(t, "", where.String())
+
+	where.stringFilter("username", &models.StringFilter{
+		Equals:   value("john"),
+		NotIn:    []string{"admin", "root"},
+		Contains: value("50%_off"),
+	})
+	where.stringFilter("first_name", &models.StringFilter{StartsWith: value("Jo"), Mode: models.InsensitiveMode})
+	where.stringFilter("last_name", &models.StringFilter{In: []string{}})
+	where.stringFilter("id::TEXT", nil)
+
+	assert.Equal(t, " WHERE username = $1 AND username NOT IN ($2, $3) AND username LIKE $4"+
+		" AND LOWER (first_name) ILIKE $5 AND last_name IN (NULL)", where.String())
+	assert.Equal(t, []interface{}{"john", "admin", "root", `%50\%\_off%`, "Jo%"}, where.args)
+}
+
+func TestOrderBuilder(t *testing.T) {
+	order := &orderBuilder{}
+	assert.Equal(t, " ORDER BY id", order.String("id"))
+
+	order.add("username", models.SortDesc)
+	order.add("first_name", "")
+	order.add("created_at", models.SortAsc)
+	assert.Equal(t, " ORDER BY username DESC, created_at, id", order.String("id"))
+}
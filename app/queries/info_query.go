@@ -2,25 +2,24 @@ package queries
 
 import (
 	"github.com/google/uuid"
-	"github.com/jmoiron/sqlx"
 	"github.com/koddr/tutorial-go-fiber-rest-api/app/models"
 )
 
 // InfoQueries struct for queries from Info model.
 type InfoQueries struct {
-	*sqlx.DB
+	Executor
 }
 
-// GetInfo method for getting all Info.
-func (q *InfoQueries) GetAllInfo() ([]models.Info, error) {
+// GetAllInfo method for getting all Info of the given organization.
+func (q *InfoQueries) GetAllInfo(orgID uuid.UUID) ([]models.Info, error) {
 	// Define Info variable.
 	Info := []models.Info{}
 
 	// Define query string.
-	query := `SELECT * FROM Info`
+	query := `SELECT * FROM info WHERE org_id = $1`
 
 	// Send query to database.
-	err := q.Get(&Info, query)
+	err := q.Select(&Info, query, orgID)
 	if err != nil {
 		// Return empty object and error.
 		return Info, err
@@ -30,16 +29,55 @@ func (q *InfoQueries) GetAllInfo() ([]models.Info, error) {
 	return Info, nil
 }
 
-// GetInfo method for getting one Info by given ID.
-func (q *InfoQueries) GetInfo(id uuid.UUID) (models.Info, error) {
+// GetAllInfoByUser method for getting all Info of the given user in organization.
+func (q *InfoQueries) GetAllInfoByUser(orgID, userID uuid.UUID) ([]models.Info, error) {
+	// Define Info variable.
+	Info := []models.Info{}
+
+	// Define query string.
+	query := `SELECT * FROM info WHERE org_id = $1 AND user_id = $2`
+
+	// Send query to database.
+	err := q.Select(&Info, query, orgID, userID)
+	if err != nil {
+		// Return empty object and error.
+		return Info, err
+	}
+
+	// Return query result.
+	return Info, nil
+}
+
+// GetInfo method for getting one Info of organization by given ID.
+func (q *InfoQueries) GetInfo(orgID, id uuid.UUID) (models.Info, error) {
 	// Define Info variable.
 	Info := models.Info{}
 
 	// Define query string.
-	query := `SELECT * FROM Info WHERE id = $1`
+	query := `SELECT * FROM info WHERE org_id = $1 AND id = $2`
 
 	// Send query to database.
-	err := q.Get(&Info, query, id)
+	err := q.Get(&Info, query, orgID, id)
+	if err != nil {
+		// Return empty object and error.
+		return Info, err
+	}
+
+	// Return query result.
+	return Info, nil
+}
+
+// GetInfoForUpdate method for getting one Info of organization by given ID
+// and locking it until the end of transaction, so it's changed by one request at a time.
+func (q *InfoQueries) GetInfoForUpdate(orgID, id uuid.UUID) (models.Info, error) {
+	// Define Info variable.
+	Info := models.Info{}
+
+	// Define query string.
+	query := `SELECT * FROM info WHERE org_id = $1 AND id = $2 FOR UPDATE`
+
+	// Send query to database.
+	err := q.Get(&Info, query, orgID, id)
 	if err != nil {
 		// Return empty object and error.
 		return Info, err
@@ -52,10 +90,10 @@ func (q *InfoQueries) GetInfo(id uuid.UUID) (models.Info, error) {
 // CreateInfo method for creating Info by given Info object.
 func (q *InfoQueries) CreateInfo(b *models.Info) error {
 	// Define query string.
-	query := `INSERT INTO Info VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+	query := `INSERT INTO info (id, created_at, updated_at, user_id, org_id, name, portfolio, info_status, info_attrs) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
 
 	// Send query to database.
-	_, err := q.Exec(query, b.ID, b.CreatedAt, b.UpdatedAt, b.UserID, b.Name, b.Portfolio, b.InfoStatus, b.InfoAttrs)
+	_, err := q.Exec(query, b.ID, b.CreatedAt, b.UpdatedAt, b.UserID, b.OrgID, b.Name, b.Portfolio, b.InfoStatus, b.InfoAttrs)
 	if err != nil {
 		// Return only error.
 		return err
@@ -65,13 +103,13 @@ func (q *InfoQueries) CreateInfo(b *models.Info) error {
 	return nil
 }
 
-// UpdateInfo method for updating Info by given Info object.
+// UpdateInfo method for updating Info of its organization by given Info object.
 func (q *InfoQueries) UpdateInfo(id uuid.UUID, b *models.Info) error {
 	// Define query string.
-	query := `UPDATE Info SET updated_at = $2, title = $3, author = $4, Info_status = $5, Info_attrs = $6 WHERE id = $1`
+	query := `UPDATE info SET updated_at = $2, name = $3, portfolio = $4, info_status = $5, info_attrs = $6 WHERE id = $1 AND org_id = $7`
 
 	// Send query to database.
-	_, err := q.Exec(query, id, b.UpdatedAt, b.UserID, b.Name, b.InfoStatus, b.InfoAttrs)
+	_, err := q.Exec(query, id, b.UpdatedAt, b.Name, b.Portfolio, b.InfoStatus, b.InfoAttrs, b.OrgID)
 	if err != nil {
 		// Return only error.
 		return err
@@ -81,13 +119,13 @@ func (q *InfoQueries) UpdateInfo(id uuid.UUID, b *models.Info) error {
 	return nil
 }
 
-// DeleteInfo method for delete Info by given ID.
-func (q *InfoQueries) DeleteInfo(id uuid.UUID) error {
+// DeleteInfo method for delete Info of organization by given ID.
+func (q *InfoQueries) DeleteInfo(orgID, id uuid.UUID) error {
 	// Define query string.
-	query := `DELETE FROM Info WHERE id = $1`
+	query := `DELETE FROM info WHERE org_id = $1 AND id = $2`
 
 	// Send query to database.
-	_, err := q.Exec(query, id)
+	_, err := q.Exec(query, orgID, id)
 	if err != nil {
 		// Return only error.
 		return err
@@ -96,3 +134,79 @@ func (q *InfoQueries) DeleteInfo(id uuid.UUID) error {
 	// This query returns nothing.
 	return nil
 }
+
+// FindInfo method for getting page of Info of the given organization by given
+// filter, sort orders, skip and take, and count of all Info of filter.
+func (q *InfoQueries) FindInfo(orgID uuid.UUID, args *models.InfoFindManyArgs) ([]models.Info, int, error) {
+	// Define Info variable.
+	info := []models.Info{}
+
+	// Define filter of Info.
+	where := &whereBuilder{}
+	where.add("org_id = " + where.arg(orgID))
+	where.stringFilter("id::TEXT", args.Where.ID)
+	where.stringFilter("user_id::TEXT", args.Where.UserID)
+	where.stringFilter("name", args.Where.Name)
+	where.stringFilter("portfolio", args.Where.Portfolio)
+	where.intFilter("info_status", args.Where.InfoStatus)
+
+	// Define sort orders of Info.
+	order := &orderBuilder{}
+	for _, o := range args.OrderBy {
+		order.add("created_at", o.CreatedAt)
+		order.add("updated_at", o.UpdatedAt)
+		order.add("id", o.ID)
+		order.add("name", o.Name)
+		order.add("portfolio", o.Portfolio)
+		order.add("info_status", o.InfoStatus)
+	}
+
+	// Send queries to database.
+	count, err := findMany(q, &info, "info", where, order, args.Skip, args.Take)
+	if err != nil {
+		// Return empty objects and error.
+		return []models.Info{}, 0, err
+	}
+
+	// Return query result.
+	return info, count, nil
+}
+
+// InfoRepository struct for queries from Info model as repository of records.
+type InfoRepository struct {
+	*InfoQueries
+}
+
+// List method for getting all Info of organization, or only Info of the given user.
+func (r InfoRepository) List(orgID, userID uuid.UUID) ([]models.Info, error) {
+	if userID != uuid.Nil {
+		return r.GetAllInfoByUser(orgID, userID)
+	}
+
+	return r.GetAllInfo(orgID)
+}
+
+// Get method for getting one Info of organization by given ID.
+func (r InfoRepository) Get(orgID, id uuid.UUID) (models.Info, error) {
+	return r.GetInfo(orgID, id)
+}
+
+// GetForUpdate method for getting one Info of organization by given ID and locking it.
+func (r InfoRepository) GetForUpdate(orgID, id uuid.UUID) (models.Info, error) {
+	return r.GetInfoForUpdate(orgID, id)
+}
+
+// Create method for creating Info by given Info object.
+func (r InfoRepository) Create(b *models.Info) error {
+	return r.CreateInfo(b)
+}
+
+// Update method for updating Info of its organization by given Info object.
+func (r InfoRepository) Update(id uuid.UUID, b *models.Info) error {
+	return r.UpdateInfo(id, b)
+}
+
+// Delete method for delete Info of organization by given ID.
+func (r InfoRepository) Delete(orgID, id uuid.UUID) error {
+	return r.DeleteInfo(orgID, id)
+}
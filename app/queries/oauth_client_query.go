@@ -0,0 +1,109 @@
+package queries
+
+import (
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/koddr/tutorial-go-fiber-rest-api/app/models"
+)
+
+// OAuthClientQueries struct for queries from OAuthClient model.
+type OAuthClientQueries struct {
+	*sqlx.DB
+}
+
+// GetOAuthClients method for getting all OAuth clients.
+func (q *OAuthClientQueries) GetOAuthClients() ([]models.OAuthClient, error) {
+	// Define OAuth clients variable.
+	clients := []models.OAuthClient{}
+
+	// Define query string.
+	query := `SELECT * FROM oauth_clients ORDER BY created_at DESC`
+
+	// Send query to database.
+	err := q.Select(&clients, query)
+	if err != nil {
+		// Return empty object and error.
+		return clients, err
+	}
+
+	// Return query result.
+	return clients, nil
+}
+
+// GetOAuthClient method for getting one OAuth client by given ID.
+func (q *OAuthClientQueries) GetOAuthClient(id uuid.UUID) (models.OAuthClient, error) {
+	// Define OAuth client variable.
+	client := models.OAuthClient{}
+
+	// Define query string.
+	query := `SELECT * FROM oauth_clients WHERE id = $1`
+
+	// Send query to database.
+	err := q.Get(&client, query, id)
+	if err != nil {
+		// Return empty object and error.
+		return client, err
+	}
+
+	// Return query result.
+	return client, nil
+}
+
+// GetOAuthClientByClientID method for getting one OAuth client by given client ID.
+func (q *OAuthClientQueries) GetOAuthClientByClientID(clientID string) (models.OAuthClient, error) {
+	// Define OAuth client variable.
+	client := models.OAuthClient{}
+
+	// Define query string.
+	query := `SELECT * FROM oauth_clients WHERE client_id = $1`
+
+	// Send query to database.
+	err := q.Get(&client, query, clientID)
+	if err != nil {
+		// Return empty object and error.
+		return client, err
+	}
+
+	// Return query result.
+	return client, nil
+}
+
+// CreateOAuthClient method for creating OAuth client by given OAuthClient object
+// with its service account in one transaction (see CreateServiceAccount).
+func (q *OAuthClientQueries) CreateOAuthClient(c *models.OAuthClient, a *models.ServiceAccount, organizationID uuid.UUID, role string) error {
+	// Begin a new transaction.
+	tx, err := q.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Define query string.
+	query := `INSERT INTO oauth_clients (id, created_at, client_id, secret_hash, name, scopes, roles, user_id) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+
+	// Send queries to database.
+	if err := createServiceAccount(tx, a, c.Roles, organizationID, role); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(query, c.ID, c.CreatedAt, c.ClientID, c.SecretHash, c.Name, c.Scopes, c.Roles, c.UserID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// RevokeOAuthClient method for revoke OAuth client by given ID.
+func (q *OAuthClientQueries) RevokeOAuthClient(id uuid.UUID) error {
+	// Define query string.
+	query := `UPDATE oauth_clients SET revoked_at = NOW () WHERE id = $1 AND revoked_at IS NULL`
+
+	// Send query to database.
+	_, err := q.Exec(query, id)
+	if err != nil {
+		// Return only error.
+		return err
+	}
+
+	// This query returns nothing.
+	return nil
+}
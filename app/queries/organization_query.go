@@ -0,0 +1,243 @@
+package queries
+
+import (
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/koddr/tutorial-go-fiber-rest-api/app/models"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/repository"
+)
+
+// OrganizationQueries struct for queries from Organization and Membership models.
+type OrganizationQueries struct {
+	*sqlx.DB
+}
+
+// GetOrganizationsByUser method for getting all organizations of the given user with roles of user.
+func (q *OrganizationQueries) GetOrganizationsByUser(userID uuid.UUID) ([]models.Organization, error) {
+	// Define organizations variable.
+	organizations := []models.Organization{}
+
+	// Define query string.
+	query := `SELECT organizations.*, memberships.role FROM organizations
+		JOIN memberships ON memberships.organization_id = organizations.id
+		WHERE memberships.user_id = $1 ORDER BY memberships.created_at`
+
+	// Send query to database.
+	err := q.Select(&organizations, query, userID)
+	if err != nil {
+		// Return empty object and error.
+		return organizations, err
+	}
+
+	// Return query result.
+	return organizations, nil
+}
+
+// GetOrganization method for getting one organization by given ID.
+func (q *OrganizationQueries) GetOrganization(id uuid.UUID) (models.Organization, error) {
+	// Define organization variable.
+	organization := models.Organization{}
+
+	// Define query string.
+	query := `SELECT * FROM organizations WHERE id = $1`
+
+	// Send query to database.
+	err := q.Get(&organization, query, id)
+	if err != nil {
+		// Return empty object and error.
+		return organization, err
+	}
+
+	// Return query result.
+	return organization, nil
+}
+
+// CreateOrganization method for creating organization with the given owner in one transaction.
+func (q *OrganizationQueries) CreateOrganization(o *models.Organization, ownerID uuid.UUID) error {
+	// Begin a new transaction.
+	tx, err := q.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Define query strings.
+	organizationQuery := `INSERT INTO organizations (id, created_at, name) VALUES ($1, $2, $3)`
+	membershipQuery := `INSERT INTO memberships (organization_id, user_id, role, created_at) VALUES ($1, $2, $3, $4)`
+
+	// Send queries to database.
+	if _, err := tx.Exec(organizationQuery, o.ID, o.CreatedAt, o.Name); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(membershipQuery, o.ID, ownerID, repository.OrganizationOwnerRole, o.CreatedAt); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// UpdateOrganization method for updating name of organization by given ID.
+func (q *OrganizationQueries) UpdateOrganization(id uuid.UUID, name string) error {
+	// Define query string.
+	query := `UPDATE organizations SET updated_at = NOW (), name = $2 WHERE id = $1`
+
+	// Send query to database.
+	_, err := q.Exec(query, id, name)
+	if err != nil {
+		// Return only error.
+		return err
+	}
+
+	// This query returns nothing.
+	return nil
+}
+
+// DeleteOrganization method for delete organization by given ID with its memberships and records.
+func (q *OrganizationQueries) DeleteOrganization(id uuid.UUID) error {
+	// Define query string.
+	query := `DELETE FROM organizations WHERE id = $1`
+
+	// Send query to database.
+	_, err := q.Exec(query, id)
+	if err != nil {
+		// Return only error.
+		return err
+	}
+
+	// This query returns nothing.
+	return nil
+}
+
+// GetMembership method for getting role of user in organization.
+func (q *OrganizationQueries) GetMembership(organizationID, userID uuid.UUID) (models.Membership, error) {
+	// Define membership variable.
+	membership := models.Membership{}
+
+	// Define query string.
+	query := `SELECT * FROM memberships WHERE organization_id = $1 AND user_id = $2`
+
+	// Send query to database.
+	err := q.Get(&membership, query, organizationID, userID)
+	if err != nil {
+		// Return empty object and error.
+		return membership, err
+	}
+
+	// Return query result.
+	return membership, nil
+}
+
+// GetDefaultMembership method for getting the oldest membership of user,
+// it's membership of personal organization, unless user left it.
+func (q *OrganizationQueries) GetDefaultMembership(userID uuid.UUID) (models.Membership, error) {
+	// Define membership variable.
+	membership := models.Membership{}
+
+	// Define query string.
+	query := `SELECT * FROM memberships WHERE user_id = $1 ORDER BY created_at, organization_id LIMIT 1`
+
+	// Send query to database.
+	err := q.Get(&membership, query, userID)
+	if err != nil {
+		// Return empty object and error.
+		return membership, err
+	}
+
+	// Return query result.
+	return membership, nil
+}
+
+// GetMembers method for getting all members of organization with their usernames.
+func (q *OrganizationQueries) GetMembers(organizationID uuid.UUID) ([]models.Membership, error) {
+	// Define members variable.
+	members := []models.Membership{}
+
+	// Define query string.
+	query := `SELECT memberships.*, users.username FROM memberships
+		JOIN users ON users.id = memberships.user_id
+		WHERE memberships.organization_id = $1 ORDER BY memberships.created_at`
+
+	// Send query to database.
+	err := q.Select(&members, query, organizationID)
+	if err != nil {
+		// Return empty object and error.
+		return members, err
+	}
+
+	// Return query result.
+	return members, nil
+}
+
+// CreateMembership method for adding user to organization. Role of existing
+// member is not changed. It returns false, if user is already a member.
+func (q *OrganizationQueries) CreateMembership(m *models.Membership) (bool, error) {
+	// Define query string.
+	query := `INSERT INTO memberships (organization_id, user_id, role, created_at) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (organization_id, user_id) DO NOTHING`
+
+	// Send query to database.
+	result, err := q.Exec(query, m.OrganizationID, m.UserID, m.Role, m.CreatedAt)
+	if err != nil {
+		// Return only error.
+		return false, err
+	}
+
+	// Checking, if user was added by this query.
+	count, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return count == 1, nil
+}
+
+// UpdateMembership method for updating role of member.
+func (q *OrganizationQueries) UpdateMembership(organizationID, userID uuid.UUID, role string) error {
+	// Define query string.
+	query := `UPDATE memberships SET role = $3 WHERE organization_id = $1 AND user_id = $2`
+
+	// Send query to database.
+	_, err := q.Exec(query, organizationID, userID, role)
+	if err != nil {
+		// Return only error.
+		return err
+	}
+
+	// This query returns nothing.
+	return nil
+}
+
+// DeleteMembership method for removing member from organization.
+func (q *OrganizationQueries) DeleteMembership(organizationID, userID uuid.UUID) error {
+	// Define query string.
+	query := `DELETE FROM memberships WHERE organization_id = $1 AND user_id = $2`
+
+	// Send query to database.
+	_, err := q.Exec(query, organizationID, userID)
+	if err != nil {
+		// Return only error.
+		return err
+	}
+
+	// This query returns nothing.
+	return nil
+}
+
+// CountOwners method for getting number of owners of organization.
+func (q *OrganizationQueries) CountOwners(organizationID uuid.UUID) (int, error) {
+	// Define count variable.
+	count := 0
+
+	// Define query string.
+	query := `SELECT COUNT (*) FROM memberships WHERE organization_id = $1 AND role = $2`
+
+	// Send query to database.
+	err := q.Get(&count, query, organizationID, repository.OrganizationOwnerRole)
+	if err != nil {
+		// Return zero and error.
+		return 0, err
+	}
+
+	// Return query result.
+	return count, nil
+}
@@ -0,0 +1,98 @@
+package queries
+
+import (
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/koddr/tutorial-go-fiber-rest-api/app/models"
+)
+
+// ProfileQueries struct for queries from Profile model.
+type ProfileQueries struct {
+	*sqlx.DB
+}
+
+// GetProfilesByUser method for getting all profiles of the given user.
+func (q *ProfileQueries) GetProfilesByUser(userID uuid.UUID) ([]models.Profile, error) {
+	// Define profiles variable.
+	profiles := []models.Profile{}
+
+	// Define query string.
+	query := `SELECT * FROM profiles WHERE user_id = $1 ORDER BY name`
+
+	// Send query to database.
+	err := q.Select(&profiles, query, userID)
+	if err != nil {
+		// Return empty object and error.
+		return profiles, err
+	}
+
+	// Return query result.
+	return profiles, nil
+}
+
+// GetProfile method for getting one profile by given ID.
+func (q *ProfileQueries) GetProfile(id uuid.UUID) (models.Profile, error) {
+	// Define profile variable.
+	profile := models.Profile{}
+
+	// Define query string.
+	query := `SELECT * FROM profiles WHERE id = $1`
+
+	// Send query to database.
+	err := q.Get(&profile, query, id)
+	if err != nil {
+		// Return empty object and error.
+		return profile, err
+	}
+
+	// Return query result.
+	return profile, nil
+}
+
+// CreateProfile method for creating profile by given Profile object.
+func (q *ProfileQueries) CreateProfile(p *models.Profile) error {
+	// Define query string.
+	query := `INSERT INTO profiles VALUES ($1, $2, $3, $4, $5, $6)`
+
+	// Send query to database.
+	_, err := q.Exec(query, p.ID, p.CreatedAt, p.UpdatedAt, p.UserID, p.Name, p.ProfileOptions)
+	if err != nil {
+		// Return only error.
+		return err
+	}
+
+	// This query returns nothing.
+	return nil
+}
+
+// UpdateProfile method for updating profile by given Profile object.
+func (q *ProfileQueries) UpdateProfile(id uuid.UUID, p *models.Profile) error {
+	// Define query string.
+	query := `UPDATE profiles SET updated_at = $2, name = $3, profile_options = $4 WHERE id = $1`
+
+	// Send query to database.
+	_, err := q.Exec(query, id, p.UpdatedAt, p.Name, p.ProfileOptions)
+	if err != nil {
+		// Return only error.
+		return err
+	}
+
+	// This query returns nothing.
+	return nil
+}
+
+// DeleteProfile method for delete profile by given ID.
+func (q *ProfileQueries) DeleteProfile(id uuid.UUID) error {
+	// Define query string.
+	query := `DELETE FROM profiles WHERE id = $1`
+
+	// Send query to database.
+	_, err := q.Exec(query, id)
+	if err != nil {
+		// Return only error.
+		return err
+	}
+
+	// This query returns nothing.
+	return nil
+}
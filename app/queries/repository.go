@@ -0,0 +1,18 @@
+package queries
+
+import "github.com/google/uuid"
+
+// Repository interface to describe queries of records of organization, like
+// books, which REST API of records needs (see controllers.Resource).
+type Repository[T any] interface {
+	// List returns all records of organization, or only records of the given
+	// user, if it's not uuid.Nil.
+	List(orgID, userID uuid.UUID) ([]T, error)
+	Get(orgID, id uuid.UUID) (T, error)
+	// GetForUpdate returns record like Get and locks it until the end of
+	// transaction (see database.InTenantTransaction).
+	GetForUpdate(orgID, id uuid.UUID) (T, error)
+	Create(record *T) error
+	Update(id uuid.UUID, record *T) error
+	Delete(orgID, id uuid.UUID) error
+}
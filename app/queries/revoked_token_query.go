@@ -0,0 +1,64 @@
+package queries
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/koddr/tutorial-go-fiber-rest-api/app/models"
+)
+
+// RevokedTokenQueries struct for queries from RevokedToken model.
+type RevokedTokenQueries struct {
+	*sqlx.DB
+}
+
+// GetRevokedTokens method for getting all revoked tokens, which are not expired yet.
+func (q *RevokedTokenQueries) GetRevokedTokens() ([]models.RevokedToken, error) {
+	// Define revoked tokens variable.
+	tokens := []models.RevokedToken{}
+
+	// Define query string.
+	query := `SELECT * FROM revoked_tokens WHERE expires_at > NOW ()`
+
+	// Send query to database.
+	err := q.Select(&tokens, query)
+	if err != nil {
+		// Return empty object and error.
+		return tokens, err
+	}
+
+	// Return query result.
+	return tokens, nil
+}
+
+// CreateRevokedToken method for creating revoked token by given RevokedToken object.
+// Token, which is already revoked, is not changed.
+func (q *RevokedTokenQueries) CreateRevokedToken(t *models.RevokedToken) error {
+	// Define query string.
+	query := `INSERT INTO revoked_tokens (token_id, revoked_at, expires_at, user_id) VALUES ($1, $2, $3, $4) ON CONFLICT (token_id) DO NOTHING`
+
+	// Send query to database.
+	_, err := q.Exec(query, t.TokenID, t.RevokedAt, t.ExpiresAt, t.UserID)
+	if err != nil {
+		// Return only error.
+		return err
+	}
+
+	// This query returns nothing.
+	return nil
+}
+
+// DeleteExpiredRevokedTokens method for deleting revoked tokens, which are expired
+// already, so they are rejected by expiration anyway.
+func (q *RevokedTokenQueries) DeleteExpiredRevokedTokens() error {
+	// Define query string.
+	query := `DELETE FROM revoked_tokens WHERE expires_at <= NOW ()`
+
+	// Send query to database.
+	_, err := q.Exec(query)
+	if err != nil {
+		// Return only error.
+		return err
+	}
+
+	// This query returns nothing.
+	return nil
+}
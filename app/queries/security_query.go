@@ -0,0 +1,142 @@
+package queries
+
+import (
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/koddr/tutorial-go-fiber-rest-api/app/models"
+)
+
+// SecurityQueries struct for queries from LoginAttempt and SecurityEvent models.
+type SecurityQueries struct {
+	*sqlx.DB
+}
+
+// GetLoginAttempt method for getting failed attempts by given key.
+func (q *SecurityQueries) GetLoginAttempt(key string) (models.LoginAttempt, error) {
+	// Define login attempt variable.
+	attempt := models.LoginAttempt{}
+
+	// Define query string.
+	query := `SELECT * FROM login_attempts WHERE key = $1`
+
+	// Send query to database.
+	err := q.Get(&attempt, query, key)
+	if err != nil {
+		// Return empty object and error.
+		return attempt, err
+	}
+
+	// Return query result.
+	return attempt, nil
+}
+
+// GetLockedLoginAttempts method for getting all keys, which are locked now.
+func (q *SecurityQueries) GetLockedLoginAttempts() ([]models.LoginAttempt, error) {
+	// Define login attempts variable.
+	attempts := []models.LoginAttempt{}
+
+	// Define query string.
+	query := `SELECT * FROM login_attempts WHERE locked_until > NOW () ORDER BY locked_until DESC`
+
+	// Send query to database.
+	err := q.Select(&attempts, query)
+	if err != nil {
+		// Return empty object and error.
+		return attempts, err
+	}
+
+	// Return query result.
+	return attempts, nil
+}
+
+// RecordLoginFailure method for count a failed attempt of given key and lock it
+// until the given time. Failures before windowStart are forgotten.
+// It returns count of failures.
+func (q *SecurityQueries) RecordLoginFailure(key string, now, windowStart time.Time) (int, error) {
+	// Define failures variable.
+	failures := 0
+
+	// Define query string.
+	query := `INSERT INTO login_attempts AS a (key, failures, last_failure_at) VALUES ($1, 1, $2)
+		ON CONFLICT (key) DO UPDATE SET
+			failures = CASE WHEN a.last_failure_at < $3 THEN 1 ELSE a.failures + 1 END,
+			last_failure_at = $2
+		RETURNING failures`
+
+	// Send query to database.
+	err := q.Get(&failures, query, key, now, windowStart)
+	if err != nil {
+		// Return only error.
+		return 0, err
+	}
+
+	return failures, nil
+}
+
+// LockLogin method for lock sign in of given key until the given time.
+func (q *SecurityQueries) LockLogin(key string, until time.Time) error {
+	// Define query string.
+	query := `UPDATE login_attempts SET locked_until = $2 WHERE key = $1`
+
+	// Send query to database.
+	_, err := q.Exec(query, key, until)
+	if err != nil {
+		// Return only error.
+		return err
+	}
+
+	// This query returns nothing.
+	return nil
+}
+
+// ResetLoginAttempts method for forget failed attempts and lock of given key.
+func (q *SecurityQueries) ResetLoginAttempts(key string) error {
+	// Define query string.
+	query := `DELETE FROM login_attempts WHERE key = $1`
+
+	// Send query to database.
+	_, err := q.Exec(query, key)
+	if err != nil {
+		// Return only error.
+		return err
+	}
+
+	// This query returns nothing.
+	return nil
+}
+
+// GetSecurityEvents method for getting the latest security events, optionally of given username.
+func (q *SecurityQueries) GetSecurityEvents(username string, limit, offset int) ([]models.SecurityEvent, error) {
+	// Define security events variable.
+	events := []models.SecurityEvent{}
+
+	// Define query string.
+	query := `SELECT * FROM security_events WHERE ($1 = '' OR username = $1) ORDER BY created_at DESC LIMIT $2 OFFSET $3`
+
+	// Send query to database.
+	err := q.Select(&events, query, username, limit, offset)
+	if err != nil {
+		// Return empty object and error.
+		return events, err
+	}
+
+	// Return query result.
+	return events, nil
+}
+
+// CreateSecurityEvent method for creating security event by given SecurityEvent object.
+func (q *SecurityQueries) CreateSecurityEvent(e *models.SecurityEvent) error {
+	// Define query string.
+	query := `INSERT INTO security_events (id, created_at, event_type, user_id, username, ip, user_agent) VALUES ($1, $2, $3, $4, $5, $6, $7)`
+
+	// Send query to database.
+	_, err := q.Exec(query, e.ID, e.CreatedAt, e.EventType, e.UserID, e.Username, e.IP, e.UserAgent)
+	if err != nil {
+		// Return only error.
+		return err
+	}
+
+	// This query returns nothing.
+	return nil
+}
@@ -2,25 +2,24 @@ package queries
 
 import (
 	"github.com/google/uuid"
-	"github.com/jmoiron/sqlx"
 	"github.com/koddr/tutorial-go-fiber-rest-api/app/models"
 )
 
 // ServerQueries struct for queries from Server model.
 type ServerQueries struct {
-	*sqlx.DB
+	Executor
 }
 
-// GetServers method for getting all servers.
-func (q *ServerQueries) GetServers() ([]models.Server, error) {
+// GetServers method for getting all servers of the given organization.
+func (q *ServerQueries) GetServers(orgID uuid.UUID) ([]models.Server, error) {
 	// Define servers variable.
 	servers := []models.Server{}
 
 	// Define query string.
-	query := `SELECT * FROM servers`
+	query := `SELECT * FROM servers WHERE org_id = $1`
 
 	// Send query to database.
-	err := q.Get(&servers, query)
+	err := q.Select(&servers, query, orgID)
 	if err != nil {
 		// Return empty object and error.
 		return servers, err
@@ -30,16 +29,55 @@ func (q *ServerQueries) GetServers() ([]models.Server, error) {
 	return servers, nil
 }
 
-// GetServer method for getting one server by given ID.
-func (q *ServerQueries) GetServer(id uuid.UUID) (models.Server, error) {
+// GetServersByUser method for getting all servers of the given user in organization.
+func (q *ServerQueries) GetServersByUser(orgID, userID uuid.UUID) ([]models.Server, error) {
+	// Define servers variable.
+	servers := []models.Server{}
+
+	// Define query string.
+	query := `SELECT * FROM servers WHERE org_id = $1 AND user_id = $2`
+
+	// Send query to database.
+	err := q.Select(&servers, query, orgID, userID)
+	if err != nil {
+		// Return empty object and error.
+		return servers, err
+	}
+
+	// Return query result.
+	return servers, nil
+}
+
+// GetServer method for getting one server of organization by given ID.
+func (q *ServerQueries) GetServer(orgID, id uuid.UUID) (models.Server, error) {
 	// Define server variable.
 	server := models.Server{}
 
 	// Define query string.
-	query := `SELECT * FROM servers WHERE id = $1`
+	query := `SELECT * FROM servers WHERE org_id = $1 AND id = $2`
 
 	// Send query to database.
-	err := q.Get(&server, query, id)
+	err := q.Get(&server, query, orgID, id)
+	if err != nil {
+		// Return empty object and error.
+		return server, err
+	}
+
+	// Return query result.
+	return server, nil
+}
+
+// GetServerForUpdate method for getting one server of organization by given ID
+// and locking it until the end of transaction, so it's changed by one request at a time.
+func (q *ServerQueries) GetServerForUpdate(orgID, id uuid.UUID) (models.Server, error) {
+	// Define server variable.
+	server := models.Server{}
+
+	// Define query string.
+	query := `SELECT * FROM servers WHERE org_id = $1 AND id = $2 FOR UPDATE`
+
+	// Send query to database.
+	err := q.Get(&server, query, orgID, id)
 	if err != nil {
 		// Return empty object and error.
 		return server, err
@@ -52,10 +90,10 @@ func (q *ServerQueries) GetServer(id uuid.UUID) (models.Server, error) {
 // CreateServer method for creating server by given Server object.
 func (q *ServerQueries) CreateServer(b *models.Server) error {
 	// Define query string.
-	query := `INSERT INTO servers VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+	query := `INSERT INTO servers (id, created_at, updated_at, user_id, org_id, title, author, server_status, server_attrs) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
 
 	// Send query to database.
-	_, err := q.Exec(query, b.ID, b.CreatedAt, b.UpdatedAt, b.UserID, b.Title, b.Author, b.ServerStatus, b.ServerAttrs)
+	_, err := q.Exec(query, b.ID, b.CreatedAt, b.UpdatedAt, b.UserID, b.OrgID, b.Title, b.Author, b.ServerStatus, b.ServerAttrs)
 	if err != nil {
 		// Return only error.
 		return err
@@ -65,13 +103,13 @@ func (q *ServerQueries) CreateServer(b *models.Server) error {
 	return nil
 }
 
-// UpdateServer method for updating server by given Server object.
+// UpdateServer method for updating server of its organization by given Server object.
 func (q *ServerQueries) UpdateServer(id uuid.UUID, b *models.Server) error {
 	// Define query string.
-	query := `UPDATE servers SET updated_at = $2, title = $3, author = $4, server_status = $5, server_attrs = $6 WHERE id = $1`
+	query := `UPDATE servers SET updated_at = $2, title = $3, author = $4, server_status = $5, server_attrs = $6 WHERE id = $1 AND org_id = $7`
 
 	// Send query to database.
-	_, err := q.Exec(query, id, b.UpdatedAt, b.Title, b.Author, b.ServerStatus, b.ServerAttrs)
+	_, err := q.Exec(query, id, b.UpdatedAt, b.Title, b.Author, b.ServerStatus, b.ServerAttrs, b.OrgID)
 	if err != nil {
 		// Return only error.
 		return err
@@ -81,13 +119,13 @@ func (q *ServerQueries) UpdateServer(id uuid.UUID, b *models.Server) error {
 	return nil
 }
 
-// DeleteServer method for delete server by given ID.
-func (q *ServerQueries) DeleteServer(id uuid.UUID) error {
+// DeleteServer method for delete server of organization by given ID.
+func (q *ServerQueries) DeleteServer(orgID, id uuid.UUID) error {
 	// Define query string.
-	query := `DELETE FROM servers WHERE id = $1`
+	query := `DELETE FROM servers WHERE org_id = $1 AND id = $2`
 
 	// Send query to database.
-	_, err := q.Exec(query, id)
+	_, err := q.Exec(query, orgID, id)
 	if err != nil {
 		// Return only error.
 		return err
@@ -96,3 +134,79 @@ func (q *ServerQueries) DeleteServer(id uuid.UUID) error {
 	// This query returns nothing.
 	return nil
 }
+
+// FindServers method for getting page of servers of the given organization by given
+// filter, sort orders, skip and take, and count of all servers of filter.
+func (q *ServerQueries) FindServers(orgID uuid.UUID, args *models.ServerFindManyArgs) ([]models.Server, int, error) {
+	// Define servers variable.
+	servers := []models.Server{}
+
+	// Define filter of servers.
+	where := &whereBuilder{}
+	where.add("org_id = " + where.arg(orgID))
+	where.stringFilter("id::TEXT", args.Where.ID)
+	where.stringFilter("user_id::TEXT", args.Where.UserID)
+	where.stringFilter("title", args.Where.Title)
+	where.stringFilter("author", args.Where.Author)
+	where.intFilter("server_status", args.Where.ServerStatus)
+
+	// Define sort orders of servers.
+	order := &orderBuilder{}
+	for _, o := range args.OrderBy {
+		order.add("created_at", o.CreatedAt)
+		order.add("updated_at", o.UpdatedAt)
+		order.add("id", o.ID)
+		order.add("title", o.Title)
+		order.add("author", o.Author)
+		order.add("server_status", o.ServerStatus)
+	}
+
+	// Send queries to database.
+	count, err := findMany(q, &servers, "servers", where, order, args.Skip, args.Take)
+	if err != nil {
+		// Return empty objects and error.
+		return []models.Server{}, 0, err
+	}
+
+	// Return query result.
+	return servers, count, nil
+}
+
+// ServerRepository struct for queries from Server model as repository of records.
+type ServerRepository struct {
+	*ServerQueries
+}
+
+// List method for getting all servers of organization, or only servers of the given user.
+func (r ServerRepository) List(orgID, userID uuid.UUID) ([]models.Server, error) {
+	if userID != uuid.Nil {
+		return r.GetServersByUser(orgID, userID)
+	}
+
+	return r.GetServers(orgID)
+}
+
+// Get method for getting one server of organization by given ID.
+func (r ServerRepository) Get(orgID, id uuid.UUID) (models.Server, error) {
+	return r.GetServer(orgID, id)
+}
+
+// GetForUpdate method for getting one server of organization by given ID and locking it.
+func (r ServerRepository) GetForUpdate(orgID, id uuid.UUID) (models.Server, error) {
+	return r.GetServerForUpdate(orgID, id)
+}
+
+// Create method for creating server by given Server object.
+func (r ServerRepository) Create(b *models.Server) error {
+	return r.CreateServer(b)
+}
+
+// Update method for updating server of its organization by given Server object.
+func (r ServerRepository) Update(id uuid.UUID, b *models.Server) error {
+	return r.UpdateServer(id, b)
+}
+
+// Delete method for delete server of organization by given ID.
+func (r ServerRepository) Delete(orgID, id uuid.UUID) error {
+	return r.DeleteServer(orgID, id)
+}
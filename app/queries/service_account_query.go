@@ -0,0 +1,76 @@
+package queries
+
+import (
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/koddr/tutorial-go-fiber-rest-api/app/models"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/repository"
+)
+
+// ServiceAccountQueries struct for queries from ServiceAccount model.
+type ServiceAccountQueries struct {
+	*sqlx.DB
+}
+
+// GetServiceAccount method for getting one service account by given identity.
+func (q *ServiceAccountQueries) GetServiceAccount(identity string) (models.ServiceAccount, error) {
+	// Define service account variable.
+	account := models.ServiceAccount{}
+
+	// Define query string.
+	query := `SELECT * FROM service_accounts WHERE identity = $1`
+
+	// Send query to database.
+	err := q.Get(&account, query, identity)
+	if err != nil {
+		// Return empty object and error.
+		return account, err
+	}
+
+	// Return query result.
+	return account, nil
+}
+
+// CreateServiceAccount method for creating service account with the given roles
+// of app in one transaction. Service account is a member of the given organization
+// with the given role, or the owner of its personal organization, if it's uuid.Nil.
+func (q *ServiceAccountQueries) CreateServiceAccount(a *models.ServiceAccount, roles []string, organizationID uuid.UUID, role string) error {
+	// Begin a new transaction.
+	tx, err := q.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := createServiceAccount(tx, a, roles, organizationID, role); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// createServiceAccount func for creating user of service account in transaction,
+// user has no password, so it can't sign in.
+func createServiceAccount(tx *sqlx.Tx, a *models.ServiceAccount, roles []string, organizationID uuid.UUID, role string) error {
+	// Define query strings.
+	userQuery := `INSERT INTO users (id, created_at, username, roles, password_hash) VALUES ($1, $2, $3, $4, '')`
+	accountQuery := `INSERT INTO service_accounts (user_id, created_at, identity) VALUES ($1, $2, $3)`
+	membershipQuery := `INSERT INTO memberships (organization_id, user_id, role, created_at) VALUES ($1, $2, $3, $4)`
+
+	// Send queries to database.
+	if _, err := tx.Exec(userQuery, a.UserID, a.CreatedAt, a.Identity, models.Roles(roles)); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(accountQuery, a.UserID, a.CreatedAt, a.Identity); err != nil {
+		return err
+	}
+	if organizationID == uuid.Nil {
+		return createPersonalOrganization(tx, &models.User{ID: a.UserID, CreatedAt: a.CreatedAt, Username: a.Identity})
+	}
+	if role == "" {
+		role = repository.OrganizationMemberRole
+	}
+	_, err := tx.Exec(membershipQuery, organizationID, a.UserID, role, a.CreatedAt)
+
+	return err
+}
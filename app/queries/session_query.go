@@ -0,0 +1,96 @@
+package queries
+
+import (
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/koddr/tutorial-go-fiber-rest-api/app/models"
+)
+
+// SessionQueries struct for queries from Session model.
+type SessionQueries struct {
+	*sqlx.DB
+}
+
+// GetSessionByHash method for getting one active session by hash of its token.
+func (q *SessionQueries) GetSessionByHash(hash string) (models.Session, error) {
+	// Define session variable.
+	session := models.Session{}
+
+	// Define query string.
+	query := `SELECT * FROM sessions WHERE token_hash = $1 AND revoked_at IS NULL`
+
+	// Send query to database.
+	err := q.Get(&session, query, hash)
+	if err != nil {
+		// Return empty object and error.
+		return session, err
+	}
+
+	// Return query result.
+	return session, nil
+}
+
+// CreateSession method for creating session by given Session object.
+func (q *SessionQueries) CreateSession(s *models.Session) error {
+	// Define query string.
+	query := `INSERT INTO sessions (id, created_at, last_seen_at, expires_at, user_id, token_hash, csrf_token_hash, two_factor, ip, user_agent)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+
+	// Send query to database.
+	_, err := q.Exec(query, s.ID, s.CreatedAt, s.LastSeenAt, s.ExpiresAt, s.UserID, s.TokenHash, s.CSRFTokenHash, s.TwoFactor, s.IP, s.UserAgent)
+	if err != nil {
+		// Return only error.
+		return err
+	}
+
+	// This query returns nothing.
+	return nil
+}
+
+// TouchSession method for updating time of the last request of session.
+func (q *SessionQueries) TouchSession(id uuid.UUID) error {
+	// Define query string.
+	query := `UPDATE sessions SET last_seen_at = NOW () WHERE id = $1`
+
+	// Send query to database.
+	_, err := q.Exec(query, id)
+	if err != nil {
+		// Return only error.
+		return err
+	}
+
+	// This query returns nothing.
+	return nil
+}
+
+// RevokeSession method for revoking session by given ID.
+func (q *SessionQueries) RevokeSession(id uuid.UUID) error {
+	// Define query string.
+	query := `UPDATE sessions SET revoked_at = NOW () WHERE id = $1 AND revoked_at IS NULL`
+
+	// Send query to database.
+	_, err := q.Exec(query, id)
+	if err != nil {
+		// Return only error.
+		return err
+	}
+
+	// This query returns nothing.
+	return nil
+}
+
+// RevokeUserSessions method for revoking all sessions of user.
+func (q *SessionQueries) RevokeUserSessions(userID uuid.UUID) error {
+	// Define query string.
+	query := `UPDATE sessions SET revoked_at = NOW () WHERE user_id = $1 AND revoked_at IS NULL`
+
+	// Send query to database.
+	_, err := q.Exec(query, userID)
+	if err != nil {
+		// Return only error.
+		return err
+	}
+
+	// This query returns nothing.
+	return nil
+}
@@ -0,0 +1,101 @@
+package queries
+
+import (
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/koddr/tutorial-go-fiber-rest-api/app/models"
+)
+
+// TokenQueries struct for queries from RefreshToken model.
+type TokenQueries struct {
+	*sqlx.DB
+}
+
+// GetRefreshTokenByHash method for getting one refresh token by given hash.
+func (q *TokenQueries) GetRefreshTokenByHash(hash string) (models.RefreshToken, error) {
+	// Define refresh token variable.
+	token := models.RefreshToken{}
+
+	// Define query string.
+	query := `SELECT * FROM refresh_tokens WHERE token_hash = $1`
+
+	// Send query to database.
+	err := q.Get(&token, query, hash)
+	if err != nil {
+		// Return empty object and error.
+		return token, err
+	}
+
+	// Return query result.
+	return token, nil
+}
+
+// CreateRefreshToken method for creating refresh token by given RefreshToken object.
+func (q *TokenQueries) CreateRefreshToken(t *models.RefreshToken) error {
+	// Define query string.
+	query := `INSERT INTO refresh_tokens (id, created_at, expires_at, family_id, user_id, token_hash, two_factor) VALUES ($1, $2, $3, $4, $5, $6, $7)`
+
+	// Send query to database.
+	_, err := q.Exec(query, t.ID, t.CreatedAt, t.ExpiresAt, t.FamilyID, t.UserID, t.TokenHash, t.TwoFactor)
+	if err != nil {
+		// Return only error.
+		return err
+	}
+
+	// This query returns nothing.
+	return nil
+}
+
+// UseRefreshToken method for mark refresh token as used. It returns false,
+// if token was already used or revoked (so, it's a replay of rotated token).
+func (q *TokenQueries) UseRefreshToken(id uuid.UUID) (bool, error) {
+	// Define query string.
+	query := `UPDATE refresh_tokens SET used_at = NOW () WHERE id = $1 AND used_at IS NULL AND revoked_at IS NULL`
+
+	// Send query to database.
+	result, err := q.Exec(query, id)
+	if err != nil {
+		// Return only error.
+		return false, err
+	}
+
+	// Checking, if token was marked by this query.
+	count, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return count == 1, nil
+}
+
+// RevokeRefreshTokenFamily method for revoke all refresh tokens of given family.
+func (q *TokenQueries) RevokeRefreshTokenFamily(familyID uuid.UUID) error {
+	// Define query string.
+	query := `UPDATE refresh_tokens SET revoked_at = NOW () WHERE family_id = $1 AND revoked_at IS NULL`
+
+	// Send query to database.
+	_, err := q.Exec(query, familyID)
+	if err != nil {
+		// Return only error.
+		return err
+	}
+
+	// This query returns nothing.
+	return nil
+}
+
+// RevokeUserRefreshTokens method for revoke all refresh tokens of given user.
+func (q *TokenQueries) RevokeUserRefreshTokens(userID uuid.UUID) error {
+	// Define query string.
+	query := `UPDATE refresh_tokens SET revoked_at = NOW () WHERE user_id = $1 AND revoked_at IS NULL`
+
+	// Send query to database.
+	_, err := q.Exec(query, userID)
+	if err != nil {
+		// Return only error.
+		return err
+	}
+
+	// This query returns nothing.
+	return nil
+}
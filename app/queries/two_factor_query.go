@@ -0,0 +1,163 @@
+package queries
+
+import (
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/koddr/tutorial-go-fiber-rest-api/app/models"
+)
+
+// TwoFactorQueries struct for queries from UserTOTP model and recovery codes.
+type TwoFactorQueries struct {
+	*sqlx.DB
+}
+
+// GetUserTOTP method for getting TOTP secret of given user.
+func (q *TwoFactorQueries) GetUserTOTP(userID uuid.UUID) (models.UserTOTP, error) {
+	// Define TOTP variable.
+	totp := models.UserTOTP{}
+
+	// Define query string.
+	query := `SELECT * FROM user_totp WHERE user_id = $1`
+
+	// Send query to database.
+	err := q.Get(&totp, query, userID)
+	if err != nil {
+		// Return empty object and error.
+		return totp, err
+	}
+
+	// Return query result.
+	return totp, nil
+}
+
+// HasTwoFactor method for checking, if given user has enabled second factor.
+func (q *TwoFactorQueries) HasTwoFactor(userID uuid.UUID) (bool, error) {
+	// Define result variable.
+	enabled := false
+
+	// Define query string.
+	query := `SELECT EXISTS (SELECT 1 FROM user_totp WHERE user_id = $1 AND enabled_at IS NOT NULL)`
+
+	// Send query to database.
+	err := q.Get(&enabled, query, userID)
+	if err != nil {
+		// Return only error.
+		return false, err
+	}
+
+	return enabled, nil
+}
+
+// SavePendingTOTP method for store a new TOTP secret of user, which is not confirmed yet.
+// Enabled secret is never replaced. It returns false, if second factor is already enabled.
+func (q *TwoFactorQueries) SavePendingTOTP(t *models.UserTOTP) (bool, error) {
+	// Define query string.
+	query := `INSERT INTO user_totp (user_id, created_at, secret) VALUES ($1, $2, $3)
+		ON CONFLICT (user_id) DO UPDATE SET created_at = $2, secret = $3, last_used_step = 0
+		WHERE user_totp.enabled_at IS NULL`
+
+	// Send query to database.
+	result, err := q.Exec(query, t.UserID, t.CreatedAt, t.Secret)
+	if err != nil {
+		// Return only error.
+		return false, err
+	}
+
+	// Checking, if secret was stored by this query.
+	count, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return count == 1, nil
+}
+
+// EnableTOTP method for enable second factor of user with hashes of a new
+// set of recovery codes. Code of the given time step can't be used again.
+func (q *TwoFactorQueries) EnableTOTP(userID uuid.UUID, step int64, codeHashes []string) error {
+	// Begin a new transaction.
+	tx, err := q.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Send queries to database.
+	if _, err := tx.Exec(`UPDATE user_totp SET enabled_at = NOW (), last_used_step = $2 WHERE user_id = $1`, userID, step); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+	for _, hash := range codeHashes {
+		if _, err := tx.Exec(`INSERT INTO recovery_codes (id, user_id, code_hash) VALUES ($1, $2, $3)`, uuid.New(), userID, hash); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// UseTOTPStep method for mark code of the given time step as used. It returns
+// false, if code of this or a later step was already used (so, it's a replay).
+func (q *TwoFactorQueries) UseTOTPStep(userID uuid.UUID, step int64) (bool, error) {
+	// Define query string.
+	query := `UPDATE user_totp SET last_used_step = $2 WHERE user_id = $1 AND last_used_step < $2`
+
+	// Send query to database.
+	result, err := q.Exec(query, userID, step)
+	if err != nil {
+		// Return only error.
+		return false, err
+	}
+
+	// Checking, if step was marked by this query.
+	count, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return count == 1, nil
+}
+
+// UseRecoveryCode method for mark recovery code of user as used.
+// It returns false, if code is not found or already used.
+func (q *TwoFactorQueries) UseRecoveryCode(userID uuid.UUID, hash string) (bool, error) {
+	// Define query string.
+	query := `UPDATE recovery_codes SET used_at = NOW () WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL`
+
+	// Send query to database.
+	result, err := q.Exec(query, userID, hash)
+	if err != nil {
+		// Return only error.
+		return false, err
+	}
+
+	// Checking, if code was marked by this query.
+	count, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return count == 1, nil
+}
+
+// DeleteTwoFactor method for disable second factor of user and delete its recovery codes.
+func (q *TwoFactorQueries) DeleteTwoFactor(userID uuid.UUID) error {
+	// Begin a new transaction.
+	tx, err := q.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Send queries to database.
+	if _, err := tx.Exec(`DELETE FROM recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM user_totp WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
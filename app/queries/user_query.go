@@ -0,0 +1,309 @@
+package queries
+
+import (
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/koddr/tutorial-go-fiber-rest-api/app/models"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/repository"
+)
+
+// UserQueries struct for queries from User model.
+type UserQueries struct {
+	*sqlx.DB
+}
+
+// GetUserByID method for getting one user by given ID.
+func (q *UserQueries) GetUserByID(id uuid.UUID) (models.User, error) {
+	// Define user variable.
+	user := models.User{}
+
+	// Define query string.
+	query := `SELECT * FROM users WHERE id = $1`
+
+	// Send query to database.
+	err := q.Get(&user, query, id)
+	if err != nil {
+		// Return empty object and error.
+		return user, err
+	}
+
+	// Return query result.
+	return user, nil
+}
+
+// GetUserByUsername method for getting one user by given username.
+func (q *UserQueries) GetUserByUsername(username string) (models.User, error) {
+	// Define user variable.
+	user := models.User{}
+
+	// Define query string.
+	query := `SELECT * FROM users WHERE username = $1`
+
+	// Send query to database.
+	err := q.Get(&user, query, username)
+	if err != nil {
+		// Return empty object and error.
+		return user, err
+	}
+
+	// Return query result.
+	return user, nil
+}
+
+// GetUserByEmail method for getting one user by given email (in any case).
+func (q *UserQueries) GetUserByEmail(email string) (models.User, error) {
+	// Define user variable.
+	user := models.User{}
+
+	// Define query string.
+	query := `SELECT * FROM users WHERE email = LOWER ($1)`
+
+	// Send query to database.
+	err := q.Get(&user, query, email)
+	if err != nil {
+		// Return empty object and error.
+		return user, err
+	}
+
+	// Return query result.
+	return user, nil
+}
+
+// CreateUser method for creating user by given User object.
+func (q *UserQueries) CreateUser(u *models.User) error {
+	// Begin a new transaction.
+	tx, err := q.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Define query string.
+	query := `INSERT INTO users (id, created_at, updated_at, username, first_name, last_name, roles, password_hash, email)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+
+	// Send queries to database.
+	if _, err := tx.Exec(query, u.ID, u.CreatedAt, u.UpdatedAt, u.Username, u.FirstName, u.LastName, u.Roles, u.PasswordHash, u.Email); err != nil {
+		return err
+	}
+	if err := createPersonalOrganization(tx, u); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// createPersonalOrganization func for creating personal organization of a new user
+// in transaction of user, the organization has ID of user and the user is its owner.
+func createPersonalOrganization(tx *sqlx.Tx, u *models.User) error {
+	// Define query strings.
+	organizationQuery := `INSERT INTO organizations (id, created_at, name) VALUES ($1, $2, $3)`
+	membershipQuery := `INSERT INTO memberships (organization_id, user_id, role, created_at) VALUES ($1, $1, $2, $3)`
+
+	// Send queries to database.
+	if _, err := tx.Exec(organizationQuery, u.ID, u.CreatedAt, u.Username); err != nil {
+		return err
+	}
+	_, err := tx.Exec(membershipQuery, u.ID, repository.OrganizationOwnerRole, u.CreatedAt)
+
+	return err
+}
+
+// GetUserByIdentity method for getting one user by account at OpenID Connect provider.
+func (q *UserQueries) GetUserByIdentity(issuer, subject string) (models.User, error) {
+	// Define user variable.
+	user := models.User{}
+
+	// Define query string.
+	query := `SELECT users.* FROM users JOIN user_identities ON user_identities.user_id = users.id WHERE user_identities.issuer = $1 AND user_identities.subject = $2`
+
+	// Send query to database.
+	err := q.Get(&user, query, issuer, subject)
+	if err != nil {
+		// Return empty object and error.
+		return user, err
+	}
+
+	// Return query result.
+	return user, nil
+}
+
+// CreateUserWithIdentity method for creating user linked to account at
+// OpenID Connect provider. Both are created in one transaction.
+func (q *UserQueries) CreateUserWithIdentity(u *models.User, i *models.UserIdentity) error {
+	// Begin a new transaction.
+	tx, err := q.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Define query strings.
+	userQuery := `INSERT INTO users (id, created_at, updated_at, username, first_name, last_name, roles, password_hash, email)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+	identityQuery := `INSERT INTO user_identities (id, created_at, user_id, issuer, subject) VALUES ($1, $2, $3, $4, $5)`
+
+	// Send queries to database.
+	if _, err := tx.Exec(userQuery, u.ID, u.CreatedAt, u.UpdatedAt, u.Username, u.FirstName, u.LastName, u.Roles, u.PasswordHash, u.Email); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(identityQuery, i.ID, i.CreatedAt, i.UserID, i.Issuer, i.Subject); err != nil {
+		return err
+	}
+	if err := createPersonalOrganization(tx, u); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// UpdateUserRoles method for updating roles of user by given ID.
+func (q *UserQueries) UpdateUserRoles(id uuid.UUID, roles models.Roles) error {
+	// Define query string.
+	query := `UPDATE users SET updated_at = NOW (), roles = $2 WHERE id = $1`
+
+	// Send query to database.
+	_, err := q.Exec(query, id, roles)
+	if err != nil {
+		// Return only error.
+		return err
+	}
+
+	// This query returns nothing.
+	return nil
+}
+
+// UpdateUserPassword method for updating hash of password of user by given ID.
+func (q *UserQueries) UpdateUserPassword(id uuid.UUID, passwordHash string) error {
+	// Define query string.
+	query := `UPDATE users SET updated_at = NOW (), password_hash = $2 WHERE id = $1`
+
+	// Send query to database.
+	_, err := q.Exec(query, id, passwordHash)
+	if err != nil {
+		// Return only error.
+		return err
+	}
+
+	// This query returns nothing.
+	return nil
+}
+
+// UpdateUserEmail method for updating email of user by given ID. New email is not verified.
+func (q *UserQueries) UpdateUserEmail(id uuid.UUID, email string) error {
+	// Define query string.
+	query := `UPDATE users SET updated_at = NOW (), email = LOWER ($2), email_verified_at = NULL WHERE id = $1`
+
+	// Send query to database.
+	_, err := q.Exec(query, id, email)
+	if err != nil {
+		// Return only error.
+		return err
+	}
+
+	// This query returns nothing.
+	return nil
+}
+
+// VerifyUserEmail method for mark email of user as verified. It returns false,
+// if user has another email now (it was changed after the link was sent).
+func (q *UserQueries) VerifyUserEmail(id uuid.UUID, email string) (bool, error) {
+	// Define query string.
+	query := `UPDATE users SET email_verified_at = NOW () WHERE id = $1 AND email = $2`
+
+	// Send query to database.
+	result, err := q.Exec(query, id, email)
+	if err != nil {
+		// Return only error.
+		return false, err
+	}
+
+	// Checking, if email was verified by this query.
+	count, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return count == 1, nil
+}
+
+// GetUsers method for getting page of users by given filter, sort orders,
+// skip and take, and count of all users of filter. Only user with the given
+// ID is found, if it's not uuid.Nil.
+func (q *UserQueries) GetUsers(args *models.UserFindManyArgs, userID uuid.UUID) ([]models.User, int, error) {
+	// Define users variable.
+	users := []models.User{}
+
+	// Define filter of users.
+	where := &whereBuilder{}
+	if userID != uuid.Nil {
+		where.add("id = " + where.arg(userID))
+	}
+	where.stringFilter("id::TEXT", args.Where.ID)
+	where.stringFilter("username", args.Where.Username)
+	where.stringFilter("first_name", args.Where.FirstName)
+	where.stringFilter("last_name", args.Where.LastName)
+
+	// Define sort orders of users.
+	order := &orderBuilder{}
+	for _, o := range args.OrderBy {
+		order.add("created_at", o.CreatedAt)
+		order.add("first_name", o.FirstName)
+		order.add("id", o.ID)
+		order.add("last_name", o.LastName)
+		order.add("roles", o.Roles)
+		order.add("updated_at", o.UpdatedAt)
+		order.add("username", o.Username)
+	}
+
+	// Send queries to database.
+	count, err := findMany(q, &users, "users", where, order, args.Skip, args.Take)
+	if err != nil {
+		// Return empty objects and error.
+		return []models.User{}, 0, err
+	}
+
+	// Return query result.
+	return users, count, nil
+}
+
+// UpdateUser method for updating user by given User object.
+func (q *UserQueries) UpdateUser(u *models.User) error {
+	// Define query string.
+	query := `UPDATE users SET updated_at = $2, username = $3, first_name = $4, last_name = $5, roles = $6, password_hash = $7 WHERE id = $1`
+
+	// Send query to database.
+	_, err := q.Exec(query, u.ID, u.UpdatedAt, u.Username, u.FirstName, u.LastName, u.Roles, u.PasswordHash)
+	if err != nil {
+		// Return only error.
+		return err
+	}
+
+	// This query returns nothing.
+	return nil
+}
+
+// DeleteUser method for delete user by given ID. Personal organization of user
+// is deleted with its records, records in other organizations are kept without owner.
+func (q *UserQueries) DeleteUser(id uuid.UUID) error {
+	// Begin a new transaction.
+	tx, err := q.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Define query strings.
+	userQuery := `DELETE FROM users WHERE id = $1`
+	organizationQuery := `DELETE FROM organizations WHERE id = $1`
+
+	// Send queries to database.
+	if _, err := tx.Exec(userQuery, id); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(organizationQuery, id); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
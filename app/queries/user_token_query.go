@@ -0,0 +1,67 @@
+package queries
+
+import (
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/koddr/tutorial-go-fiber-rest-api/app/models"
+)
+
+// UserTokenQueries struct for queries from UserToken model.
+type UserTokenQueries struct {
+	*sqlx.DB
+}
+
+// CreateUserToken method for creating user token by given UserToken object.
+func (q *UserTokenQueries) CreateUserToken(t *models.UserToken) error {
+	// Define query string.
+	query := `INSERT INTO user_tokens (id, created_at, expires_at, user_id, purpose, token_hash, email) VALUES ($1, $2, $3, $4, $5, $6, $7)`
+
+	// Send query to database.
+	_, err := q.Exec(query, t.ID, t.CreatedAt, t.ExpiresAt, t.UserID, t.Purpose, t.TokenHash, t.Email)
+	if err != nil {
+		// Return only error.
+		return err
+	}
+
+	// This query returns nothing.
+	return nil
+}
+
+// UseUserToken method for mark token of given purpose as used and get it.
+// Token, which is unknown, expired or already used, is not found.
+func (q *UserTokenQueries) UseUserToken(hash, purpose string) (models.UserToken, error) {
+	// Define user token variable.
+	token := models.UserToken{}
+
+	// Define query string.
+	query := `UPDATE user_tokens SET used_at = NOW ()
+		WHERE token_hash = $1 AND purpose = $2 AND used_at IS NULL AND expires_at > NOW ()
+		RETURNING *`
+
+	// Send query to database.
+	err := q.Get(&token, query, hash, purpose)
+	if err != nil {
+		// Return empty object and error.
+		return token, err
+	}
+
+	// Return query result.
+	return token, nil
+}
+
+// DeleteUserTokens method for delete all tokens of given purpose of user, so
+// links, which were sent before, can't be used anymore.
+func (q *UserTokenQueries) DeleteUserTokens(userID uuid.UUID, purpose string) error {
+	// Define query string.
+	query := `DELETE FROM user_tokens WHERE user_id = $1 AND purpose = $2`
+
+	// Send query to database.
+	_, err := q.Exec(query, userID, purpose)
+	if err != nil {
+		// Return only error.
+		return err
+	}
+
+	// This query returns nothing.
+	return nil
+}
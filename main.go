@@ -1,11 +1,14 @@
 package main
 
 import (
+	"log"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/configs"
 	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/middleware"
 	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/routes"
 	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/utils"
+	"github.com/koddr/tutorial-go-fiber-rest-api/platform/database"
 
 	_ "github.com/joho/godotenv/autoload"                // load .env file automatically
 	_ "github.com/koddr/tutorial-go-fiber-rest-api/docs" // load API Docs files (Swagger)
@@ -27,6 +30,12 @@ func main() {
 	// Define Fiber config.
 	config := configs.FiberConfig()
 
+	// Open shared pool of database connections, handlers and middleware reuse it.
+	if err := database.Connect(); err != nil {
+		log.Printf("Oops... Database is not connected! Reason: %v", err)
+	}
+	defer database.Close()
+
 	// Define a new Fiber app with config.
 	app := fiber.New(config)
 
@@ -34,10 +43,13 @@ func main() {
 	middleware.FiberMiddleware(app) // Register Fiber's middleware for app.
 
 	// Routes.
-	routes.SwaggerRoute(app)  // Register a route for API Docs (Swagger).
-	routes.PublicRoutes(app)  // Register a public routes for app.
-	routes.PrivateRoutes(app) // Register a private routes for app.
-	routes.NotFoundRoute(app) // Register route for 404 Error.
+	routes.SwaggerRoute(app)    // Register a route for API Docs (Swagger).
+	routes.WellKnownRoutes(app) // Register a well-known routes for app.
+	routes.OAuthRoutes(app)     // Register an OAuth routes for app.
+	routes.PublicRoutes(app)    // Register a public routes for app.
+	routes.PrivateRoutes(app)   // Register a private routes for app.
+	routes.GraphQLRoute(app)    // Register a GraphQL route for app.
+	routes.NotFoundRoute(app)   // Register route for 404 Error.
 
 	// Start server (with graceful shutdown).
 	utils.StartServer(app)
@@ -0,0 +1,99 @@
+package acl
+
+import (
+	_ "embed" // load default grants
+	"log"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// defaultGrants are used, if ACL_GRANTS_FILE is not set.
+//
+//go:embed grants.json
+var defaultGrants []byte
+
+var (
+	current   atomic.Value // *Policy
+	startOnce sync.Once
+)
+
+// CurrentPolicy func for get the current policy. Grants are loaded from the file
+// of ACL_GRANTS_FILE (or the embedded defaults) on first call, and the file is
+// checked for changes every ACL_GRANTS_RELOAD_SECONDS (default 10) seconds,
+// so policies are reloaded without restart.
+func CurrentPolicy() *Policy {
+	startOnce.Do(start)
+
+	return current.Load().(*Policy)
+}
+
+func start() {
+	path := os.Getenv("ACL_GRANTS_FILE")
+	if path == "" {
+		policy, err := Parse(defaultGrants)
+		if err != nil {
+			panic(err)
+		}
+		current.Store(policy)
+		return
+	}
+
+	modTime, err := load(path)
+	if err != nil {
+		// Deny everything, if grants file can't be loaded on start.
+		log.Printf("Oops... Grants are not loaded! Reason: %v", err)
+		current.Store(&Policy{grants: map[string]Attributes{}})
+	}
+
+	seconds, _ := strconv.Atoi(os.Getenv("ACL_GRANTS_RELOAD_SECONDS"))
+	if seconds <= 0 {
+		seconds = 10
+	}
+
+	go watch(path, modTime, time.Duration(seconds)*time.Second)
+}
+
+// load func for parse grants file and store it as the current policy.
+func load(path string) (time.Time, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	policy, err := Parse(data)
+	if err != nil {
+		return time.Time{}, err
+	}
+	current.Store(policy)
+
+	return info.ModTime(), nil
+}
+
+// watch func for reload grants file after each change.
+func watch(path string, modTime time.Time, interval time.Duration) {
+	for range time.Tick(interval) {
+		info, err := os.Stat(path)
+		if err != nil || info.ModTime().Equal(modTime) {
+			continue
+		}
+
+		// Keep the previous policy, if the changed file is not valid.
+		loaded, err := load(path)
+		if err != nil {
+			log.Printf("Oops... Grants are not reloaded! Reason: %v", err)
+			modTime = info.ModTime()
+			continue
+		}
+
+		modTime = loaded
+		log.Printf("Grants are reloaded from %s", path)
+	}
+}
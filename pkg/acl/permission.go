@@ -0,0 +1,116 @@
+package acl
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// permissionContextKey is a key of fiber.Ctx locals for the route Permission.
+const permissionContextKey = "permission"
+
+// Permission struct to describe result of the access check.
+type Permission struct {
+	Granted    bool
+	Possession string     // PossessionAny or PossessionOwn
+	Attributes Attributes // globs of allowed ("name") and denied ("!name") attributes
+}
+
+// Filter method for remove attributes, which are not allowed by permission.
+// Nested attributes are separated by dot, like "book_attrs.rating".
+func (p Permission) Filter(data map[string]interface{}) map[string]interface{} {
+	allow, deny := [][]string{}, [][]string{}
+	for _, attribute := range p.Attributes {
+		if strings.HasPrefix(attribute, "!") {
+			deny = append(deny, strings.Split(strings.TrimPrefix(attribute, "!"), "."))
+		} else {
+			allow = append(allow, strings.Split(attribute, "."))
+		}
+	}
+
+	return filterObject(data, allow, deny)
+}
+
+// AllowsAll method for checking, if permission allows all attributes.
+func (p Permission) AllowsAll() bool {
+	allowed := false
+	for _, attribute := range p.Attributes {
+		if strings.HasPrefix(attribute, "!") {
+			return false
+		}
+		allowed = allowed || attribute == "*"
+	}
+
+	return allowed
+}
+
+// InvalidAttributes method for get attributes of data, which are not allowed by permission.
+func (p Permission) InvalidAttributes(data map[string]interface{}) []string {
+	filtered := p.Filter(data)
+
+	invalid := []string{}
+	for key := range data {
+		if _, ok := filtered[key]; !ok {
+			invalid = append(invalid, key)
+		}
+	}
+	sort.Strings(invalid)
+
+	return invalid
+}
+
+func filterObject(data map[string]interface{}, allow, deny [][]string) map[string]interface{} {
+	result := map[string]interface{}{}
+
+	for key, value := range data {
+		childAllow, childDeny := [][]string{}, [][]string{}
+		allowed, denied := false, false
+
+		for _, path := range allow {
+			if path[0] == "*" || path[0] == key {
+				if len(path) == 1 {
+					allowed = true
+				} else {
+					childAllow = append(childAllow, path[1:])
+				}
+			}
+		}
+		for _, path := range deny {
+			if path[0] == "*" || path[0] == key {
+				if len(path) == 1 {
+					denied = true
+				} else {
+					childDeny = append(childDeny, path[1:])
+				}
+			}
+		}
+
+		if denied || (!allowed && len(childAllow) == 0) {
+			continue
+		}
+		if allowed {
+			childAllow = append(childAllow, []string{"*"})
+		}
+
+		// Filter nested objects by the rest of attribute paths.
+		if object, ok := value.(map[string]interface{}); ok && (len(childDeny) > 0 || !allowed) {
+			value = filterObject(object, childAllow, childDeny)
+		}
+
+		result[key] = value
+	}
+
+	return result
+}
+
+// SetPermission func for store permission of the current route.
+func SetPermission(c *fiber.Ctx, p Permission) {
+	c.Locals(permissionContextKey, p)
+}
+
+// GetPermission func for get permission of the current route, if it was checked.
+func GetPermission(c *fiber.Ctx) (Permission, bool) {
+	p, ok := c.Locals(permissionContextKey).(Permission)
+	return p, ok
+}
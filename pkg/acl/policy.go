@@ -0,0 +1,112 @@
+package acl
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const (
+	// PossessionAny const for permission on records of any owner.
+	PossessionAny string = "any"
+
+	// PossessionOwn const for permission on own records only.
+	PossessionOwn string = "own"
+)
+
+// Grant struct to describe one grant of the grants file.
+// The format is the same as in grants.json of the generated NestJS server.
+type Grant struct {
+	Role       string     `json:"role"`
+	Resource   string     `json:"resource"`
+	Action     string     `json:"action"` // like "read:own" or "delete:any"
+	Attributes Attributes `json:"attributes"`
+}
+
+// Attributes struct to describe attributes of grant,
+// given as a glob string ("*, !password") or as a list of globs.
+type Attributes []string
+
+// UnmarshalJSON method for decode attributes from a string or a list.
+func (a *Attributes) UnmarshalJSON(data []byte) error {
+	var list []string
+	if err := json.Unmarshal(data, &list); err == nil {
+		*a = list
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("attributes must be a string or a list of strings, %w", err)
+	}
+
+	*a = Attributes{}
+	for _, attribute := range strings.Split(s, ",") {
+		if attribute = strings.TrimSpace(attribute); attribute != "" {
+			*a = append(*a, attribute)
+		}
+	}
+
+	return nil
+}
+
+// Policy struct to describe a parsed set of grants.
+type Policy struct {
+	grants map[string]Attributes // key is "role/resource/verb:possession"
+}
+
+// Parse func for parse grants in the grants file format.
+func Parse(data []byte) (*Policy, error) {
+	grants := []Grant{}
+	if err := json.Unmarshal(data, &grants); err != nil {
+		return nil, fmt.Errorf("grants are not valid, %w", err)
+	}
+
+	policy := &Policy{grants: map[string]Attributes{}}
+	for _, g := range grants {
+		verb, possession, ok := splitAction(g.Action)
+		if !ok || g.Role == "" || g.Resource == "" {
+			return nil, fmt.Errorf("grant %+v must have role, resource and action like \"read:own\"", g)
+		}
+
+		key := grantKey(g.Role, g.Resource, verb, possession)
+		policy.grants[key] = append(policy.grants[key], g.Attributes...)
+	}
+
+	return policy, nil
+}
+
+func splitAction(action string) (verb, possession string, ok bool) {
+	parts := strings.Split(strings.ToLower(action), ":")
+	if len(parts) != 2 || (parts[1] != PossessionAny && parts[1] != PossessionOwn) {
+		return "", "", false
+	}
+
+	switch parts[0] {
+	case "create", "read", "update", "delete":
+		return parts[0], parts[1], true
+	}
+
+	return "", "", false
+}
+
+func grantKey(role, resource, verb, possession string) string {
+	return strings.ToLower(role) + "/" + strings.ToLower(resource) + "/" + verb + ":" + possession
+}
+
+// Permission method for get permission of the given roles on action with resource.
+// Action is a verb (create, read, update, delete); "any" grants take precedence.
+func (p *Policy) Permission(roles []string, resource, action string) Permission {
+	for _, possession := range []string{PossessionAny, PossessionOwn} {
+		attributes := Attributes{}
+		for _, role := range roles {
+			attributes = append(attributes, p.grants[grantKey(role, resource, action, possession)]...)
+		}
+
+		if len(attributes) > 0 {
+			return Permission{Granted: true, Possession: possession, Attributes: attributes}
+		}
+	}
+
+	return Permission{}
+}
@@ -0,0 +1,63 @@
+package acl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolicy(t *testing.T) {
+	// Create a sample grants string.
+	grants := `[
+		{"role": "user", "resource": "books", "action": "read:any", "attributes": "*, !book_attrs.picture"},
+		{"role": "user", "resource": "books", "action": "update:own", "attributes": ["title", "author"]},
+		{"role": "admin", "resource": "books", "action": "update:any", "attributes": "*"}
+	]`
+
+	policy, err := Parse([]byte(grants))
+	assert.NoError(t, err)
+
+	// Define a structure for specifying input and output data of a single test case.
+	tests := []struct {
+		description        string
+		roles              []string
+		action             string
+		expectedGranted    bool
+		expectedPossession string
+	}{
+		{"user reads any book", []string{"user"}, "read", true, PossessionAny},
+		{"user updates own book", []string{"user"}, "update", true, PossessionOwn},
+		{"admin updates any book", []string{"user", "admin"}, "update", true, PossessionAny},
+		{"user can't delete book", []string{"user"}, "delete", false, ""},
+		{"anonymous can't read book", []string{"anonymous"}, "read", false, ""},
+	}
+
+	// Iterate through test single test cases
+	for _, test := range tests {
+		permission := policy.Permission(test.roles, "books", test.action)
+		assert.Equalf(t, test.expectedGranted, permission.Granted, test.description)
+		assert.Equalf(t, test.expectedPossession, permission.Possession, test.description)
+	}
+
+	// Verify, that attributes are filtered by grant.
+	book := map[string]interface{}{
+		"title":      "Title",
+		"author":     "Author",
+		"user_id":    "00000000-0000-0000-0000-000000000000",
+		"book_attrs": map[string]interface{}{"picture": "x.png", "rating": 8.0},
+	}
+	read := policy.Permission([]string{"user"}, "books", "read")
+	assert.Equal(t, map[string]interface{}{
+		"title":      "Title",
+		"author":     "Author",
+		"user_id":    "00000000-0000-0000-0000-000000000000",
+		"book_attrs": map[string]interface{}{"rating": 8.0},
+	}, read.Filter(book))
+
+	update := policy.Permission([]string{"user"}, "books", "update")
+	assert.Equal(t, []string{"book_attrs", "user_id"}, update.InvalidAttributes(book))
+
+	// Verify, that grants with unknown actions are rejected.
+	_, err = Parse([]byte(`[{"role": "user", "resource": "books", "action": "read", "attributes": "*"}]`))
+	assert.Error(t, err)
+}
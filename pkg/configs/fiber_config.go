@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/problem"
 )
 
 // FiberConfig func for configuration Fiber app.
@@ -16,6 +17,7 @@ func FiberConfig() fiber.Config {
 
 	// Return Fiber configuration.
 	return fiber.Config{
-		ReadTimeout: time.Second * time.Duration(readTimeoutSecondsCount),
+		ReadTimeout:  time.Second * time.Duration(readTimeoutSecondsCount),
+		ErrorHandler: problem.Handler, // errors of handlers are returned as problem details (RFC 7807)
 	}
 }
@@ -0,0 +1,376 @@
+package jsonpatch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Media types of patches.
+const (
+	MergePatchType = "application/merge-patch+json" // JSON Merge Patch (RFC 7396)
+	PatchType      = "application/json-patch+json"  // JSON Patch (RFC 6902)
+)
+
+var (
+	// ErrMalformed is error of patch, which is not valid JSON or JSON Patch.
+	ErrMalformed = errors.New("malformed patch")
+	// ErrConflict is error of patch, which can't be applied to the document,
+	// like failed "test" operation or path, which doesn't exist.
+	ErrConflict = errors.New("patch can't be applied")
+)
+
+// Operation struct to describe operation of JSON Patch.
+type Operation struct {
+	Op    string           `json:"op"`
+	Path  *string          `json:"path"`
+	From  *string          `json:"from"`
+	Value *json.RawMessage `json:"value"`
+}
+
+// MergePatch func for apply JSON Merge Patch to JSON document. Members of patch
+// with null are removed from document, objects are merged, other values replace
+// values of document.
+func MergePatch(document, patch []byte) ([]byte, error) {
+	var target, changes interface{}
+	if err := json.Unmarshal(document, &target); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(patch, &changes); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformed, err)
+	}
+
+	return json.Marshal(mergeValue(target, changes))
+}
+
+// CreateMergePatch func for get JSON Merge Patch, which changes original
+// document to modified one, it has only changed members of objects.
+func CreateMergePatch(original, modified []byte) ([]byte, error) {
+	var before, after interface{}
+	if err := json.Unmarshal(original, &before); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(modified, &after); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(diffValue(before, after))
+}
+
+// Apply func for apply JSON Patch to JSON document. Operations are applied in
+// order to a copy of document, so document is changed by all operations or by
+// none of them.
+func Apply(document, patch []byte) ([]byte, error) {
+	var target interface{}
+	if err := json.Unmarshal(document, &target); err != nil {
+		return nil, err
+	}
+
+	operations := []Operation{}
+	if err := json.Unmarshal(patch, &operations); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformed, err)
+	}
+
+	for i, operation := range operations {
+		var err error
+		if target, err = operation.apply(target); err != nil {
+			return nil, fmt.Errorf("operation %d (%s): %w", i, operation.Op, err)
+		}
+	}
+
+	return json.Marshal(target)
+}
+
+// apply method for apply operation to document, it returns changed document.
+func (o Operation) apply(document interface{}) (interface{}, error) {
+	if o.Path == nil {
+		return nil, fmt.Errorf("%w: path is required", ErrMalformed)
+	}
+	path, err := parsePointer(*o.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch o.Op {
+	case "add", "replace", "test":
+		if o.Value == nil {
+			return nil, fmt.Errorf("%w: value is required", ErrMalformed)
+		}
+		var value interface{}
+		if err := json.Unmarshal(*o.Value, &value); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrMalformed, err)
+		}
+
+		switch o.Op {
+		case "add":
+			return change(document, path, func(parent interface{}, key string) (interface{}, error) {
+				return addValue(parent, key, value)
+			}, value)
+		case "replace":
+			if _, err := get(document, path); err != nil {
+				return nil, err
+			}
+			return change(document, path, func(parent interface{}, key string) (interface{}, error) {
+				return replaceValue(parent, key, value)
+			}, value)
+		}
+
+		current, err := get(document, path)
+		if err != nil {
+			return nil, err
+		}
+		if !reflect.DeepEqual(current, value) {
+			return nil, fmt.Errorf("%w: value at %q is not equal to tested value", ErrConflict, *o.Path)
+		}
+		return document, nil
+
+	case "remove":
+		if len(path) == 0 {
+			return nil, fmt.Errorf("%w: document can't be removed", ErrConflict)
+		}
+		return change(document, path, removeValue, nil)
+
+	case "move", "copy":
+		if o.From == nil {
+			return nil, fmt.Errorf("%w: from is required", ErrMalformed)
+		}
+		from, err := parsePointer(*o.From)
+		if err != nil {
+			return nil, err
+		}
+		value, err := get(document, from)
+		if err != nil {
+			return nil, err
+		}
+
+		if o.Op == "copy" {
+			value = copyValue(value)
+		} else {
+			if len(from) < len(path) && reflect.DeepEqual(from, path[:len(from)]) {
+				return nil, fmt.Errorf("%w: value can't be moved into itself", ErrConflict)
+			}
+			if document, err = change(document, from, removeValue, nil); err != nil {
+				return nil, err
+			}
+		}
+
+		return change(document, path, func(parent interface{}, key string) (interface{}, error) {
+			return addValue(parent, key, value)
+		}, value)
+	}
+
+	return nil, fmt.Errorf("%w: unknown operation %q", ErrMalformed, o.Op)
+}
+
+// parsePointer func for get reference tokens of JSON Pointer (RFC 6901).
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return []string{}, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("%w: path %q must start with \"/\"", ErrMalformed, pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+	}
+
+	return tokens, nil
+}
+
+// get func for get value of document by reference tokens.
+func get(document interface{}, path []string) (interface{}, error) {
+	value := document
+	for _, key := range path {
+		switch parent := value.(type) {
+		case map[string]interface{}:
+			child, ok := parent[key]
+			if !ok {
+				return nil, notFound(key)
+			}
+			value = child
+		case []interface{}:
+			i, err := index(key, len(parent)-1)
+			if err != nil {
+				return nil, err
+			}
+			value = parent[i]
+		default:
+			return nil, notFound(key)
+		}
+	}
+
+	return value, nil
+}
+
+// change func for change parent of the last reference token by fn, it returns
+// changed document. Value replaces document, if path is empty.
+func change(document interface{}, path []string, fn func(parent interface{}, key string) (interface{}, error), value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	if len(path) == 1 {
+		return fn(document, path[0])
+	}
+
+	child, err := get(document, path[:1])
+	if err != nil {
+		return nil, err
+	}
+	if child, err = change(child, path[1:], fn, value); err != nil {
+		return nil, err
+	}
+
+	return replaceValue(document, path[0], child)
+}
+
+func addValue(parent interface{}, key string, value interface{}) (interface{}, error) {
+	switch parent := parent.(type) {
+	case map[string]interface{}:
+		parent[key] = value
+		return parent, nil
+	case []interface{}:
+		if key == "-" {
+			return append(parent, value), nil
+		}
+		i, err := index(key, len(parent))
+		if err != nil {
+			return nil, err
+		}
+		parent = append(parent, nil)
+		copy(parent[i+1:], parent[i:])
+		parent[i] = value
+		return parent, nil
+	}
+
+	return nil, notFound(key)
+}
+
+func replaceValue(parent interface{}, key string, value interface{}) (interface{}, error) {
+	switch parent := parent.(type) {
+	case map[string]interface{}:
+		if _, ok := parent[key]; !ok {
+			return nil, notFound(key)
+		}
+		parent[key] = value
+		return parent, nil
+	case []interface{}:
+		i, err := index(key, len(parent)-1)
+		if err != nil {
+			return nil, err
+		}
+		parent[i] = value
+		return parent, nil
+	}
+
+	return nil, notFound(key)
+}
+
+func removeValue(parent interface{}, key string) (interface{}, error) {
+	switch parent := parent.(type) {
+	case map[string]interface{}:
+		if _, ok := parent[key]; !ok {
+			return nil, notFound(key)
+		}
+		delete(parent, key)
+		return parent, nil
+	case []interface{}:
+		i, err := index(key, len(parent)-1)
+		if err != nil {
+			return nil, err
+		}
+		return append(parent[:i], parent[i+1:]...), nil
+	}
+
+	return nil, notFound(key)
+}
+
+// index func for get index of array by reference token, which is not greater
+// than max. Leading zeros are not allowed.
+func index(key string, max int) (int, error) {
+	i, err := strconv.Atoi(key)
+	if err != nil || strings.Trim(key, "0123456789") != "" || (len(key) > 1 && key[0] == '0') {
+		return 0, fmt.Errorf("%w: %q is not index of array", ErrConflict, key)
+	}
+	if i > max {
+		return 0, fmt.Errorf("%w: index %d is out of bounds", ErrConflict, i)
+	}
+
+	return i, nil
+}
+
+func notFound(key string) error {
+	return fmt.Errorf("%w: %q is not found", ErrConflict, key)
+}
+
+// copyValue func for get deep copy of value of document.
+func copyValue(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(value))
+		for key, child := range value {
+			result[key] = copyValue(child)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(value))
+		for i, child := range value {
+			result[i] = copyValue(child)
+		}
+		return result
+	}
+
+	return value
+}
+
+// mergeValue func for apply changes of JSON Merge Patch to target value.
+func mergeValue(target, changes interface{}) interface{} {
+	patch, ok := changes.(map[string]interface{})
+	if !ok {
+		return changes
+	}
+
+	object, ok := target.(map[string]interface{})
+	if !ok {
+		object = map[string]interface{}{}
+	}
+	for key, value := range patch {
+		if value == nil {
+			delete(object, key)
+		} else {
+			object[key] = mergeValue(object[key], value)
+		}
+	}
+
+	return object
+}
+
+// diffValue func for get changes of JSON Merge Patch from before to after value.
+func diffValue(before, after interface{}) interface{} {
+	beforeObject, ok := before.(map[string]interface{})
+	afterObject, ok2 := after.(map[string]interface{})
+	if !ok || !ok2 {
+		return after
+	}
+
+	changes := map[string]interface{}{}
+	for key, value := range afterObject {
+		old, found := beforeObject[key]
+		if !found {
+			changes[key] = value
+		} else if !reflect.DeepEqual(old, value) {
+			changes[key] = diffValue(old, value)
+		}
+	}
+	for key := range beforeObject {
+		if _, found := afterObject[key]; !found {
+			changes[key] = nil
+		}
+	}
+
+	return changes
+}
@@ -0,0 +1,124 @@
+package jsonpatch
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const book = `{"title":"Go","author":"Rob","book_status":1,"book_attrs":{"picture":"go.png","description":"","rating":5},"tags":["a","b"]}`
+
+func TestMergePatch(t *testing.T) {
+	tests := []struct {
+		description string
+		patch       string
+		expected    string
+	}{
+		{"replace field", `{"title":"Go 2"}`, `{"title":"Go 2","author":"Rob","book_status":1,"book_attrs":{"picture":"go.png","description":"","rating":5},"tags":["a","b"]}`},
+		{"nested field", `{"book_attrs":{"rating":9}}`, `{"title":"Go","author":"Rob","book_status":1,"book_attrs":{"picture":"go.png","description":"","rating":9},"tags":["a","b"]}`},
+		{"null removes field", `{"book_attrs":{"picture":null},"tags":null}`, `{"title":"Go","author":"Rob","book_status":1,"book_attrs":{"description":"","rating":5}}`},
+		{"array is replaced", `{"tags":["c"]}`, `{"title":"Go","author":"Rob","book_status":1,"book_attrs":{"picture":"go.png","description":"","rating":5},"tags":["c"]}`},
+	}
+
+	for _, test := range tests {
+		patched, err := MergePatch([]byte(book), []byte(test.patch))
+		assert.NoErrorf(t, err, test.description)
+		assert.JSONEqf(t, test.expected, string(patched), test.description)
+	}
+
+	_, err := MergePatch([]byte(book), []byte(`{"title":`))
+	assert.True(t, errors.Is(err, ErrMalformed))
+}
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		description string
+		patch       string
+		expected    string
+		expectedErr error
+	}{
+		{
+			description: "replace nested field after test",
+			patch:       `[{"op":"test","path":"/book_attrs/rating","value":5},{"op":"replace","path":"/book_attrs/rating","value":7}]`,
+			expected:    `{"title":"Go","author":"Rob","book_status":1,"book_attrs":{"picture":"go.png","description":"","rating":7},"tags":["a","b"]}`,
+		},
+		{
+			description: "add, remove, move and copy",
+			patch: `[{"op":"add","path":"/tags/1","value":"x"},{"op":"add","path":"/tags/-","value":"z"},{"op":"remove","path":"/tags/0"},
+				{"op":"move","from":"/book_attrs/picture","path":"/picture"},{"op":"copy","from":"/title","path":"/book_attrs/description"}]`,
+			expected: `{"title":"Go","author":"Rob","book_status":1,"picture":"go.png","book_attrs":{"description":"Go","rating":5},"tags":["x","b","z"]}`,
+		},
+		{
+			description: "escaped path",
+			patch:       `[{"op":"add","path":"/a~1b~0c","value":true}]`,
+			expected:    `{"title":"Go","author":"Rob","book_status":1,"book_attrs":{"picture":"go.png","description":"","rating":5},"tags":["a","b"],"a/b~c":true}`,
+		},
+		{
+			description: "failed test",
+			patch:       `[{"op":"replace","path":"/title","value":"Go 2"},{"op":"test","path":"/book_attrs/rating","value":6}]`,
+			expectedErr: ErrConflict,
+		},
+		{
+			description: "replace unknown field",
+			patch:       `[{"op":"replace","path":"/book_attrs/pages","value":100}]`,
+			expectedErr: ErrConflict,
+		},
+		{
+			description: "index out of bounds",
+			patch:       `[{"op":"add","path":"/tags/3","value":"c"}]`,
+			expectedErr: ErrConflict,
+		},
+		{
+			description: "index with leading zero",
+			patch:       `[{"op":"remove","path":"/tags/01"}]`,
+			expectedErr: ErrConflict,
+		},
+		{
+			description: "move into itself",
+			patch:       `[{"op":"move","from":"/book_attrs","path":"/book_attrs/copy"}]`,
+			expectedErr: ErrConflict,
+		},
+		{
+			description: "unknown operation",
+			patch:       `[{"op":"merge","path":"/title","value":"Go 2"}]`,
+			expectedErr: ErrMalformed,
+		},
+		{
+			description: "operation without value",
+			patch:       `[{"op":"add","path":"/title"}]`,
+			expectedErr: ErrMalformed,
+		},
+		{
+			description: "object instead of operations",
+			patch:       `{"title":"Go 2"}`,
+			expectedErr: ErrMalformed,
+		},
+	}
+
+	for _, test := range tests {
+		patched, err := Apply([]byte(book), []byte(test.patch))
+		if test.expectedErr != nil {
+			assert.Truef(t, errors.Is(err, test.expectedErr), "%s: %v", test.description, err)
+			assert.Nilf(t, patched, test.description)
+			continue
+		}
+
+		assert.NoErrorf(t, err, test.description)
+		assert.JSONEqf(t, test.expected, string(patched), test.description)
+	}
+}
+
+func TestCreateMergePatch(t *testing.T) {
+	patched, err := Apply([]byte(book), []byte(`[{"op":"replace","path":"/book_attrs/rating","value":7},{"op":"remove","path":"/tags"}]`))
+	assert.NoError(t, err)
+
+	changes, err := CreateMergePatch([]byte(book), patched)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"book_attrs":{"rating":7},"tags":null}`, string(changes))
+
+	// Changes are the patch, which makes the same document.
+	merged, err := MergePatch([]byte(book), changes)
+	assert.NoError(t, err)
+	assert.JSONEq(t, string(patched), string(merged))
+}
@@ -0,0 +1,63 @@
+package lockout
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+// Policy struct to describe backoff of sign in after failed attempts. First
+// FreeAttempts failures are not delayed, each next one doubles the delay from
+// BaseDelay up to MaxDelay (lockout). Failures older than Window are forgotten.
+type Policy struct {
+	FreeAttempts int
+	BaseDelay    time.Duration
+	MaxDelay     time.Duration
+	Window       time.Duration
+}
+
+// UserPolicy func for get policy of failures per username from .env file.
+func UserPolicy() Policy {
+	return policyFromEnv("LOGIN_USER_FREE_ATTEMPTS", 5)
+}
+
+// IPPolicy func for get policy of failures per IP from .env file. Many users
+// may share IP, so it allows more failures than policy of username.
+func IPPolicy() Policy {
+	return policyFromEnv("LOGIN_IP_FREE_ATTEMPTS", 20)
+}
+
+// Delay method for get time to wait before the next attempt after the given count of failures.
+func (p Policy) Delay(failures int) time.Duration {
+	if failures <= p.FreeAttempts {
+		return 0
+	}
+
+	delay := p.BaseDelay
+	for i := p.FreeAttempts + 1; i < failures && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+
+	return delay
+}
+
+func policyFromEnv(freeAttemptsKey string, defaultFreeAttempts int) Policy {
+	return Policy{
+		FreeAttempts: envInt(freeAttemptsKey, defaultFreeAttempts),
+		BaseDelay:    time.Duration(envInt("LOGIN_BACKOFF_BASE_SECONDS", 1)) * time.Second,
+		MaxDelay:     time.Duration(envInt("LOGIN_LOCKOUT_MAX_MINUTES", 15)) * time.Minute,
+		Window:       time.Duration(envInt("LOGIN_ATTEMPTS_WINDOW_MINUTES", 60)) * time.Minute,
+	}
+}
+
+func envInt(key string, defaultValue int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || value < 0 {
+		return defaultValue
+	}
+
+	return value
+}
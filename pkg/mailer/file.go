@@ -0,0 +1,32 @@
+package mailer
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// FileMailer struct to describe transport, which writes emails to files
+// of directory instead of sending them, for development.
+type FileMailer struct {
+	Dir  string
+	From string
+}
+
+// Send method for write message to a new ".eml" file of directory.
+func (f *FileMailer) Send(m *Message) error {
+	now := time.Now()
+	body, err := buildMIME(f.From, m, now)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(f.Dir, 0o700); err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%s-%09d.eml", now.Format("20060102-150405"), now.Nanosecond())
+
+	return ioutil.WriteFile(filepath.Join(f.Dir, name), body, 0o600)
+}
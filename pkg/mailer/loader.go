@@ -0,0 +1,55 @@
+package mailer
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+var (
+	current     Mailer
+	currentErr  error
+	currentOnce sync.Once
+)
+
+// CurrentMailer func for get transport of emails of app, see FromEnv.
+func CurrentMailer() (Mailer, error) {
+	currentOnce.Do(func() {
+		current, currentErr = FromEnv()
+	})
+
+	return current, currentErr
+}
+
+// FromEnv func for make a new transport of emails from .env file:
+//   - MAIL_TRANSPORT: "smtp", "file" (default) or "memory";
+//   - MAIL_FROM: address of sender;
+//   - SMTP_HOST, SMTP_PORT (default 587), SMTP_USERNAME, SMTP_PASSWORD: server of "smtp" transport;
+//   - MAIL_FILE_DIR: directory of "file" transport (default "mail").
+func FromEnv() (Mailer, error) {
+	from := os.Getenv("MAIL_FROM")
+
+	switch transport := os.Getenv("MAIL_TRANSPORT"); transport {
+	case "smtp":
+		port := os.Getenv("SMTP_PORT")
+		if port == "" {
+			port = "587"
+		}
+		return &SMTPMailer{
+			Addr:     os.Getenv("SMTP_HOST") + ":" + port,
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+			From:     from,
+		}, nil
+	case "", "file":
+		dir := os.Getenv("MAIL_FILE_DIR")
+		if dir == "" {
+			dir = "mail"
+		}
+		return &FileMailer{Dir: dir, From: from}, nil
+	case "memory":
+		return &MemoryMailer{}, nil
+	default:
+		return nil, fmt.Errorf("MAIL_TRANSPORT %q is not supported", transport)
+	}
+}
@@ -0,0 +1,14 @@
+package mailer
+
+// Message struct to describe email with text and HTML versions of body.
+type Message struct {
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Mailer interface to describe transport of emails.
+type Mailer interface {
+	Send(m *Message) error
+}
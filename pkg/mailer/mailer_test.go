@@ -0,0 +1,87 @@
+package mailer
+
+import (
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender(t *testing.T) {
+	message, err := Render("john@example.com", "password_reset", map[string]string{
+		"Username":  "<john>",
+		"Link":      "https://example.com/reset?token=abc",
+		"ExpiresIn": "30 minutes",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "john@example.com", message.To)
+	assert.Equal(t, "Reset your password", message.Subject)
+	assert.Contains(t, message.Text, "Hello <john>,")
+	assert.Contains(t, message.Text, "https://example.com/reset?token=abc")
+	assert.Contains(t, message.HTML, "Hello &lt;john&gt;,")
+	assert.Contains(t, message.HTML, `href="https://example.com/reset?token=abc"`)
+
+	_, err = Render("john@example.com", "unknown", nil)
+	assert.Error(t, err)
+}
+
+func TestMemoryMailer(t *testing.T) {
+	m := &MemoryMailer{}
+	require.NoError(t, m.Send(&Message{To: "john@example.com", Subject: "Hello"}))
+
+	messages := m.Messages()
+	require.Len(t, messages, 1)
+	assert.Equal(t, "Hello", messages[0].Subject)
+}
+
+func TestFileMailer(t *testing.T) {
+	m := &FileMailer{Dir: filepath.Join(t.TempDir(), "mail"), From: "noreply@example.com"}
+	require.NoError(t, m.Send(&Message{
+		To:      "john@example.com",
+		Subject: "Привет",
+		Text:    "text body",
+		HTML:    "<p>html body</p>",
+	}))
+
+	files, err := filepath.Glob(filepath.Join(m.Dir, "*.eml"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	data, err := ioutil.ReadFile(files[0])
+	require.NoError(t, err)
+
+	// File is a valid email with text and HTML parts.
+	email, err := mail.ReadMessage(strings.NewReader(string(data)))
+	require.NoError(t, err)
+	assert.Equal(t, "noreply@example.com", email.Header.Get("From"))
+	subject, err := new(mime.WordDecoder).DecodeHeader(email.Header.Get("Subject"))
+	require.NoError(t, err)
+	assert.Equal(t, "Привет", subject)
+	date, err := email.Header.Date()
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), date, time.Minute)
+
+	mediaType, params, err := mime.ParseMediaType(email.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	assert.Equal(t, "multipart/alternative", mediaType)
+
+	reader := multipart.NewReader(email.Body, params["boundary"])
+	bodies := []string{}
+	for {
+		part, err := reader.NextPart()
+		if err != nil {
+			break
+		}
+		body, err := ioutil.ReadAll(part)
+		require.NoError(t, err)
+		bodies = append(bodies, string(body))
+	}
+	assert.Equal(t, []string{"text body", "<p>html body</p>"}, bodies)
+}
@@ -0,0 +1,27 @@
+package mailer
+
+import "sync"
+
+// MemoryMailer struct to describe transport, which keeps emails in memory, for tests.
+type MemoryMailer struct {
+	mu       sync.Mutex
+	messages []Message
+}
+
+// Send method for keep message in memory.
+func (m *MemoryMailer) Send(message *Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.messages = append(m.messages, *message)
+
+	return nil
+}
+
+// Messages method for get all sent messages.
+func (m *MemoryMailer) Messages() []Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]Message(nil), m.messages...)
+}
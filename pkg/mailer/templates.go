@@ -0,0 +1,51 @@
+package mailer
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	htmlTemplate "html/template"
+	"strings"
+	textTemplate "text/template"
+)
+
+// templateFiles has text and HTML templates of every email: "<name>.txt" with
+// "subject" and "body" blocks, and "<name>.html" with the HTML body.
+//
+//go:embed templates
+var templateFiles embed.FS
+
+var (
+	textTemplates = textTemplate.Must(textTemplate.ParseFS(templateFiles, "templates/*.txt"))
+	htmlTemplates = htmlTemplate.Must(htmlTemplate.ParseFS(templateFiles, "templates/*.html"))
+)
+
+// Render func for make message to the given address by templates of the given name.
+// Values of data are escaped in the HTML body.
+func Render(to, name string, data interface{}) (*Message, error) {
+	text := textTemplates.Lookup(name + ".txt")
+	html := htmlTemplates.Lookup(name + ".html")
+	if text == nil || html == nil {
+		return nil, errors.New("mail template " + name + " is not found")
+	}
+
+	subject := &bytes.Buffer{}
+	if err := text.ExecuteTemplate(subject, name+".subject", data); err != nil {
+		return nil, err
+	}
+	textBody := &bytes.Buffer{}
+	if err := text.Execute(textBody, data); err != nil {
+		return nil, err
+	}
+	htmlBody := &bytes.Buffer{}
+	if err := html.Execute(htmlBody, data); err != nil {
+		return nil, err
+	}
+
+	return &Message{
+		To:      to,
+		Subject: strings.TrimSpace(subject.String()),
+		Text:    strings.TrimSpace(textBody.String()) + "\n",
+		HTML:    htmlBody.String(),
+	}, nil
+}
@@ -0,0 +1,146 @@
+package middleware
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/acl"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/problem"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/repository"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/utils"
+)
+
+// AccessControlled func for specify route with access control by grants.
+// Action is one of create, read, update or delete. Attributes of request body
+// are validated and attributes of response are filtered by the grant.
+func AccessControlled(resource, action string) func(*fiber.Ctx) error {
+	return func(c *fiber.Ctx) error {
+		// Get roles of principal, callers without token are anonymous.
+		roles := []string{repository.AnonymousRoleName}
+		principal, err := requestPrincipal(c)
+		if err != nil {
+			return sessionError(c, err)
+		}
+		if principal != nil {
+			roles = principal.Roles
+		}
+
+		// Check permission of roles by the current policy.
+		permission := acl.CurrentPolicy().Permission(roles, resource, action)
+		if !permission.Granted {
+			return aclForbidden(c)
+		}
+
+		// Checking, if scopes of principal (API keys) allow the action.
+		if principal != nil && !principal.AllowsScope(resource, action) {
+			return aclForbidden(c)
+		}
+
+		// Checking, if request body has only allowed attributes.
+		if action == "create" || action == "update" {
+			body := map[string]interface{}{}
+			if err := json.Unmarshal(c.Body(), &body); err == nil && len(permission.InvalidAttributes(body)) > 0 {
+				return aclForbidden(c)
+			}
+		}
+
+		// Store permission for controllers.
+		acl.SetPermission(c, permission)
+
+		if err := c.Next(); err != nil {
+			return err
+		}
+
+		return filterResponse(c, permission)
+	}
+}
+
+// Identified func for specify route, which is open for anonymous callers.
+// Principal of callers with credentials is resolved, like for AccessControlled,
+// so controllers check permissions by themselves.
+func Identified() func(*fiber.Ctx) error {
+	return func(c *fiber.Ctx) error {
+		if _, err := requestPrincipal(c); err != nil {
+			return sessionError(c, err)
+		}
+
+		return c.Next()
+	}
+}
+
+// requestPrincipal func for get principal of request by API key, token, session
+// cookie or client certificate, and store it for controllers. Public routes
+// have no auth middleware, so credentials are checked here. It returns nil
+// principal for callers without credentials.
+func requestPrincipal(c *fiber.Ctx) (*utils.Principal, error) {
+	if principal, err := utils.GetPrincipal(c); err == nil {
+		return principal, nil
+	}
+
+	var principal *utils.Principal
+	var err error
+	if apiKeyFromRequest(c) != "" {
+		principal, err = authenticateAPIKey(c)
+	} else if c.Get(fiber.HeaderAuthorization) != "" {
+		if principal, err = utils.ExtractPrincipal(c); err == nil {
+			err = notRevoked(principal)
+		}
+	} else if principal, err = sessionPrincipal(c); err == nil && principal == nil {
+		principal, err = clientCertPrincipal(c)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Store principal for controllers.
+	if principal != nil {
+		utils.SetPrincipal(c, principal)
+	}
+
+	return principal, nil
+}
+
+// filterResponse func for remove attributes, which are not allowed by permission,
+// from records of a successful JSON response.
+func filterResponse(c *fiber.Ctx, permission acl.Permission) error {
+	if permission.AllowsAll() {
+		return nil
+	}
+
+	status := c.Response().StatusCode()
+	contentType := string(c.Response().Header.ContentType())
+	if status < 200 || status > 299 || !strings.HasPrefix(contentType, fiber.MIMEApplicationJSON) {
+		return nil
+	}
+
+	response := map[string]interface{}{}
+	if err := json.Unmarshal(c.Response().Body(), &response); err != nil {
+		return nil
+	}
+
+	for key, value := range response {
+		switch key {
+		case "error", "msg", "count":
+			continue
+		}
+
+		switch records := value.(type) {
+		case map[string]interface{}:
+			response[key] = permission.Filter(records)
+		case []interface{}:
+			for i, record := range records {
+				if object, ok := record.(map[string]interface{}); ok {
+					records[i] = permission.Filter(object)
+				}
+			}
+		}
+	}
+
+	return c.JSON(response)
+}
+
+func aclForbidden(c *fiber.Ctx) error {
+	// Return status 403 and permission denied error.
+	return problem.Forbidden("permission denied, check credentials of your token")
+}
@@ -0,0 +1,136 @@
+package middleware
+
+import (
+	"errors"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/koddr/tutorial-go-fiber-rest-api/app/models"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/utils"
+	"github.com/koddr/tutorial-go-fiber-rest-api/platform/database"
+)
+
+// Protected func for specify routes group with authentication by JWT or API key.
+// API key is sent in the X-API-Key header or as a bearer token.
+func Protected() func(*fiber.Ctx) error {
+	jwtProtected := JWTProtected()
+
+	return func(c *fiber.Ctx) error {
+		// Routes are protected by JWT, if there is no API key.
+		if apiKeyFromRequest(c) == "" {
+			return jwtProtected(c)
+		}
+
+		// API key is already authenticated by rate limiting of routes group.
+		if _, err := utils.GetPrincipal(c); err == nil {
+			return c.Next()
+		}
+
+		principal, err := authenticateAPIKey(c)
+		if err != nil {
+			return jwtError(c, err)
+		}
+
+		// Store principal for controllers.
+		utils.SetPrincipal(c, principal)
+
+		return c.Next()
+	}
+}
+
+// apiKeyFromRequest func for get API key of request, or empty string.
+func apiKeyFromRequest(c *fiber.Ctx) string {
+	if key := c.Get("X-API-Key"); key != "" {
+		return key
+	}
+
+	if token := strings.TrimPrefix(c.Get(fiber.HeaderAuthorization), "Bearer "); utils.IsAPIKey(token) {
+		return token
+	}
+
+	return ""
+}
+
+// authenticateAPIKey func for make principal of user by API key of request.
+// Principal has scopes of the key and roles of its user.
+func authenticateAPIKey(c *fiber.Ctx) (*utils.Principal, error) {
+	// Create database connection.
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		return nil, err
+	}
+
+	// Get API key by its hash.
+	key, err := db.GetAPIKeyByHash(utils.HashToken(apiKeyFromRequest(c)))
+	if err != nil {
+		return nil, errors.New("API key is not valid")
+	}
+
+	// Checking, if API key is still active.
+	if key.RevokedAt != nil {
+		return nil, errors.New("API key is revoked")
+	}
+	if key.ExpiresAt != nil && time.Now().After(*key.ExpiresAt) {
+		return nil, errors.New("API key is expired")
+	}
+
+	// Checking, if API key is used from the allowed IP.
+	if !ipAllowed(key.AllowedIPs, c.IP()) {
+		return nil, errors.New("API key is not allowed from this IP")
+	}
+
+	// Roles are taken from user, so changes of roles apply to keys at once.
+	user, err := db.GetUserByID(key.UserID)
+	if err != nil {
+		return nil, errors.New("API key is not valid")
+	}
+
+	// Roles, which require the second factor, are granted only to keys of users with it.
+	twoFactor, err := db.HasTwoFactor(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Tracking of usage must not fail the request.
+	_ = db.TouchAPIKey(key.ID)
+
+	principal := &utils.Principal{
+		UserID:  user.ID,
+		Subject: user.ID.String(),
+		Roles:   utils.EffectiveRoles(user.Roles, twoFactor),
+		Scopes:  key.Scopes,
+		TokenID: key.ID.String(),
+	}
+	if key.ExpiresAt != nil {
+		principal.Expires = key.ExpiresAt.Unix()
+	}
+
+	return principal, nil
+}
+
+// ipAllowed func for checking, if IP is in the allowlist of IPs and CIDRs.
+// Empty allowlist allows any IP.
+func ipAllowed(allowed models.StringList, remote string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+
+	ip := net.ParseIP(remote)
+	if ip == nil {
+		return false
+	}
+
+	for _, entry := range allowed {
+		if _, network, err := net.ParseCIDR(entry); err == nil {
+			if network.Contains(ip) {
+				return true
+			}
+		} else if allowedIP := net.ParseIP(entry); allowedIP != nil && allowedIP.Equal(ip) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"testing"
+
+	"github.com/koddr/tutorial-go-fiber-rest-api/app/models"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIPAllowed(t *testing.T) {
+	tests := []struct {
+		allowed  models.StringList
+		remote   string
+		expected bool
+	}{
+		{nil, "203.0.113.7", true},
+		{models.StringList{"203.0.113.7"}, "203.0.113.7", true},
+		{models.StringList{"203.0.113.7"}, "203.0.113.8", false},
+		{models.StringList{"10.0.0.0/8", "2001:db8::/32"}, "10.1.2.3", true},
+		{models.StringList{"10.0.0.0/8", "2001:db8::/32"}, "2001:db8::1", true},
+		{models.StringList{"10.0.0.0/8"}, "11.0.0.1", false},
+		{models.StringList{"10.0.0.0/8"}, "not-an-ip", false},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, ipAllowed(test.allowed, test.remote), test.remote)
+	}
+}
+
+func TestPrincipalAllowsScope(t *testing.T) {
+	user := &utils.Principal{}
+	assert.True(t, user.AllowsScope("servers", "create"))
+
+	key := &utils.Principal{Scopes: []string{"servers:write", "books:read"}}
+	assert.True(t, key.AllowsScope("servers", "create"))
+	assert.True(t, key.AllowsScope("servers", "delete"))
+	assert.False(t, key.AllowsScope("servers", "read"))
+	assert.True(t, key.AllowsScope("books", "read"))
+	assert.False(t, key.AllowsScope("books", "update"))
+}
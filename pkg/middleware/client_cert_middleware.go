@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/koddr/tutorial-go-fiber-rest-api/app/models"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/mtls"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/utils"
+	"github.com/koddr/tutorial-go-fiber-rest-api/platform/database"
+)
+
+// clientCertPrincipal func for make principal by verified client certificate
+// of TLS connection. It returns nil, if request has no client certificate.
+func clientCertPrincipal(c *fiber.Ctx) (*utils.Principal, error) {
+	state := c.Context().TLSConnectionState()
+	if state == nil || len(state.VerifiedChains) == 0 {
+		return nil, nil
+	}
+
+	// Only certificates of identities listed in config are authenticated.
+	config := mtls.CurrentConfig()
+	cert := state.VerifiedChains[0][0]
+	identity, roles, ok := config.Lookup(cert)
+	if !ok {
+		return nil, errors.New("client certificate is not allowed")
+	}
+
+	// Records of certificate are owned by its service account.
+	account, err := clientCertServiceAccount(identity, roles, config.Organizations[identity])
+	if err != nil {
+		return nil, err
+	}
+
+	return &utils.Principal{
+		UserID:  account.UserID,
+		Subject: identity,
+		Roles:   roles,
+		Expires: cert.NotAfter.Unix(),
+	}, nil
+}
+
+// clientCertServiceAccount func for get service account of identity of client
+// certificate. Identities are registered in config, so service account is
+// created on the first request of identity.
+func clientCertServiceAccount(identity string, roles []string, organizationID uuid.UUID) (models.ServiceAccount, error) {
+	// Create database connection.
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		return models.ServiceAccount{}, err
+	}
+
+	// Get service account of identity.
+	name := models.ClientCertIdentityPrefix + identity
+	if account, err := db.GetServiceAccount(name); err == nil {
+		return account, nil
+	}
+
+	// Create a new service account, concurrent request may create it first.
+	account := models.ServiceAccount{UserID: uuid.New(), CreatedAt: time.Now(), Identity: name}
+	if err := db.CreateServiceAccount(&account, roles, organizationID, ""); err != nil {
+		if existing, getErr := db.GetServiceAccount(name); getErr == nil {
+			return existing, nil
+		}
+		return account, err
+	}
+
+	return account, nil
+}
@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"os"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/gofiber/fiber/v2/middleware/logger"
@@ -11,8 +13,23 @@ import (
 func FiberMiddleware(a *fiber.App) {
 	a.Use(
 		// Add CORS to each route.
-		cors.New(),
+		cors.New(corsConfig()),
 		// Add simple logger.
 		logger.New(),
 	)
 }
+
+// corsConfig func for allow credentials (cookies of sessions) only for origins
+// from CORS_ALLOW_ORIGINS, any origin is allowed without credentials by default.
+func corsConfig() cors.Config {
+	origins := os.Getenv("CORS_ALLOW_ORIGINS")
+	if origins == "" {
+		return cors.ConfigDefault
+	}
+
+	return cors.Config{
+		AllowOrigins:     origins,
+		AllowMethods:     cors.ConfigDefault.AllowMethods,
+		AllowCredentials: true,
+	}
+}
@@ -1,38 +1,106 @@
 package middleware
 
 import (
-	"os"
+	"errors"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/problem"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/revocation"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/utils"
 
 	jwtMiddleware "github.com/gofiber/jwt/v2"
 )
 
 // JWTProtected func for specify routes group with JWT authentication.
+// Requests without JWT are authenticated by session cookie of browser,
+// or by client certificate of TLS connection.
 // See: https://github.com/gofiber/jwt
 func JWTProtected() func(*fiber.Ctx) error {
+	// Get keys for verifying tokens.
+	keys, err := utils.JWTKeySet()
+	if err != nil {
+		panic(err)
+	}
+
 	// Create config for JWT authentication middleware.
 	config := jwtMiddleware.Config{
-		SigningKey:   []byte(os.Getenv("JWT_SECRET_KEY")),
-		ContextKey:   "jwt", // used in private routes
-		ErrorHandler: jwtError,
+		SigningMethod:  keys.Method.Alg(),
+		ContextKey:     "jwt", // used in private routes
+		ErrorHandler:   jwtError,
+		SuccessHandler: jwtSuccess,
+	}
+
+	// Asymmetric keys are selected by ID of key from the token header.
+	if keys.SigningKID != "" {
+		config.SigningKeys = keys.VerifyKeys
+	} else {
+		config.SigningKey = keys.SigningKey
+	}
+
+	jwtProtected := jwtMiddleware.New(config)
+
+	return func(c *fiber.Ctx) error {
+		// Requests without token are authenticated by session cookie, or by client certificate, if any.
+		if c.Get(fiber.HeaderAuthorization) == "" {
+			principal, err := sessionPrincipal(c)
+			if err != nil {
+				return sessionError(c, err)
+			}
+			if principal != nil {
+				// Store principal for controllers.
+				utils.SetPrincipal(c, principal)
+
+				return c.Next()
+			}
+
+			principal, err = clientCertPrincipal(c)
+			if err != nil {
+				return jwtError(c, err)
+			}
+			if principal != nil {
+				// Store principal for controllers.
+				utils.SetPrincipal(c, principal)
+
+				return c.Next()
+			}
+		}
+
+		return jwtProtected(c)
+	}
+}
+
+func jwtSuccess(c *fiber.Ctx) error {
+	// Parse claims of the verified token into principal only once per request.
+	principal, err := utils.NewPrincipal(c.Locals("jwt").(*jwt.Token))
+	if err != nil {
+		return jwtError(c, err)
+	}
+	if err := notRevoked(principal); err != nil {
+		return jwtError(c, err)
+	}
+
+	// Store principal for controllers.
+	utils.SetPrincipal(c, principal)
+
+	return c.Next()
+}
+
+// notRevoked func for checking, if token of principal is not revoked before its expiration.
+func notRevoked(principal *utils.Principal) error {
+	if revocation.CurrentDenylist().IsRevoked(principal.TokenID) {
+		return errors.New("token is revoked")
 	}
 
-	return jwtMiddleware.New(config)
+	return nil
 }
 
 func jwtError(c *fiber.Ctx, err error) error {
 	// Return status 401 and failed authentication error.
 	if err.Error() == "Missing or malformed JWT" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": true,
-			"msg":   err.Error(),
-		})
+		return problem.BadRequest(err.Error())
 	}
 
 	// Return status 401 and failed authentication error.
-	return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-		"error": true,
-		"msg":   err.Error(),
-	})
+	return problem.Unauthorized(err.Error())
 }
@@ -0,0 +1,171 @@
+package middleware
+
+import (
+	"log"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/problem"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/ratelimit"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/utils"
+	"github.com/koddr/tutorial-go-fiber-rest-api/platform/database"
+)
+
+var (
+	rateLimitStore     ratelimit.Store
+	rateLimitStoreOnce sync.Once
+
+	// Buckets of process are used, while store is not available.
+	rateLimitFallback = ratelimit.NewMemoryStore()
+
+	rateLimitErrorLogged time.Time
+	rateLimitErrorMutex  sync.Mutex
+)
+
+// RateLimited func for specify routes group with rate limiting by token bucket.
+// Limit of group is set in .env file as RATE_LIMIT_<GROUP>, like
+// RATE_LIMIT_API="120/1m", and routes are not limited, if it's not set.
+// Buckets are kept in store of RATE_LIMIT_STORE ("memory", "postgres" or "redis").
+func RateLimited(group string) func(*fiber.Ctx) error {
+	limit, err := ratelimit.ParseLimit(os.Getenv("RATE_LIMIT_" + strings.ToUpper(group)))
+	if err != nil {
+		panic(err)
+	}
+	if limit == nil {
+		return func(c *fiber.Ctx) error {
+			return c.Next()
+		}
+	}
+
+	rateLimitStoreOnce.Do(func() {
+		rateLimitStore = newRateLimitStore(os.Getenv("RATE_LIMIT_STORE"))
+	})
+
+	return func(c *fiber.Ctx) error {
+		key, now := group+":"+rateLimitKey(c), time.Now()
+		result, err := rateLimitStore.Take(key, *limit, now)
+		if err != nil {
+			// Limit is checked per process, if store is not available
+			// (memory store doesn't fail).
+			logRateLimitError(err)
+			result, _ = rateLimitFallback.Take(key, *limit, now)
+		}
+
+		// Set headers of rate limit (draft-ietf-httpapi-ratelimit-headers).
+		c.Set("RateLimit-Limit", strconv.Itoa(limit.Capacity))
+		c.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Set("RateLimit-Reset", ceilSeconds(result.Reset))
+		c.Set("RateLimit-Policy", strconv.Itoa(limit.Capacity)+";w="+ceilSeconds(limit.Period))
+
+		if !result.Allowed {
+			c.Set(fiber.HeaderRetryAfter, ceilSeconds(result.RetryAfter))
+
+			// Return status 429 and rate limit error.
+			return problem.TooManyRequests("too many requests, retry later")
+		}
+
+		return c.Next()
+	}
+}
+
+// RateLimitedWhen func for specify route with rate limiting of group, like
+// RateLimited, only for requests, which match, like GraphQL requests with
+// login mutation. Other requests of route are not limited by the group.
+func RateLimitedWhen(group string, match func(c *fiber.Ctx) bool) func(*fiber.Ctx) error {
+	limited := RateLimited(group)
+
+	return func(c *fiber.Ctx) error {
+		if !match(c) {
+			return c.Next()
+		}
+
+		return limited(c)
+	}
+}
+
+// rateLimitKey func for get key of bucket: ID of a valid API key, subject of
+// a valid JWT, or IP of client for anonymous requests and invalid API keys.
+func rateLimitKey(c *fiber.Ctx) string {
+	// Each API key has its own bucket, so keys behind the same IP don't share it.
+	if apiKeyFromRequest(c) != "" {
+		principal, err := utils.GetPrincipal(c)
+		if err != nil {
+			// Key is authenticated once, principal is used by Protected later.
+			if principal, err = authenticateAPIKey(c); err == nil {
+				utils.SetPrincipal(c, principal)
+			}
+		}
+		if err != nil {
+			return "ip:" + c.IP()
+		}
+		return "key:" + principal.TokenID
+	}
+
+	if principal, err := utils.GetPrincipal(c); err == nil {
+		return "sub:" + principal.Subject
+	}
+
+	if c.Get(fiber.HeaderAuthorization) != "" {
+		if principal, err := utils.ExtractPrincipal(c); err == nil {
+			return "sub:" + principal.Subject
+		}
+	}
+
+	return "ip:" + c.IP()
+}
+
+// logRateLimitError func for log error of store at most once a minute, because
+// it happens on each request, until store is available again.
+func logRateLimitError(err error) {
+	rateLimitErrorMutex.Lock()
+	defer rateLimitErrorMutex.Unlock()
+
+	if time.Since(rateLimitErrorLogged) < time.Minute {
+		return
+	}
+	rateLimitErrorLogged = time.Now()
+
+	log.Printf("Oops... Rate limit store is not available, memory is used! Reason: %v", err)
+}
+
+// newRateLimitStore func for create store of buckets by its name.
+func newRateLimitStore(name string) ratelimit.Store {
+	if name == "redis" {
+		store, err := ratelimit.NewRedisStore(os.Getenv("RATE_LIMIT_REDIS_URL"))
+		if err != nil {
+			panic(err)
+		}
+		return store
+	}
+	if name != "postgres" {
+		return ratelimit.NewMemoryStore()
+	}
+
+	db, err := database.SharedConnection()
+	if err != nil {
+		// Limits are kept per process, until database is available on restart.
+		log.Printf("Oops... Rate limit store is not connected, memory is used! Reason: %v", err)
+		return ratelimit.NewMemoryStore()
+	}
+	store := &ratelimit.PostgresStore{DB: db}
+
+	// Delete buckets of clients, which are gone.
+	go func() {
+		for range time.Tick(time.Hour) {
+			if err := store.Prune(24 * time.Hour); err != nil {
+				log.Printf("Oops... Rate limit buckets are not pruned! Reason: %v", err)
+			}
+		}
+	}()
+
+	return store
+}
+
+func ceilSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
@@ -0,0 +1,133 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/problem"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/ratelimit"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimited(t *testing.T) {
+	os.Setenv("RATE_LIMIT_TEST", "2/1m")
+	defer os.Unsetenv("RATE_LIMIT_TEST")
+
+	app := fiber.New(fiber.Config{ErrorHandler: problem.Handler})
+	app.Get("/", RateLimited("test"), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+	app.Get("/free", RateLimited("unlimited"), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	for i, expected := range []int{200, 200, 429} {
+		resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, expected, resp.StatusCode, i)
+		assert.Equal(t, "2", resp.Header.Get("RateLimit-Limit"))
+		assert.Equal(t, "2;w=60", resp.Header.Get("RateLimit-Policy"))
+
+		if expected == 429 {
+			assert.Equal(t, "0", resp.Header.Get("RateLimit-Remaining"))
+			assert.Equal(t, "30", resp.Header.Get("Retry-After"))
+		}
+	}
+
+	// Routes without limit have no headers of rate limit.
+	resp, err := app.Test(httptest.NewRequest("GET", "/free", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Empty(t, resp.Header.Get("RateLimit-Limit"))
+}
+
+func TestRateLimitedAPIKeys(t *testing.T) {
+	os.Setenv("RATE_LIMIT_KEYS", "2/1m")
+	defer os.Unsetenv("RATE_LIMIT_KEYS")
+
+	// Keys of the same user are authenticated before rate limiting.
+	userID := uuid.New()
+	keyIDs := map[string]string{"ak_first": uuid.NewString(), "ak_second": uuid.NewString()}
+	authenticated := func(c *fiber.Ctx) error {
+		if keyID, ok := keyIDs[c.Get("X-API-Key")]; ok {
+			utils.SetPrincipal(c, &utils.Principal{UserID: userID, Subject: userID.String(), TokenID: keyID})
+		}
+		return c.Next()
+	}
+
+	app := fiber.New(fiber.Config{ErrorHandler: problem.Handler})
+	app.Get("/", authenticated, RateLimited("keys"), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	tests := []struct {
+		description  string
+		key          string
+		expectedCode int
+	}{
+		{"first request of key", "ak_first", 200},
+		{"second request of key", "ak_first", 200},
+		{"bucket of key is empty", "ak_first", 429},
+		{"other key of the same user and IP has its own bucket", "ak_second", 200},
+		{"anonymous request of the same IP has its own bucket", "", 200},
+		{"invalid key uses bucket of IP", "ak_invalid", 200},
+		{"bucket of IP is empty", "ak_invalid", 429},
+	}
+
+	for _, test := range tests {
+		req := httptest.NewRequest("GET", "/", nil)
+		if test.key != "" {
+			req.Header.Set("X-API-Key", test.key)
+		}
+
+		resp, err := app.Test(req, -1)
+		assert.NoError(t, err, test.description)
+		assert.Equal(t, test.expectedCode, resp.StatusCode, test.description)
+	}
+}
+
+// brokenStore is store of buckets, which is not available.
+type brokenStore struct{}
+
+func (brokenStore) Take(key string, limit ratelimit.Limit, now time.Time) (ratelimit.Result, error) {
+	return ratelimit.Result{}, errors.New("store is not available")
+}
+
+func TestRateLimitedBrokenStore(t *testing.T) {
+	os.Setenv("RATE_LIMIT_BROKEN", "2/1m")
+	defer os.Unsetenv("RATE_LIMIT_BROKEN")
+
+	app := fiber.New(fiber.Config{ErrorHandler: problem.Handler})
+	app.Get("/", RateLimited("broken"), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	// Store is broken after it's created by the first limited route.
+	store := rateLimitStore
+	rateLimitStore = brokenStore{}
+	defer func() { rateLimitStore = store }()
+
+	output := &bytes.Buffer{}
+	log.SetOutput(output)
+	defer log.SetOutput(os.Stderr)
+	rateLimitErrorLogged = time.Time{}
+
+	// Limit is still checked by buckets of process.
+	for i, expected := range []int{200, 200, 429} {
+		resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, expected, resp.StatusCode, i)
+	}
+
+	// Error of store is logged once, not on each request.
+	assert.Equal(t, 1, strings.Count(output.String(), "Oops... Rate limit store"))
+}
@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/problem"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/session"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/utils"
+	"github.com/koddr/tutorial-go-fiber-rest-api/platform/database"
+)
+
+// sessionPrincipal func for make principal of user by session cookie of request.
+// Unsafe requests must have CSRF token of session. It returns nil principal,
+// if there is no session cookie.
+func sessionPrincipal(c *fiber.Ctx) (*utils.Principal, error) {
+	cfg := session.ConfigFromEnv()
+	token := cfg.Token(c)
+	if token == "" {
+		return nil, nil
+	}
+
+	// Create database connection.
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		return nil, err
+	}
+
+	// Get active session by hash of token.
+	s, err := db.GetSessionByHash(utils.HashToken(token))
+	if err != nil {
+		return nil, errors.New("session is not valid")
+	}
+
+	// Checking, if session is expired by idle or absolute timeout.
+	now := time.Now()
+	if cfg.Expired(s.LastSeenAt, s.ExpiresAt, now) {
+		return nil, errors.New("session is expired")
+	}
+
+	// Double-submitted CSRF token must be the token of this session.
+	if !session.IsSafeMethod(c.Method()) {
+		csrfToken, err := cfg.CSRFToken(c)
+		if err != nil {
+			return nil, err
+		}
+		if subtle.ConstantTimeCompare([]byte(utils.HashToken(csrfToken)), []byte(s.CSRFTokenHash)) != 1 {
+			return nil, session.ErrCSRF
+		}
+	}
+
+	// Roles are taken from user, so changes of roles apply to sessions at once.
+	user, err := db.GetUserByID(s.UserID)
+	if err != nil {
+		return nil, errors.New("session is not valid")
+	}
+
+	// Tracking of activity must not fail the request.
+	if cfg.NeedsTouch(s.LastSeenAt, now) {
+		_ = db.TouchSession(s.ID)
+	}
+
+	return &utils.Principal{
+		UserID:   user.ID,
+		Subject:  user.ID.String(),
+		Roles:    utils.EffectiveRoles(user.Roles, s.TwoFactor),
+		TokenID:  s.ID.String(),
+		IssuedAt: s.CreatedAt.Unix(),
+		Expires:  s.ExpiresAt.Unix(),
+	}, nil
+}
+
+// sessionError func for return status 403 for requests without CSRF token,
+// and status 401 for other errors of session.
+func sessionError(c *fiber.Ctx, err error) error {
+	if errors.Is(err, session.ErrCSRF) {
+		// Return status 403 and CSRF error.
+		return problem.Forbidden(err.Error())
+	}
+
+	return jwtError(c, err)
+}
@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/problem"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/repository"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/utils"
+	"github.com/koddr/tutorial-go-fiber-rest-api/platform/database"
+)
+
+// OrganizationHeader is a header of request with ID of organization, which
+// records of request belong to. Default organization of user is used without it.
+const OrganizationHeader = "X-Organization-ID"
+
+// TenantError struct to describe, why organization of request is not resolved.
+type TenantError struct {
+	Status int
+	Msg    string
+}
+
+// Error method for get message of error.
+func (e *TenantError) Error() string {
+	return e.Msg
+}
+
+// TenantScoped func for specify route with records of one organization.
+// It must follow auth or access control middleware, which set principal.
+// Caller must be a member of organization; admins can select any organization.
+func TenantScoped() func(*fiber.Ctx) error {
+	return func(c *fiber.Ctx) error {
+		// Records of organizations belong only to users.
+		principal, err := utils.GetPrincipal(c)
+		if err != nil {
+			return jwtError(c, err)
+		}
+
+		// Get role of user in organization of request.
+		tenant, tenantErr := ResolveTenant(c, principal)
+		if tenantErr != nil {
+			return problem.FromStatus(tenantErr.Status, tenantErr.Msg)
+		}
+
+		// Store tenant for controllers.
+		utils.SetTenant(c, tenant)
+
+		return c.Next()
+	}
+}
+
+// ResolveTenant func for get role of user or service account in organization of
+// request, which is selected by the X-Organization-ID header, or in the default
+// organization of user.
+func ResolveTenant(c *fiber.Ctx, principal *utils.Principal) (*utils.Tenant, *TenantError) {
+	if principal.UserID == uuid.Nil {
+		// OAuth clients and client certificates are members by their service accounts.
+		return nil, &TenantError{fiber.StatusForbidden, "permission denied, token subject is not a user or service account"}
+	}
+
+	// Get organization of request from header.
+	organizationID := uuid.Nil
+	if header := c.Get(OrganizationHeader); header != "" {
+		var err error
+		if organizationID, err = uuid.Parse(header); err != nil {
+			return nil, &TenantError{fiber.StatusBadRequest, OrganizationHeader + " header is not valid"}
+		}
+	}
+
+	// Create database connection.
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		return nil, &TenantError{fiber.StatusInternalServerError, err.Error()}
+	}
+
+	// Get role of user in the selected, or the default organization.
+	tenant := &utils.Tenant{OrganizationID: organizationID, UserID: principal.UserID}
+	if organizationID == uuid.Nil {
+		membership, err := db.GetDefaultMembership(principal.UserID)
+		if err != nil {
+			return nil, &TenantError{fiber.StatusForbidden, "permission denied, user is not a member of any organization"}
+		}
+		tenant.OrganizationID, tenant.Role = membership.OrganizationID, membership.Role
+	} else if membership, err := db.GetMembership(organizationID, principal.UserID); err == nil {
+		tenant.Role = membership.Role
+	} else if _, err := db.GetOrganization(organizationID); err == nil && principal.IsAdmin() {
+		// Admins of app manage records of any organization.
+		tenant.Role = repository.OrganizationAdminRole
+	} else {
+		return nil, &TenantError{fiber.StatusForbidden, "permission denied, user is not a member of organization"}
+	}
+
+	return tenant, nil
+}
@@ -0,0 +1,117 @@
+package mtls
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Config struct to describe TLS of server and authentication of clients by certificates.
+type Config struct {
+	CertFile       string               // certificate of server (PEM), with intermediates
+	KeyFile        string               // private key of server (PEM)
+	ClientCAFile   string               // CAs of client certificates (PEM), empty to disable client certificates
+	ClientAuth     tls.ClientAuthType   // optional or required client certificates
+	ReloadInterval time.Duration        // how often files are checked for changes
+	Principals     map[string][]string  // identity of certificate => roles
+	Organizations  map[string]uuid.UUID // identity of certificate => organization of its service account
+}
+
+// ConfigFromEnv func for get TLS config from .env file:
+//   - TLS_CERT_FILE, TLS_KEY_FILE: certificate and key of server, plain HTTP if not set;
+//   - TLS_CLIENT_CA_FILE: CAs, which issue client certificates;
+//   - TLS_CLIENT_AUTH: "optional" (default) or "require" client certificates;
+//   - TLS_RELOAD_SECONDS: how often files are checked for changes (default 30);
+//   - TLS_CLIENT_PRINCIPALS: comma-separated "identity:role|role" or
+//     "identity:role|role@organization-id", only listed identities (URI SAN,
+//     DNS SAN, email SAN or common name) are authenticated. Service account of
+//     identity is a member of the organization, or has a personal organization.
+func ConfigFromEnv() (Config, error) {
+	config := Config{
+		CertFile:       os.Getenv("TLS_CERT_FILE"),
+		KeyFile:        os.Getenv("TLS_KEY_FILE"),
+		ClientCAFile:   os.Getenv("TLS_CLIENT_CA_FILE"),
+		ClientAuth:     tls.NoClientCert,
+		ReloadInterval: 30 * time.Second,
+		Principals:     map[string][]string{},
+		Organizations:  map[string]uuid.UUID{},
+	}
+
+	if config.ClientCAFile != "" {
+		switch mode := os.Getenv("TLS_CLIENT_AUTH"); mode {
+		case "", "optional":
+			config.ClientAuth = tls.VerifyClientCertIfGiven
+		case "require":
+			config.ClientAuth = tls.RequireAndVerifyClientCert
+		default:
+			return config, fmt.Errorf("TLS_CLIENT_AUTH %q is not supported", mode)
+		}
+	}
+
+	if seconds, err := strconv.Atoi(os.Getenv("TLS_RELOAD_SECONDS")); err == nil && seconds > 0 {
+		config.ReloadInterval = time.Duration(seconds) * time.Second
+	}
+
+	for _, entry := range strings.Split(os.Getenv("TLS_CLIENT_PRINCIPALS"), ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		// Identity may contain ":" (like "spiffe://..."), so roles follow the last one.
+		i := strings.LastIndex(entry, ":")
+		if i <= 0 || i == len(entry)-1 {
+			return config, fmt.Errorf("TLS_CLIENT_PRINCIPALS entry %q is not valid", entry)
+		}
+		roles := entry[i+1:]
+		if j := strings.LastIndex(roles, "@"); j >= 0 {
+			organizationID, err := uuid.Parse(roles[j+1:])
+			if err != nil || j == 0 {
+				return config, fmt.Errorf("TLS_CLIENT_PRINCIPALS entry %q is not valid", entry)
+			}
+			config.Organizations[entry[:i]], roles = organizationID, roles[:j]
+		}
+		config.Principals[entry[:i]] = strings.Split(roles, "|")
+	}
+
+	return config, nil
+}
+
+// Enabled method for checking, if server listens with TLS.
+func (c Config) Enabled() bool {
+	return c.CertFile != "" && c.KeyFile != ""
+}
+
+// Identities func for get identities of certificate, from the most to the least
+// specific: URI SANs (like SPIFFE ID), DNS SANs, email SANs and common name.
+func Identities(cert *x509.Certificate) []string {
+	identities := []string{}
+	for _, uri := range cert.URIs {
+		identities = append(identities, uri.String())
+	}
+	identities = append(identities, cert.DNSNames...)
+	identities = append(identities, cert.EmailAddresses...)
+	if cert.Subject.CommonName != "" {
+		identities = append(identities, cert.Subject.CommonName)
+	}
+
+	return identities
+}
+
+// Lookup method for get identity and roles of client certificate. It returns
+// false, if no identity of certificate is listed in principals.
+func (c Config) Lookup(cert *x509.Certificate) (string, []string, bool) {
+	for _, identity := range Identities(cert) {
+		if roles, ok := c.Principals[identity]; ok {
+			return identity, roles, true
+		}
+	}
+
+	return "", nil, false
+}
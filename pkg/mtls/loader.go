@@ -0,0 +1,48 @@
+package mtls
+
+import (
+	"crypto/tls"
+	"log"
+	"net"
+	"sync"
+)
+
+var (
+	current     Config
+	currentOnce sync.Once
+)
+
+// CurrentConfig func for get TLS config of app from .env file. Client
+// certificates are not authenticated, if config is not valid.
+func CurrentConfig() Config {
+	currentOnce.Do(func() {
+		config, err := ConfigFromEnv()
+		if err != nil {
+			log.Printf("Oops... TLS config is not valid! Reason: %v", err)
+			config = Config{}
+		}
+		current = config
+	})
+
+	return current
+}
+
+// Listen func for create listener of server. It's a TLS listener with
+// reloaded certificates, if TLS is enabled in config, or plain TCP otherwise.
+func Listen(addr string) (net.Listener, error) {
+	config, err := ConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	if !config.Enabled() {
+		return net.Listen("tcp", addr)
+	}
+
+	reloader, err := NewReloader(config)
+	if err != nil {
+		return nil, err
+	}
+	go reloader.Watch()
+
+	return tls.Listen("tcp", addr, reloader.TLSConfig())
+}
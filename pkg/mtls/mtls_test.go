@@ -0,0 +1,204 @@
+package mtls
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testCert struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	der  []byte
+}
+
+func newCert(t *testing.T, template *x509.Certificate, parent *testCert) *testCert {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template.SerialNumber = big.NewInt(time.Now().UnixNano())
+	template.NotBefore = time.Now().Add(-time.Minute)
+	template.NotAfter = time.Now().Add(time.Hour)
+
+	parentCert, parentKey := template, key
+	if parent != nil {
+		parentCert, parentKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parentCert, &key.PublicKey, parentKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return &testCert{cert: cert, key: key, der: der}
+}
+
+func newCA(t *testing.T, name string) *testCert {
+	return newCert(t, &x509.Certificate{
+		Subject:               pkix.Name{CommonName: name},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}, nil)
+}
+
+func (c *testCert) write(t *testing.T, certFile, keyFile string) {
+	require.NoError(t, ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.der}), 0o600))
+	if keyFile != "" {
+		der, err := x509.MarshalECPrivateKey(c.key)
+		require.NoError(t, err)
+		require.NoError(t, ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), 0o600))
+	}
+}
+
+func setenv(t *testing.T, key, value string) {
+	old, ok := os.LookupEnv(key)
+	os.Setenv(key, value)
+	t.Cleanup(func() {
+		if ok {
+			os.Setenv(key, old)
+		} else {
+			os.Unsetenv(key)
+		}
+	})
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	setenv(t, "TLS_CLIENT_CA_FILE", "ca.pem")
+	setenv(t, "TLS_CLIENT_AUTH", "require")
+	setenv(t, "TLS_CLIENT_PRINCIPALS", "spiffe://internal/billing:admin|user, reports.internal:user@6c1b2bd4-3f3e-4b8e-9d2a-2f0c6a8e5d01")
+
+	config, err := ConfigFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, tls.RequireAndVerifyClientCert, config.ClientAuth)
+	assert.Equal(t, []string{"admin", "user"}, config.Principals["spiffe://internal/billing"])
+	assert.Equal(t, []string{"user"}, config.Principals["reports.internal"])
+	assert.Equal(t, "6c1b2bd4-3f3e-4b8e-9d2a-2f0c6a8e5d01", config.Organizations["reports.internal"].String())
+	assert.NotContains(t, config.Organizations, "spiffe://internal/billing")
+
+	// URI SAN is preferred to common name.
+	uri, _ := url.Parse("spiffe://internal/billing")
+	identity, roles, ok := config.Lookup(&x509.Certificate{URIs: []*url.URL{uri}, Subject: pkix.Name{CommonName: "reports.internal"}})
+	assert.True(t, ok)
+	assert.Equal(t, "spiffe://internal/billing", identity)
+	assert.Equal(t, []string{"admin", "user"}, roles)
+
+	_, _, ok = config.Lookup(&x509.Certificate{DNSNames: []string{"unknown.internal"}})
+	assert.False(t, ok)
+
+	setenv(t, "TLS_CLIENT_PRINCIPALS", "no-roles")
+	_, err = ConfigFromEnv()
+	assert.Error(t, err)
+
+	setenv(t, "TLS_CLIENT_PRINCIPALS", "reports.internal:user@not-uuid")
+	_, err = ConfigFromEnv()
+	assert.Error(t, err)
+}
+
+func TestReloaderClientCertificates(t *testing.T) {
+	dir := t.TempDir()
+	config := Config{
+		CertFile:     filepath.Join(dir, "server.pem"),
+		KeyFile:      filepath.Join(dir, "server.key"),
+		ClientCAFile: filepath.Join(dir, "ca.pem"),
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	}
+
+	ca := newCA(t, "Test CA")
+	ca.write(t, config.ClientCAFile, "")
+	server := newCert(t, &x509.Certificate{Subject: pkix.Name{CommonName: "server-1"}, DNSNames: []string{"localhost"}, ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}}, ca)
+	server.write(t, config.CertFile, config.KeyFile)
+	client := newCert(t, &x509.Certificate{Subject: pkix.Name{CommonName: "billing.internal"}, ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}}, ca)
+
+	reloader, err := NewReloader(config)
+	require.NoError(t, err)
+
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", reloader.TLSConfig())
+	require.NoError(t, err)
+	defer ln.Close()
+
+	peers := make(chan []string, 1)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			tlsConn := conn.(*tls.Conn)
+			names := []string{}
+			if tlsConn.Handshake() == nil {
+				for _, cert := range tlsConn.ConnectionState().PeerCertificates {
+					names = append(names, cert.Subject.CommonName)
+				}
+			}
+			peers <- names
+			conn.Close()
+		}
+	}()
+
+	dial := func(clientCert *testCert) (*tls.Conn, error) {
+		clientConfig := &tls.Config{InsecureSkipVerify: true} // server certificate is checked by test
+		if clientCert != nil {
+			clientConfig.Certificates = []tls.Certificate{{Certificate: [][]byte{clientCert.der}, PrivateKey: clientCert.key}}
+		}
+		conn, err := tls.DialWithDialer(&net.Dialer{Timeout: time.Second}, "tcp", ln.Addr().String(), clientConfig)
+		if err != nil {
+			return nil, err
+		}
+
+		// Handshake of TLS 1.3 fails on server after client has finished it.
+		_, err = conn.Read(make([]byte, 1))
+		if err != nil && err.Error() != "EOF" {
+			return nil, err
+		}
+
+		return conn, nil
+	}
+
+	// Client certificate of the trusted CA is verified.
+	conn, err := dial(client)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"billing.internal"}, <-peers)
+	assert.Equal(t, "server-1", conn.ConnectionState().PeerCertificates[0].Subject.CommonName)
+	conn.Close()
+
+	// Required client certificate is missing.
+	_, err = dial(nil)
+	assert.Error(t, err)
+	<-peers
+
+	// Nothing changed, nothing is reloaded.
+	reloaded, err := reloader.Reload()
+	require.NoError(t, err)
+	assert.False(t, reloaded)
+
+	// A new certificate of server is used without restart.
+	server = newCert(t, &x509.Certificate{Subject: pkix.Name{CommonName: "server-2"}, ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}}, ca)
+	server.write(t, config.CertFile, config.KeyFile)
+	later := time.Now().Add(time.Second)
+	require.NoError(t, os.Chtimes(config.CertFile, later, later))
+
+	reloaded, err = reloader.Reload()
+	require.NoError(t, err)
+	assert.True(t, reloaded)
+
+	conn, err = dial(client)
+	require.NoError(t, err)
+	<-peers
+	assert.Equal(t, "server-2", conn.ConnectionState().PeerCertificates[0].Subject.CommonName)
+	conn.Close()
+}
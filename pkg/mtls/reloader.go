@@ -0,0 +1,126 @@
+package mtls
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io/ioutil"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// Reloader struct to describe certificates of server, which are reloaded,
+// when their files are changed. New connections use the last loaded files.
+type Reloader struct {
+	config Config
+
+	mu        sync.RWMutex
+	cert      *tls.Certificate
+	clientCAs *x509.CertPool
+	modTimes  map[string]time.Time
+}
+
+// NewReloader func for load certificates of config.
+func NewReloader(config Config) (*Reloader, error) {
+	r := &Reloader{config: config}
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// TLSConfig method for get TLS config of server, which uses the current certificates.
+func (r *Reloader) TLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			r.mu.RLock()
+			defer r.mu.RUnlock()
+
+			return &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{*r.cert},
+				ClientAuth:   r.config.ClientAuth,
+				ClientCAs:    r.clientCAs,
+			}, nil
+		},
+	}
+}
+
+// Reload method for load files again, if any of them was changed. Certificates
+// are kept, if files can't be loaded (like while they are being replaced).
+func (r *Reloader) Reload() (bool, error) {
+	r.mu.RLock()
+	changed := false
+	for file, modTime := range r.modTimes {
+		info, err := os.Stat(file)
+		if err != nil {
+			r.mu.RUnlock()
+			return false, err
+		}
+		if !info.ModTime().Equal(modTime) {
+			changed = true
+		}
+	}
+	r.mu.RUnlock()
+
+	if !changed {
+		return false, nil
+	}
+
+	return true, r.load()
+}
+
+// Watch method for reload files every interval of config, it never returns.
+func (r *Reloader) Watch() {
+	for range time.Tick(r.config.ReloadInterval) {
+		reloaded, err := r.Reload()
+		if err != nil {
+			log.Printf("Oops... TLS certificates are not reloaded! Reason: %v", err)
+		} else if reloaded {
+			log.Printf("TLS certificates are reloaded")
+		}
+	}
+}
+
+func (r *Reloader) load() error {
+	// Remember times of files before reading, so changes during reading are not lost.
+	files := []string{r.config.CertFile, r.config.KeyFile}
+	if r.config.ClientCAFile != "" {
+		files = append(files, r.config.ClientCAFile)
+	}
+	modTimes := map[string]time.Time{}
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return err
+		}
+		modTimes[file] = info.ModTime()
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.config.CertFile, r.config.KeyFile)
+	if err != nil {
+		return err
+	}
+
+	var clientCAs *x509.CertPool
+	if r.config.ClientCAFile != "" {
+		data, err := ioutil.ReadFile(r.config.ClientCAFile)
+		if err != nil {
+			return err
+		}
+		clientCAs = x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(data) {
+			return errors.New("no CA certificates are found in " + r.config.ClientCAFile)
+		}
+	}
+
+	r.mu.Lock()
+	r.cert, r.clientCAs, r.modTimes = &cert, clientCAs, modTimes
+	r.mu.Unlock()
+
+	return nil
+}
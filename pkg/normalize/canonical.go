@@ -0,0 +1,247 @@
+package normalize
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// Canonicalize func for serialize a decoded JSON value by RFC 8785 (JCS).
+// See: https://www.rfc-editor.org/rfc/rfc8785
+func Canonicalize(v interface{}) ([]byte, error) {
+	buf := &bytes.Buffer{}
+
+	if err := writeCanonical(buf, v); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func writeCanonical(buf *bytes.Buffer, v interface{}) error {
+	switch value := v.(type) {
+	case nil:
+		buf.WriteString("null")
+	case bool:
+		buf.WriteString(strconv.FormatBool(value))
+	case string:
+		writeString(buf, value)
+	case json.Number:
+		number, err := formatNumber(value, numberModeIEEE754)
+		if err != nil {
+			return err
+		}
+		buf.WriteString(number)
+	case float64:
+		number, err := formatFloat(value)
+		if err != nil {
+			return err
+		}
+		buf.WriteString(number)
+	case canonicalNumber:
+		// Number was already canonicalized by the engine.
+		buf.WriteString(string(value))
+	case []interface{}:
+		buf.WriteByte('[')
+		for i, item := range value {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeCanonical(buf, item); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	case map[string]interface{}:
+		// Members are sorted by their names as UTF-16 code units.
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			return lessUTF16(keys[i], keys[j])
+		})
+
+		buf.WriteByte('{')
+		for i, key := range keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			writeString(buf, key)
+			buf.WriteByte(':')
+			if err := writeCanonical(buf, value[key]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	default:
+		return fmt.Errorf("unsupported JSON value of type %T", v)
+	}
+
+	return nil
+}
+
+// canonicalNumber is a number already serialized in its canonical form.
+type canonicalNumber string
+
+func writeString(buf *bytes.Buffer, s string) {
+	buf.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			buf.WriteString(`\"`)
+		case '\\':
+			buf.WriteString(`\\`)
+		case '\b':
+			buf.WriteString(`\b`)
+		case '\f':
+			buf.WriteString(`\f`)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case '\t':
+			buf.WriteString(`\t`)
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(buf, `\u%04x`, r)
+			} else {
+				buf.WriteRune(r)
+			}
+		}
+	}
+	buf.WriteByte('"')
+}
+
+func lessUTF16(a, b string) bool {
+	ua, ub := utf16.Encode([]rune(a)), utf16.Encode([]rune(b))
+	for i := 0; i < len(ua) && i < len(ub); i++ {
+		if ua[i] != ub[i] {
+			return ua[i] < ub[i]
+		}
+	}
+	return len(ua) < len(ub)
+}
+
+const (
+	numberModeIEEE754 = "ieee754" // RFC 8785 behaviour, numbers are IEEE 754 doubles
+	numberModeDecimal = "decimal" // exact decimal text, safe for big integers
+)
+
+// formatNumber func for serialize a JSON number by the given mode.
+func formatNumber(n json.Number, mode string) (string, error) {
+	switch mode {
+	case "", numberModeIEEE754:
+		f, err := strconv.ParseFloat(string(n), 64)
+		if err != nil {
+			return "", fmt.Errorf("number %s is out of IEEE 754 range", n)
+		}
+		return formatFloat(f)
+	case numberModeDecimal:
+		return formatDecimal(string(n))
+	default:
+		return "", fmt.Errorf("unknown number mode %q", mode)
+	}
+}
+
+// formatFloat func for serialize a double like ECMAScript Number.prototype.toString.
+func formatFloat(f float64) (string, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return "", errors.New("NaN and Infinity are not valid JSON numbers")
+	}
+	if f == 0 {
+		// Also covers negative zero.
+		return "0", nil
+	}
+
+	// Shortest round-trip digits, like "d.ddddde±XX".
+	e := strconv.FormatFloat(math.Abs(f), 'e', -1, 64)
+	mantissa, exponent := e[:strings.IndexByte(e, 'e')], e[strings.IndexByte(e, 'e')+1:]
+	exp, _ := strconv.Atoi(exponent)
+
+	return formatDigits(f < 0, strings.Replace(mantissa, ".", "", 1), exp+1), nil
+}
+
+// formatDecimal func for serialize a number exactly from its decimal text.
+func formatDecimal(text string) (string, error) {
+	f, _, err := big.ParseFloat(text, 10, 0, big.ToNearestEven)
+	if err != nil || f.IsInf() {
+		return "", fmt.Errorf("number %s is not a valid decimal", text)
+	}
+	if f.Sign() == 0 {
+		return "0", nil
+	}
+
+	negative := strings.HasPrefix(text, "-")
+	text = strings.TrimPrefix(text, "-")
+
+	// Split the text into significant digits and the position of the decimal point.
+	exp := 0
+	if i := strings.IndexAny(text, "eE"); i >= 0 {
+		exp, _ = strconv.Atoi(text[i+1:])
+		text = text[:i]
+	}
+	point := len(text)
+	if i := strings.IndexByte(text, '.'); i >= 0 {
+		point = i
+		text = text[:i] + text[i+1:]
+	}
+	trimmed := strings.TrimLeft(text, "0")
+	point -= len(text) - len(trimmed)
+	digits := strings.TrimRight(trimmed, "0")
+
+	// Integers are never written in the exponent form, however big they are.
+	if n := point + exp; len(digits) <= n && n > 21 {
+		sign := ""
+		if negative {
+			sign = "-"
+		}
+		return sign + digits + strings.Repeat("0", n-len(digits)), nil
+	}
+
+	return formatDigits(negative, digits, point+exp), nil
+}
+
+// formatDigits func for place significant digits by the ECMAScript rules,
+// where the value equals 0.digits × 10^n.
+func formatDigits(negative bool, digits string, n int) string {
+	var b strings.Builder
+	if negative {
+		b.WriteByte('-')
+	}
+
+	k := len(digits)
+	switch {
+	case k <= n && n <= 21:
+		b.WriteString(digits)
+		b.WriteString(strings.Repeat("0", n-k))
+	case 0 < n && n <= 21:
+		b.WriteString(digits[:n])
+		b.WriteByte('.')
+		b.WriteString(digits[n:])
+	case -6 < n && n <= 0:
+		b.WriteString("0.")
+		b.WriteString(strings.Repeat("0", -n))
+		b.WriteString(digits)
+	default:
+		b.WriteByte(digits[0])
+		if k > 1 {
+			b.WriteByte('.')
+			b.WriteString(digits[1:])
+		}
+		b.WriteByte('e')
+		if n-1 >= 0 {
+			b.WriteByte('+')
+		}
+		b.WriteString(strconv.Itoa(n - 1))
+	}
+
+	return b.String()
+}
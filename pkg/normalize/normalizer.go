@@ -0,0 +1,255 @@
+package normalize
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"math/big"
+	"regexp"
+	"sort"
+)
+
+// Options struct to describe how a JSON document must be normalized.
+type Options struct {
+	Ignore       []string      `json:"ignore,omitempty"`        // JSON Pointers or JSONPaths of values to remove
+	Unordered    []string      `json:"unordered,omitempty"`     // JSON Pointers or JSONPaths of order-insensitive arrays
+	UnorderedAll bool          `json:"unordered_all,omitempty"` // treat every array as order-insensitive
+	Numbers      NumberOptions `json:"numbers"`
+	Redact       []Redaction   `json:"redact,omitempty"`
+}
+
+// NumberOptions struct to describe number canonicalization.
+type NumberOptions struct {
+	Mode      string `json:"mode,omitempty" validate:"omitempty,oneof=ieee754 decimal"` // "ieee754" (RFC 8785, default) or "decimal"
+	Precision *int   `json:"precision,omitempty" validate:"omitempty,min=0,max=20"`     // round to the given count of decimal places
+}
+
+// Redaction struct to describe a regular expression replaced in string values.
+type Redaction struct {
+	Pattern     string   `json:"pattern" validate:"required"`
+	Replacement string   `json:"replacement"`
+	Paths       []string `json:"paths,omitempty"` // limit redaction to the selected values
+}
+
+// Normalizer struct to describe a compiled set of normalization options.
+type Normalizer struct {
+	options   Options
+	ignore    []*selector
+	unordered []*selector
+	redact    []compiledRedaction
+}
+
+type compiledRedaction struct {
+	pattern     *regexp.Regexp
+	replacement string
+	paths       []*selector
+}
+
+// removed is a marker for values dropped by the ignore list.
+type removed struct{}
+
+// New func for compile normalization options into a Normalizer.
+func New(options Options) (*Normalizer, error) {
+	n := &Normalizer{options: options}
+
+	if _, err := formatNumber("0", options.Numbers.Mode); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if n.ignore, err = compileSelectors(options.Ignore); err != nil {
+		return nil, err
+	}
+	if n.unordered, err = compileSelectors(options.Unordered); err != nil {
+		return nil, err
+	}
+
+	for _, r := range options.Redact {
+		pattern, err := regexp.Compile(r.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("redaction pattern %q is not valid, %w", r.Pattern, err)
+		}
+		paths, err := compileSelectors(r.Paths)
+		if err != nil {
+			return nil, err
+		}
+		n.redact = append(n.redact, compiledRedaction{pattern: pattern, replacement: r.Replacement, paths: paths})
+	}
+
+	return n, nil
+}
+
+func compileSelectors(sources []string) ([]*selector, error) {
+	selectors := make([]*selector, 0, len(sources))
+	for _, source := range sources {
+		s, err := compileSelector(source)
+		if err != nil {
+			return nil, err
+		}
+		selectors = append(selectors, s)
+	}
+
+	return selectors, nil
+}
+
+// Normalize method for normalize a JSON document and return its canonical form.
+func (n *Normalizer) Normalize(document []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(document))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, fmt.Errorf("document is not valid JSON, %w", err)
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, errors.New("document must contain a single JSON value")
+	}
+
+	value, err := n.walk(value, []step{})
+	if err != nil {
+		return nil, err
+	}
+
+	return Canonicalize(value)
+}
+
+// walk method for apply the options to a value, children first.
+func (n *Normalizer) walk(value interface{}, path []step) (interface{}, error) {
+	if len(path) > 0 && matchAny(n.ignore, path) {
+		return removed{}, nil
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			child, err := n.walk(item, append(path, step{key: key}))
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := child.(removed); ok {
+				delete(v, key)
+				continue
+			}
+			v[key] = child
+		}
+	case []interface{}:
+		items := make([]interface{}, 0, len(v))
+		for i, item := range v {
+			child, err := n.walk(item, append(path, step{index: i, isIndex: true}))
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := child.(removed); !ok {
+				items = append(items, child)
+			}
+		}
+		if n.options.UnorderedAll || matchAny(n.unordered, path) {
+			if err := sortItems(items); err != nil {
+				return nil, err
+			}
+		}
+		value = items
+	case string:
+		value = n.redactString(v, path)
+	case json.Number:
+		number, err := n.canonicalizeNumber(v)
+		if err != nil {
+			return nil, err
+		}
+		value = number
+	}
+
+	return value, nil
+}
+
+func (n *Normalizer) redactString(s string, path []step) string {
+	for _, r := range n.redact {
+		if len(r.paths) > 0 && !matchWithinAny(r.paths, path) {
+			continue
+		}
+		s = r.pattern.ReplaceAllString(s, r.replacement)
+	}
+
+	return s
+}
+
+func matchWithinAny(selectors []*selector, path []step) bool {
+	for _, s := range selectors {
+		if s.matchWithin(path) {
+			return true
+		}
+	}
+	return false
+}
+
+func (n *Normalizer) canonicalizeNumber(number json.Number) (canonicalNumber, error) {
+	if precision := n.options.Numbers.Precision; precision != nil {
+		f, _, err := big.ParseFloat(string(number), 10, 256, big.ToNearestEven)
+		if err != nil {
+			return "", fmt.Errorf("number %s is not valid, %w", number, err)
+		}
+		number = json.Number(f.Text('f', *precision))
+	}
+
+	formatted, err := formatNumber(number, n.options.Numbers.Mode)
+	if err != nil {
+		return "", err
+	}
+
+	return canonicalNumber(formatted), nil
+}
+
+// sortItems func for order array items by their canonical serialization.
+func sortItems(items []interface{}) error {
+	keys := make([]string, len(items))
+	for i, item := range items {
+		key, err := Canonicalize(item)
+		if err != nil {
+			return err
+		}
+		keys[i] = string(key)
+	}
+
+	sort.Sort(byKey{items: items, keys: keys})
+
+	return nil
+}
+
+type byKey struct {
+	items []interface{}
+	keys  []string
+}
+
+func (b byKey) Len() int           { return len(b.items) }
+func (b byKey) Less(i, j int) bool { return b.keys[i] < b.keys[j] }
+func (b byKey) Swap(i, j int) {
+	b.items[i], b.items[j] = b.items[j], b.items[i]
+	b.keys[i], b.keys[j] = b.keys[j], b.keys[i]
+}
+
+// Digest func for calculate a hex-encoded digest of the data by the given algorithm.
+// Supported algorithms: sha256 (default), sha384 and sha512.
+func Digest(data []byte, algorithm string) (string, error) {
+	var h hash.Hash
+
+	switch algorithm {
+	case "", "sha256":
+		h = sha256.New()
+	case "sha384":
+		h = sha512.New384()
+	case "sha512":
+		h = sha512.New()
+	default:
+		return "", fmt.Errorf("unsupported digest algorithm %q", algorithm)
+	}
+
+	h.Write(data)
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
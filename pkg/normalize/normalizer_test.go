@@ -0,0 +1,115 @@
+package normalize
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalize(t *testing.T) {
+	precision := 2
+
+	// Define a structure for specifying input and output data of a single test case.
+	tests := []struct {
+		description   string
+		options       Options
+		document      string
+		expected      string
+		expectedError bool
+	}{
+		{
+			description: "sort members and drop whitespace",
+			document:    `{ "b": [1, 2], "a": {"y": true, "x": null} }`,
+			expected:    `{"a":{"x":null,"y":true},"b":[1,2]}`,
+		},
+		{
+			description: "serialize numbers like RFC 8785",
+			document:    `[1.0, -0, 1e21, 1e-7, 0.000001, 123456789012345680000, 4.50, 2e-3]`,
+			expected:    `[1,0,1e+21,1e-7,0.000001,123456789012345680000,4.5,0.002]`,
+		},
+		{
+			description: "escape strings like RFC 8785",
+			document:    `{"s": "€$\u000f\nA'B\"\\\\\"/", "\u0080": 1, "😀": 2, "\ufb33": 3}`,
+			expected:    `{"s":"€$\u000f\nA'B\"\\\\\"/","":1,"😀":2,"דּ":3}`,
+		},
+		{
+			description: "keep big integers in decimal mode",
+			options:     Options{Numbers: NumberOptions{Mode: "decimal"}},
+			document:    `[12345678901234567890123, 1.500, 0.0001e2, -0.000]`,
+			expected:    `[12345678901234567890123,1.5,0.01,0]`,
+		},
+		{
+			description: "round numbers to precision",
+			options:     Options{Numbers: NumberOptions{Precision: &precision}},
+			document:    `{"price": 10.004999, "total": 3.14159}`,
+			expected:    `{"price":10,"total":3.14}`,
+		},
+		{
+			description: "ignore by JSON Pointer and JSONPath",
+			options:     Options{Ignore: []string{"/meta/request_id", "$..timestamp", "$.items[1]"}},
+			document:    `{"meta": {"request_id": "x", "v": 1}, "items": [{"timestamp": 1, "id": 1}, {"id": 2}, {"id": 3}]}`,
+			expected:    `{"items":[{"id":1},{"id":3}],"meta":{"v":1}}`,
+		},
+		{
+			description: "sort selected arrays only",
+			options:     Options{Unordered: []string{"$.tags"}},
+			document:    `{"tags": ["b", "a"], "list": ["b", "a"]}`,
+			expected:    `{"list":["b","a"],"tags":["a","b"]}`,
+		},
+		{
+			description: "sort every array after normalizing nested values",
+			options:     Options{UnorderedAll: true},
+			document:    `[[2, 1], [1, 1], {"a": [3, 2]}]`,
+			expected:    `[[1,1],[1,2],{"a":[2,3]}]`,
+		},
+		{
+			description: "redact text by regular expression",
+			options: Options{Redact: []Redaction{
+				{Pattern: `[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}`, Replacement: "<uuid>"},
+				{Pattern: `\d{4}-\d{2}-\d{2}`, Replacement: "<date>", Paths: []string{"/log"}},
+			}},
+			document: `{"log": ["at 2021-01-02 id 0b7f5c3e-5f7b-4c1e-9d6a-2d0f7c1b9e11"], "date": "2021-01-02"}`,
+			expected: `{"date":"2021-01-02","log":["at <date> id <uuid>"]}`,
+		},
+		{
+			description:   "reject invalid JSON",
+			document:      `{"a": }`,
+			expectedError: true,
+		},
+		{
+			description:   "reject trailing values",
+			document:      `{} {}`,
+			expectedError: true,
+		},
+		{
+			description:   "reject unsupported selectors",
+			options:       Options{Ignore: []string{"$.items[?(@.id)]"}},
+			document:      `{}`,
+			expectedError: true,
+		},
+	}
+
+	// Iterate through test single test cases
+	for _, test := range tests {
+		normalizer, err := New(test.options)
+		if err == nil {
+			var normalized []byte
+			normalized, err = normalizer.Normalize([]byte(test.document))
+			if err == nil {
+				assert.Equalf(t, test.expected, string(normalized), test.description)
+			}
+		}
+
+		// Verify, that no error occurred, that is not expected
+		assert.Equalf(t, test.expectedError, err != nil, test.description)
+	}
+}
+
+func TestDigest(t *testing.T) {
+	digest, err := Digest([]byte(`{}`), "")
+	assert.NoError(t, err)
+	assert.Equal(t, "44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a", digest)
+
+	_, err = Digest([]byte(`{}`), "md5")
+	assert.Error(t, err)
+}
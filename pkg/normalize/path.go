@@ -0,0 +1,180 @@
+package normalize
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// step struct to describe one step from the document root to a value.
+type step struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+// segment struct to describe one compiled part of a selector.
+type segment struct {
+	name     string
+	index    int
+	isIndex  bool
+	wildcard bool
+	descend  bool // recursive descent (JSONPath "..")
+}
+
+func (s segment) matchStep(st step) bool {
+	switch {
+	case s.wildcard:
+		return true
+	case s.isIndex:
+		return st.isIndex && st.index == s.index
+	case st.isIndex:
+		// JSON Pointer tokens address array items by their decimal index.
+		return s.name == strconv.Itoa(st.index)
+	default:
+		return s.name == st.key
+	}
+}
+
+// selector struct to describe a compiled JSON Pointer or JSONPath expression.
+type selector struct {
+	source   string
+	segments []segment
+}
+
+// compileSelector func for compile a JSON Pointer (RFC 6901) or a JSONPath
+// expression. Supported JSONPath subset: $, .name, ['name'], [n], [*], .* and "..".
+func compileSelector(source string) (*selector, error) {
+	var (
+		segments []segment
+		err      error
+	)
+
+	switch {
+	case strings.HasPrefix(source, "/"):
+		segments = compilePointer(source)
+	case strings.HasPrefix(source, "$"):
+		segments, err = compileJSONPath(source)
+	default:
+		err = fmt.Errorf("selector %q must be a JSON Pointer (\"/...\") or a JSONPath (\"$...\")", source)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("selector %q must not select the document root", source)
+	}
+
+	return &selector{source: source, segments: segments}, nil
+}
+
+func compilePointer(pointer string) []segment {
+	tokens := strings.Split(pointer[1:], "/")
+	segments := make([]segment, 0, len(tokens))
+	for _, token := range tokens {
+		token = strings.ReplaceAll(token, "~1", "/")
+		token = strings.ReplaceAll(token, "~0", "~")
+		segments = append(segments, segment{name: token})
+	}
+
+	return segments
+}
+
+func compileJSONPath(path string) ([]segment, error) {
+	segments := []segment{}
+	rest := path[1:]
+
+	for rest != "" {
+		descend := false
+		switch {
+		case strings.HasPrefix(rest, ".."):
+			descend = true
+			rest = rest[2:]
+			if strings.HasPrefix(rest, "[") {
+				break
+			}
+			fallthrough
+		case strings.HasPrefix(rest, "."):
+			rest = strings.TrimPrefix(rest, ".")
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			name := rest[:end]
+			if name == "" {
+				return nil, fmt.Errorf("JSONPath %q has an empty member name", path)
+			}
+			segments = append(segments, segment{name: name, wildcard: name == "*", descend: descend})
+			rest = rest[end:]
+			continue
+		}
+
+		if !strings.HasPrefix(rest, "[") {
+			return nil, fmt.Errorf("JSONPath %q is not supported near %q", path, rest)
+		}
+		end := strings.IndexByte(rest, ']')
+		if end < 0 {
+			return nil, fmt.Errorf("JSONPath %q has an unclosed bracket", path)
+		}
+		inner := strings.TrimSpace(rest[1:end])
+		rest = rest[end+1:]
+
+		switch {
+		case inner == "*":
+			segments = append(segments, segment{wildcard: true, descend: descend})
+		case len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0]:
+			segments = append(segments, segment{name: inner[1 : len(inner)-1], descend: descend})
+		default:
+			index, err := strconv.Atoi(inner)
+			if err != nil || index < 0 {
+				return nil, fmt.Errorf("JSONPath %q has an unsupported subscript [%s]", path, inner)
+			}
+			segments = append(segments, segment{index: index, isIndex: true, descend: descend})
+		}
+	}
+
+	return segments, nil
+}
+
+// match method for checking, if the selector selects exactly the given path.
+func (s *selector) match(path []step) bool {
+	return matchSegments(s.segments, path)
+}
+
+// matchWithin method for checking, if the given path is inside a selected value.
+func (s *selector) matchWithin(path []step) bool {
+	for i := len(path); i > 0; i-- {
+		if s.match(path[:i]) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchSegments(segments []segment, path []step) bool {
+	if len(segments) == 0 {
+		return len(path) == 0
+	}
+
+	head := segments[0]
+	if head.descend {
+		for i := range path {
+			if head.matchStep(path[i]) && matchSegments(segments[1:], path[i+1:]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return len(path) > 0 && head.matchStep(path[0]) && matchSegments(segments[1:], path[1:])
+}
+
+func matchAny(selectors []*selector, path []step) bool {
+	for _, s := range selectors {
+		if s.match(path) {
+			return true
+		}
+	}
+	return false
+}
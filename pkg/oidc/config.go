@@ -0,0 +1,55 @@
+package oidc
+
+import (
+	"os"
+	"strings"
+)
+
+// Config struct to describe client of OpenID Connect provider.
+type Config struct {
+	Issuer       string            // URL of provider, used for discovery
+	ClientID     string            // client ID registered at provider
+	ClientSecret string            // empty for public clients
+	RedirectURL  string            // URL of the callback route of this API
+	Scopes       []string          // "openid" is always requested
+	RolesClaim   string            // claim of ID token with roles or groups of user
+	RoleMapping  map[string]string // role at provider => local role
+}
+
+// ConfigFromEnv func for get provider config from .env file:
+//   - OIDC_ISSUER, OIDC_CLIENT_ID, OIDC_CLIENT_SECRET, OIDC_REDIRECT_URL;
+//   - OIDC_SCOPES: space-separated scopes (default "openid profile email");
+//   - OIDC_ROLES_CLAIM: claim with roles (default "roles");
+//   - OIDC_ROLE_MAPPING: comma-separated "provider-role:local-role".
+func ConfigFromEnv() Config {
+	config := Config{
+		Issuer:       strings.TrimSuffix(os.Getenv("OIDC_ISSUER"), "/"),
+		ClientID:     os.Getenv("OIDC_CLIENT_ID"),
+		ClientSecret: os.Getenv("OIDC_CLIENT_SECRET"),
+		RedirectURL:  os.Getenv("OIDC_REDIRECT_URL"),
+		Scopes:       strings.Fields(os.Getenv("OIDC_SCOPES")),
+		RolesClaim:   os.Getenv("OIDC_ROLES_CLAIM"),
+		RoleMapping:  map[string]string{},
+	}
+
+	if len(config.Scopes) == 0 {
+		config.Scopes = []string{"openid", "profile", "email"}
+	}
+	if config.RolesClaim == "" {
+		config.RolesClaim = "roles"
+	}
+
+	for _, entry := range strings.Split(os.Getenv("OIDC_ROLE_MAPPING"), ",") {
+		parts := strings.SplitN(strings.TrimSpace(entry), ":", 2)
+		if len(parts) == 2 && parts[0] != "" && parts[1] != "" {
+			config.RoleMapping[parts[0]] = parts[1]
+		}
+	}
+
+	return config
+}
+
+// Enabled method for checking, if provider is configured.
+func (c Config) Enabled() bool {
+	return c.Issuer != "" && c.ClientID != "" && c.RedirectURL != ""
+}
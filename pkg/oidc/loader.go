@@ -0,0 +1,35 @@
+package oidc
+
+import (
+	"errors"
+	"sync"
+)
+
+var (
+	current   *Provider
+	currentMu sync.Mutex
+)
+
+// CurrentProvider func for get provider configured by .env file. Discovery is
+// done on first call and retried on next calls, until it succeeds.
+func CurrentProvider() (*Provider, error) {
+	currentMu.Lock()
+	defer currentMu.Unlock()
+
+	if current != nil {
+		return current, nil
+	}
+
+	config := ConfigFromEnv()
+	if !config.Enabled() {
+		return nil, errors.New("OpenID Connect login is not configured")
+	}
+
+	provider, err := NewProvider(config)
+	if err != nil {
+		return nil, err
+	}
+	current = provider
+
+	return current, nil
+}
@@ -0,0 +1,333 @@
+package oidc
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+)
+
+// Metadata struct to describe discovery document of provider.
+// See: https://openid.net/specs/openid-connect-discovery-1_0.html
+type Metadata struct {
+	Issuer                string   `json:"issuer"`
+	AuthorizationEndpoint string   `json:"authorization_endpoint"`
+	TokenEndpoint         string   `json:"token_endpoint"`
+	JWKSURI               string   `json:"jwks_uri"`
+	SigningAlgorithms     []string `json:"id_token_signing_alg_values_supported"`
+}
+
+// TokenResponse struct to describe response of token endpoint.
+type TokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	IDToken          string `json:"id_token"`
+	TokenType        string `json:"token_type"`
+	ExpiresIn        int64  `json:"expires_in"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// Identity struct to describe user from a verified ID token.
+type Identity struct {
+	Issuer            string
+	Subject           string
+	PreferredUsername string
+	Email             string
+	GivenName         string
+	FamilyName        string
+	Roles             []string // local roles, mapped from roles claim
+	MultiFactor       bool     // user was authenticated by many factors ("mfa" in "amr" claim, RFC 8176)
+}
+
+// Provider struct to describe OpenID Connect provider.
+type Provider struct {
+	Config   Config
+	Metadata Metadata
+
+	client *http.Client
+
+	mu          sync.RWMutex
+	keys        map[string]interface{}
+	keysFetched time.Time
+}
+
+// NewProvider func for create a provider by its discovery document.
+func NewProvider(config Config) (*Provider, error) {
+	p := &Provider{
+		Config: config,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+
+	if err := p.getJSON(config.Issuer+"/.well-known/openid-configuration", &p.Metadata); err != nil {
+		return nil, fmt.Errorf("discovery of provider failed, %w", err)
+	}
+
+	// Issuer of discovery document must be exactly the same as configured.
+	if p.Metadata.Issuer != config.Issuer {
+		return nil, fmt.Errorf("provider issuer %q does not match %q", p.Metadata.Issuer, config.Issuer)
+	}
+	if p.Metadata.AuthorizationEndpoint == "" || p.Metadata.TokenEndpoint == "" || p.Metadata.JWKSURI == "" {
+		return nil, errors.New("discovery document of provider is not complete")
+	}
+
+	return p, nil
+}
+
+// AuthCodeURL method for get URL of provider to redirect user for login,
+// with PKCE S256 code challenge of the given verifier.
+func (p *Provider) AuthCodeURL(state, nonce, verifier string) string {
+	scopes := p.Config.Scopes
+	if !contains(scopes, "openid") {
+		scopes = append([]string{"openid"}, scopes...)
+	}
+
+	query := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {p.Config.ClientID},
+		"redirect_uri":          {p.Config.RedirectURL},
+		"scope":                 {strings.Join(scopes, " ")},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {CodeChallenge(verifier)},
+		"code_challenge_method": {"S256"},
+	}
+
+	separator := "?"
+	if strings.Contains(p.Metadata.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+
+	return p.Metadata.AuthorizationEndpoint + separator + query.Encode()
+}
+
+// Exchange method for exchange authorization code to tokens.
+func (p *Provider) Exchange(code, verifier string) (*TokenResponse, error) {
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.Config.RedirectURL},
+		"code_verifier": {verifier},
+	}
+
+	// Public clients send only client ID, confidential ones use HTTP Basic auth.
+	if p.Config.ClientSecret == "" {
+		form.Set("client_id", p.Config.ClientID)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, p.Metadata.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if p.Config.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(p.Config.ClientID), url.QueryEscape(p.Config.ClientSecret))
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	tokens := &TokenResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(tokens); err != nil {
+		return nil, fmt.Errorf("token response is not valid, %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK || tokens.Error != "" {
+		return nil, fmt.Errorf("token request failed: %s %s", tokens.Error, tokens.ErrorDescription)
+	}
+	if tokens.IDToken == "" {
+		return nil, errors.New("token response has no ID token")
+	}
+
+	return tokens, nil
+}
+
+// VerifyIDToken method for validate ID token: signature by keys of provider,
+// issuer, audience, expiration and nonce of the login.
+func (p *Provider) VerifyIDToken(raw, nonce string) (*Identity, error) {
+	token, err := jwt.Parse(raw, p.keyFunc)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return nil, errors.New("ID token is not valid")
+	}
+
+	if stringClaim(claims, "iss") != p.Metadata.Issuer {
+		return nil, errors.New("ID token issuer is not valid")
+	}
+
+	audience := stringsClaim(claims, "aud")
+	if !contains(audience, p.Config.ClientID) {
+		return nil, errors.New("ID token audience is not valid")
+	}
+	if azp := stringClaim(claims, "azp"); (len(audience) > 1 || azp != "") && azp != p.Config.ClientID {
+		return nil, errors.New("ID token authorized party is not valid")
+	}
+
+	if _, ok := claims["exp"]; !ok {
+		return nil, errors.New("ID token has no expiration")
+	}
+
+	if subtle.ConstantTimeCompare([]byte(stringClaim(claims, "nonce")), []byte(nonce)) != 1 {
+		return nil, errors.New("ID token nonce is not valid")
+	}
+
+	identity := &Identity{
+		Issuer:            stringClaim(claims, "iss"),
+		Subject:           stringClaim(claims, "sub"),
+		PreferredUsername: stringClaim(claims, "preferred_username"),
+		Email:             stringClaim(claims, "email"),
+		GivenName:         stringClaim(claims, "given_name"),
+		FamilyName:        stringClaim(claims, "family_name"),
+		Roles:             p.mapRoles(stringsClaim(claims, p.Config.RolesClaim)),
+		MultiFactor:       contains(stringsClaim(claims, "amr"), "mfa"),
+	}
+	if identity.Subject == "" {
+		return nil, errors.New("ID token has no subject")
+	}
+
+	return identity, nil
+}
+
+// mapRoles method for map roles of provider to local roles.
+// Roles without mapping are dropped.
+func (p *Provider) mapRoles(roles []string) []string {
+	result := []string{}
+	for _, role := range roles {
+		if local, ok := p.Config.RoleMapping[role]; ok && !contains(result, local) {
+			result = append(result, local)
+		}
+	}
+
+	return result
+}
+
+// keyFunc method for select key of provider to verify token.
+func (p *Provider) keyFunc(token *jwt.Token) (interface{}, error) {
+	// Only asymmetric signatures are accepted for ID tokens.
+	switch token.Method.(type) {
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS, *jwt.SigningMethodECDSA:
+	default:
+		if token.Method.Alg() != "EdDSA" {
+			return nil, fmt.Errorf("unexpected signing method %q of ID token", token.Method.Alg())
+		}
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	if key := p.lookupKey(kid); key != nil {
+		return key, nil
+	}
+
+	// Keys of provider may be rotated, so refresh them, but not too often.
+	p.mu.RLock()
+	recently := time.Since(p.keysFetched) < time.Minute
+	p.mu.RUnlock()
+	if !recently {
+		if err := p.refreshKeys(); err != nil {
+			return nil, err
+		}
+		if key := p.lookupKey(kid); key != nil {
+			return key, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unknown key ID %q of ID token", kid)
+}
+
+func (p *Provider) lookupKey(kid string) interface{} {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	// Token without key ID is allowed, if provider has only one key.
+	if kid == "" && len(p.keys) == 1 {
+		for _, key := range p.keys {
+			return key
+		}
+	}
+
+	return p.keys[kid]
+}
+
+func (p *Provider) refreshKeys() error {
+	set := &jsonWebKeySet{}
+	if err := p.getJSON(p.Metadata.JWKSURI, set); err != nil {
+		return fmt.Errorf("keys of provider are not loaded, %w", err)
+	}
+
+	keys := map[string]interface{}{}
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		// Keys of unsupported types are skipped.
+		if key, err := k.publicKey(); err == nil {
+			keys[k.Kid] = key
+		}
+	}
+
+	p.mu.Lock()
+	p.keys, p.keysFetched = keys, time.Now()
+	p.mu.Unlock()
+
+	return nil
+}
+
+func (p *Provider) getJSON(url string, v interface{}) error {
+	resp, err := p.client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d of %s", resp.StatusCode, url)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+func stringClaim(claims jwt.MapClaims, name string) string {
+	value, _ := claims[name].(string)
+	return value
+}
+
+// stringsClaim func for get claim, which may be a string or a list of strings.
+func stringsClaim(claims jwt.MapClaims, name string) []string {
+	switch value := claims[name].(type) {
+	case string:
+		return []string{value}
+	case []interface{}:
+		result := make([]string, 0, len(value))
+		for _, v := range value {
+			if s, ok := v.(string); ok {
+				result = append(result, s)
+			}
+		}
+		return result
+	}
+
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,205 @@
+package oidc
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockIdP struct to describe a local OpenID Connect provider for tests.
+type mockIdP struct {
+	*httptest.Server
+	key    *rsa.PrivateKey
+	kid    string
+	claims jwt.MapClaims
+
+	// Code challenge and nonce of the last authorization request.
+	challenge string
+	nonce     string
+}
+
+func newMockIdP(t *testing.T) *mockIdP {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	idp := &mockIdP{key: key, kid: "key-1"}
+	mux := http.NewServeMux()
+	idp.Server = httptest.NewServer(mux)
+	t.Cleanup(idp.Close)
+
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(Metadata{
+			Issuer:                idp.URL,
+			AuthorizationEndpoint: idp.URL + "/authorize",
+			TokenEndpoint:         idp.URL + "/token",
+			JWKSURI:               idp.URL + "/keys",
+		})
+	})
+
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": idp.kid,
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(idp.key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(idp.key.E)).Bytes()),
+		}}})
+	})
+
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		id, secret, _ := r.BasicAuth()
+		if r.PostFormValue("code") != "code-1" || id != "api" || secret != "secret" ||
+			CodeChallenge(r.PostFormValue("code_verifier")) != idp.challenge {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(TokenResponse{Error: "invalid_grant"})
+			return
+		}
+
+		claims := jwt.MapClaims{"nonce": idp.nonce}
+		for name, value := range idp.claims {
+			claims[name] = value
+		}
+		_ = json.NewEncoder(w).Encode(TokenResponse{IDToken: idp.sign(t, claims), TokenType: "Bearer"})
+	})
+
+	return idp
+}
+
+func (idp *mockIdP) sign(t *testing.T, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = idp.kid
+	s, err := token.SignedString(idp.key)
+	require.NoError(t, err)
+	return s
+}
+
+// authorize method for simulate login of user at provider.
+func (idp *mockIdP) authorize(t *testing.T, authURL string) {
+	u, err := url.Parse(authURL)
+	require.NoError(t, err)
+	assert.Equal(t, "/authorize", u.Path)
+	assert.Equal(t, "S256", u.Query().Get("code_challenge_method"))
+	idp.challenge = u.Query().Get("code_challenge")
+	idp.nonce = u.Query().Get("nonce")
+}
+
+func TestProviderLogin(t *testing.T) {
+	idp := newMockIdP(t)
+	provider, err := NewProvider(Config{
+		Issuer:       idp.URL,
+		ClientID:     "api",
+		ClientSecret: "secret",
+		RedirectURL:  "http://localhost/callback",
+		Scopes:       []string{"profile"},
+		RolesClaim:   "groups",
+		RoleMapping:  map[string]string{"admins": "admin", "staff": "user"},
+	})
+	require.NoError(t, err)
+
+	valid := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"iss":                idp.URL,
+			"sub":                "user-1",
+			"aud":                "api",
+			"exp":                time.Now().Add(time.Minute).Unix(),
+			"iat":                time.Now().Unix(),
+			"preferred_username": "alice",
+			"groups":             []string{"staff", "admins", "unknown"},
+		}
+	}
+
+	tests := []struct {
+		description string
+		change      func(claims jwt.MapClaims)
+		nonce       string
+		expectError bool
+	}{
+		{"valid ID token", func(jwt.MapClaims) {}, "", false},
+		{"audience as list", func(c jwt.MapClaims) { c["aud"] = []string{"api"} }, "", false},
+		{"other audience", func(c jwt.MapClaims) { c["aud"] = "other" }, "", true},
+		{"many audiences without azp", func(c jwt.MapClaims) { c["aud"] = []string{"api", "other"} }, "", true},
+		{"other issuer", func(c jwt.MapClaims) { c["iss"] = "http://evil" }, "", true},
+		{"expired", func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Minute).Unix() }, "", true},
+		{"no expiration", func(c jwt.MapClaims) { delete(c, "exp") }, "", true},
+		{"other nonce", func(jwt.MapClaims) {}, "replayed", true},
+	}
+
+	for _, test := range tests {
+		state, _ := RandomString()
+		nonce, _ := RandomString()
+		verifier, _ := RandomString()
+
+		idp.claims = valid()
+		test.change(idp.claims)
+		idp.authorize(t, provider.AuthCodeURL(state, nonce, verifier))
+		if test.nonce != "" {
+			idp.nonce = test.nonce
+		}
+
+		tokens, err := provider.Exchange("code-1", verifier)
+		require.NoError(t, err, test.description)
+
+		identity, err := provider.VerifyIDToken(tokens.IDToken, nonce)
+		if test.expectError {
+			assert.Error(t, err, test.description)
+			continue
+		}
+		require.NoError(t, err, test.description)
+		assert.Equal(t, "user-1", identity.Subject, test.description)
+		assert.Equal(t, "alice", identity.PreferredUsername, test.description)
+		assert.Equal(t, []string{"user", "admin"}, identity.Roles, test.description)
+	}
+
+	// Code can't be exchanged without the right PKCE verifier.
+	idp.authorize(t, provider.AuthCodeURL("state", "nonce", "verifier"))
+	_, err = provider.Exchange("code-1", "other-verifier")
+	assert.Error(t, err)
+
+	// Token signed by another key with a known key ID is rejected.
+	other, _ := rsa.GenerateKey(rand.Reader, 2048)
+	forged := jwt.NewWithClaims(jwt.SigningMethodRS256, valid())
+	forged.Header["kid"] = idp.kid
+	raw, _ := forged.SignedString(other)
+	_, err = provider.VerifyIDToken(raw, "")
+	assert.Error(t, err)
+
+	// HS256 token signed by client secret is not accepted.
+	raw, _ = jwt.NewWithClaims(jwt.SigningMethodHS256, valid()).SignedString([]byte("secret"))
+	_, err = provider.VerifyIDToken(raw, "")
+	assert.Error(t, err)
+}
+
+func TestProviderDiscoveryIssuerMismatch(t *testing.T) {
+	idp := newMockIdP(t)
+
+	_, err := NewProvider(Config{Issuer: idp.URL + "/other", ClientID: "api"})
+	assert.Error(t, err)
+}
+
+func TestLoginState(t *testing.T) {
+	secret := []byte("secret")
+	value, err := EncodeState(secret, &LoginState{State: "s", Nonce: "n", Verifier: "v", Expires: time.Now().Add(time.Minute).Unix()})
+	require.NoError(t, err)
+
+	state, err := DecodeState(secret, value)
+	require.NoError(t, err)
+	assert.Equal(t, "v", state.Verifier)
+
+	_, err = DecodeState([]byte("other"), value)
+	assert.Error(t, err)
+
+	expired, _ := EncodeState(secret, &LoginState{Expires: time.Now().Add(-time.Minute).Unix()})
+	_, err = DecodeState(secret, expired)
+	assert.Error(t, err)
+}
@@ -0,0 +1,88 @@
+package oidc
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	processSecret     []byte
+	processSecretOnce sync.Once
+)
+
+// StateSecret func for get secret to sign login state from .env file
+// (OIDC_STATE_SECRET). If it's not set, a random secret of process is used,
+// so logins started before restart are not finished.
+func StateSecret() []byte {
+	if secret := os.Getenv("OIDC_STATE_SECRET"); secret != "" {
+		return []byte(secret)
+	}
+
+	processSecretOnce.Do(func() {
+		processSecret = make([]byte, 32)
+		if _, err := rand.Read(processSecret); err != nil {
+			panic(err)
+		}
+	})
+
+	return processSecret
+}
+
+// LoginState struct to describe state of login between redirect to provider
+// and callback. It's kept by browser in a signed cookie, so no server storage is needed.
+type LoginState struct {
+	State    string `json:"s"`
+	Nonce    string `json:"n"`
+	Verifier string `json:"v"`
+	Expires  int64  `json:"e"`
+}
+
+// EncodeState func for sign login state by the given secret.
+func EncodeState(secret []byte, s *LoginState) (string, error) {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return "", err
+	}
+
+	payload := base64.RawURLEncoding.EncodeToString(data)
+
+	return payload + "." + sign(secret, payload), nil
+}
+
+// DecodeState func for verify signature and expiration of login state.
+func DecodeState(secret []byte, value string) (*LoginState, error) {
+	parts := strings.Split(value, ".")
+	if len(parts) != 2 || !hmac.Equal([]byte(parts[1]), []byte(sign(secret, parts[0]))) {
+		return nil, errors.New("login state is not valid")
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, err
+	}
+
+	s := &LoginState{}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, err
+	}
+
+	if time.Now().Unix() > s.Expires {
+		return nil, errors.New("login state is expired")
+	}
+
+	return s, nil
+}
+
+func sign(secret []byte, payload string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
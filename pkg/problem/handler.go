@@ -0,0 +1,69 @@
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"regexp"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// HeaderCorrelationID is header of correlation ID of request. Clients may send
+// their own ID, it's returned with problems of internal errors and logged.
+const HeaderCorrelationID = "X-Correlation-ID"
+
+// Handler func for write error of request as problem details, it's ErrorHandler
+// of Fiber app (see configs.FiberConfig). Errors, which are not problems, are
+// internal errors: they are logged with correlation ID, client gets only the ID.
+func Handler(c *fiber.Ctx, err error) error {
+	var p *Problem
+	var fiberErr *fiber.Error
+	switch {
+	case errors.As(err, &p):
+	case errors.As(err, &fiberErr):
+		p = FromStatus(fiberErr.Code, fiberErr.Message)
+	default:
+		p = Internal(err)
+	}
+	p.Instance = c.Path()
+
+	// Internal errors are logged, they may contain details of database.
+	if p.Status >= fiber.StatusInternalServerError {
+		p.CorrelationID = Report(c, p.Unwrap())
+	}
+
+	body, err := json.Marshal(p)
+	if err != nil {
+		return c.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	// Return status of problem and problem details.
+	c.Set(fiber.HeaderContentType, ContentType)
+	return c.Status(p.Status).Send(body)
+}
+
+// Report func for log internal error of request with correlation ID, which is
+// returned and set to response header, so only the ID is sent to client.
+func Report(c *fiber.Ctx, err error) string {
+	id := correlationID(c)
+	c.Set(HeaderCorrelationID, id)
+	log.Printf("[%s] %s %s: %v", id, c.Method(), c.Path(), err)
+
+	return id
+}
+
+// correlationIDPattern is a format of correlation ID of client, so it can be logged safely.
+var correlationIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// correlationID func for get correlation ID of request from its header, or a new ID.
+func correlationID(c *fiber.Ctx) string {
+	for _, header := range []string{HeaderCorrelationID, fiber.HeaderXRequestID} {
+		if id := c.Get(header); correlationIDPattern.MatchString(id) {
+			return id
+		}
+	}
+
+	return uuid.New().String()
+}
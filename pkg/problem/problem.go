@@ -0,0 +1,184 @@
+package problem
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// Codes of problems. Codes are stable, so clients may rely on them, unlike
+// on detail messages.
+const (
+	CodeBadRequest           = "bad_request"
+	CodeValidationFailed     = "validation_failed"
+	CodeUnauthorized         = "unauthorized"
+	CodeForbidden            = "forbidden"
+	CodeNotFound             = "not_found"
+	CodeConflict             = "conflict"
+	CodeUnsupportedMediaType = "unsupported_media_type"
+	CodeUnprocessable        = "unprocessable_entity"
+	CodeTooManyRequests      = "too_many_requests"
+	CodeInternal             = "internal_error"
+	CodeServiceUnavailable   = "service_unavailable"
+)
+
+// ContentType is media type of problem details (RFC 7807).
+const ContentType = "application/problem+json"
+
+// Problem struct to describe error of request as problem details (RFC 7807).
+// Problems are returned by handlers as errors and written by Handler.
+type Problem struct {
+	Type          string       `json:"type"`
+	Title         string       `json:"title"`
+	Status        int          `json:"status"`
+	Detail        string       `json:"detail,omitempty"`
+	Instance      string       `json:"instance,omitempty"`
+	Code          string       `json:"code"`
+	Errors        []FieldError `json:"errors,omitempty"`
+	CorrelationID string       `json:"correlation_id,omitempty"`
+
+	cause error // internal error, it's logged and never sent to client
+}
+
+// FieldError struct to describe invalid field of request.
+type FieldError struct {
+	Field  string `json:"field"`
+	Code   string `json:"code"` // failed validation rule, like "required"
+	Detail string `json:"detail"`
+}
+
+// New func for create a new problem with the given status, code and detail.
+func New(status int, code, detail string) *Problem {
+	return &Problem{
+		Type:   TypeURI(code),
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+		Code:   code,
+	}
+}
+
+// Error method for get message of problem.
+func (p *Problem) Error() string {
+	if p.cause != nil {
+		return p.Title + ": " + p.cause.Error()
+	}
+	if p.Detail != "" {
+		return p.Title + ": " + p.Detail
+	}
+
+	return p.Title
+}
+
+// Unwrap method for get internal error of problem.
+func (p *Problem) Unwrap() error {
+	return p.cause
+}
+
+// BadRequest func for create problem of malformed request, like invalid JSON body.
+func BadRequest(detail string) *Problem {
+	return New(http.StatusBadRequest, CodeBadRequest, detail)
+}
+
+// Validation func for create problem of invalid fields of request with the
+// failed rule of each field. Other errors are used as detail of problem.
+func Validation(err error) *Problem {
+	p := New(http.StatusBadRequest, CodeValidationFailed, "some fields are not valid")
+
+	var fields validator.ValidationErrors
+	if !errors.As(err, &fields) {
+		p.Detail = err.Error()
+		return p
+	}
+	for _, field := range fields {
+		p.Errors = append(p.Errors, FieldError{
+			Field:  field.Field(),
+			Code:   field.Tag(),
+			Detail: field.Error(),
+		})
+	}
+
+	return p
+}
+
+// Unauthorized func for create problem of request without valid credentials.
+func Unauthorized(detail string) *Problem {
+	return New(http.StatusUnauthorized, CodeUnauthorized, detail)
+}
+
+// Forbidden func for create problem of request, which is not permitted.
+func Forbidden(detail string) *Problem {
+	return New(http.StatusForbidden, CodeForbidden, detail)
+}
+
+// NotFound func for create problem of unknown resource.
+func NotFound(detail string) *Problem {
+	return New(http.StatusNotFound, CodeNotFound, detail)
+}
+
+// Conflict func for create problem of request, which conflicts with state of resource.
+func Conflict(detail string) *Problem {
+	return New(http.StatusConflict, CodeConflict, detail)
+}
+
+// UnsupportedMediaType func for create problem of request body of unknown media type.
+func UnsupportedMediaType(detail string) *Problem {
+	return New(http.StatusUnsupportedMediaType, CodeUnsupportedMediaType, detail)
+}
+
+// Unprocessable func for create problem of well-formed request, which can't be processed.
+func Unprocessable(detail string) *Problem {
+	return New(http.StatusUnprocessableEntity, CodeUnprocessable, detail)
+}
+
+// TooManyRequests func for create problem of request over limits.
+func TooManyRequests(detail string) *Problem {
+	return New(http.StatusTooManyRequests, CodeTooManyRequests, detail)
+}
+
+// Internal func for create problem of internal error, like database error.
+// The error is logged with correlation ID of request, client gets only the ID.
+func Internal(err error) *Problem {
+	p := New(http.StatusInternalServerError, CodeInternal, "internal error, report correlation ID to support")
+	p.cause = err
+
+	return p
+}
+
+// Unavailable func for create problem of unavailable external service, like
+// OpenID Connect provider. The error is logged like in Internal.
+func Unavailable(err error) *Problem {
+	p := New(http.StatusServiceUnavailable, CodeServiceUnavailable, "service is temporarily unavailable, retry later")
+	p.cause = err
+
+	return p
+}
+
+// FromStatus func for create problem of the given HTTP status, like 405 of
+// router, with code made from text of status.
+func FromStatus(status int, detail string) *Problem {
+	if status >= http.StatusInternalServerError {
+		return Internal(errors.New(detail))
+	}
+
+	code := strings.NewReplacer(" ", "_", "-", "_", "'", "").Replace(strings.ToLower(http.StatusText(status)))
+	if code == "" {
+		code = CodeBadRequest
+	}
+
+	return New(status, code, detail)
+}
+
+// TypeURI func for get URI of problem type by its code. Base of URIs is set by
+// PROBLEM_TYPE_BASE_URI, relative URIs like "/problems/not_found" are used by default.
+func TypeURI(code string) string {
+	base := os.Getenv("PROBLEM_TYPE_BASE_URI")
+	if base == "" {
+		base = "/problems/"
+	}
+
+	return base + code
+}
@@ -0,0 +1,106 @@
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidation(t *testing.T) {
+	record := struct {
+		Title string `validate:"required"`
+		Email string `validate:"omitempty,email"`
+	}{Email: "not-email"}
+
+	p := Validation(validator.New().Struct(record))
+	assert.Equal(t, 400, p.Status)
+	assert.Equal(t, CodeValidationFailed, p.Code)
+	assert.Equal(t, "/problems/validation_failed", p.Type)
+	require.Len(t, p.Errors, 2)
+	assert.Equal(t, "Title", p.Errors[0].Field)
+	assert.Equal(t, "required", p.Errors[0].Code)
+	assert.Equal(t, "Email", p.Errors[1].Field)
+	assert.Equal(t, "email", p.Errors[1].Code)
+
+	// Errors of hooks have no fields.
+	p = Validation(errors.New("title is reserved"))
+	assert.Equal(t, "title is reserved", p.Detail)
+	assert.Empty(t, p.Errors)
+}
+
+func TestFromStatus(t *testing.T) {
+	p := FromStatus(fiber.StatusMethodNotAllowed, "Method Not Allowed")
+	assert.Equal(t, 405, p.Status)
+	assert.Equal(t, "method_not_allowed", p.Code)
+
+	p = FromStatus(fiber.StatusBadGateway, "upstream error")
+	assert.Equal(t, 500, p.Status)
+	assert.Equal(t, CodeInternal, p.Code)
+	assert.NotContains(t, p.Detail, "upstream")
+}
+
+func TestHandler(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: Handler})
+	app.Get("/missing", func(c *fiber.Ctx) error {
+		return NotFound("book with the given ID is not found")
+	})
+	app.Get("/database", func(c *fiber.Ctx) error {
+		return errors.New("pq: password authentication failed")
+	})
+	app.Get("/fiber", func(c *fiber.Ctx) error {
+		return fiber.ErrUnauthorized
+	})
+
+	tests := []struct {
+		description   string
+		route         string
+		correlationID string // input header
+		expectedCode  int
+		expected      string // code of problem
+	}{
+		{"problem of handler", "/missing", "", 404, CodeNotFound},
+		{"internal error", "/database", "", 500, CodeInternal},
+		{"internal error with ID of client", "/database", "req-42", 500, CodeInternal},
+		{"internal error with unsafe ID of client", "/database", "bad id; drop", 500, CodeInternal},
+		{"error of fiber", "/fiber", "", 401, CodeUnauthorized},
+	}
+
+	for _, test := range tests {
+		req := httptest.NewRequest("GET", test.route, nil)
+		req.Header.Set(HeaderCorrelationID, test.correlationID)
+
+		resp, err := app.Test(req)
+		require.NoError(t, err, test.description)
+		assert.Equalf(t, test.expectedCode, resp.StatusCode, test.description)
+		assert.Equalf(t, ContentType, resp.Header.Get(fiber.HeaderContentType), test.description)
+
+		body, _ := io.ReadAll(resp.Body)
+		p := &Problem{}
+		require.NoError(t, json.Unmarshal(body, p), test.description)
+		assert.Equalf(t, test.expectedCode, p.Status, test.description)
+		assert.Equalf(t, test.expected, p.Code, test.description)
+		assert.Equalf(t, test.route, p.Instance, test.description)
+
+		if test.expectedCode < 500 {
+			assert.Emptyf(t, p.CorrelationID, test.description)
+			continue
+		}
+
+		// Internal errors are hidden, client gets only correlation ID.
+		assert.NotContainsf(t, string(body), "pq:", test.description)
+		assert.NotEmptyf(t, p.CorrelationID, test.description)
+		assert.Equalf(t, p.CorrelationID, resp.Header.Get(HeaderCorrelationID), test.description)
+		if test.correlationID == "req-42" {
+			assert.Equal(t, "req-42", p.CorrelationID)
+		}
+		assert.Falsef(t, strings.ContainsAny(p.CorrelationID, " ;"), test.description)
+	}
+}
@@ -0,0 +1,92 @@
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit struct to describe token bucket: up to Capacity requests at once,
+// refilled with Capacity tokens every Period.
+type Limit struct {
+	Capacity int
+	Period   time.Duration
+}
+
+// ParseLimit func for parse limit like "120/1m" (120 requests per minute).
+// Empty string means no limit.
+func ParseLimit(s string) (*Limit, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+
+	parts := strings.SplitN(s, "/", 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("rate limit %q must be like 120/1m", s)
+	}
+
+	capacity, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil || capacity <= 0 {
+		return nil, fmt.Errorf("capacity of rate limit %q is not valid", s)
+	}
+	period, err := time.ParseDuration(strings.TrimSpace(parts[1]))
+	if err != nil || period <= 0 {
+		return nil, fmt.Errorf("period of rate limit %q is not valid", s)
+	}
+
+	return &Limit{Capacity: capacity, Period: period}, nil
+}
+
+// Rate method for get count of tokens added to bucket per second.
+func (l Limit) Rate() float64 {
+	return float64(l.Capacity) / l.Period.Seconds()
+}
+
+// Refill method for get tokens of bucket after the elapsed time.
+func (l Limit) Refill(tokens float64, elapsed time.Duration) float64 {
+	if elapsed < 0 {
+		elapsed = 0
+	}
+
+	return math.Min(float64(l.Capacity), tokens+elapsed.Seconds()*l.Rate())
+}
+
+// Result struct to describe result of taking a token from bucket.
+type Result struct {
+	Allowed    bool
+	Limit      Limit
+	Remaining  int           // whole tokens left in bucket
+	Reset      time.Duration // time until bucket is full again
+	RetryAfter time.Duration // time until the next token, if not allowed
+}
+
+// NewResult func for make result by tokens left in bucket.
+func NewResult(limit Limit, tokens float64, allowed bool) Result {
+	result := Result{
+		Allowed:   allowed,
+		Limit:     limit,
+		Remaining: int(math.Floor(tokens)),
+		Reset:     seconds((float64(limit.Capacity) - tokens) / limit.Rate()),
+	}
+	if !allowed {
+		result.RetryAfter = seconds((1 - tokens) / limit.Rate())
+	}
+
+	return result
+}
+
+// Store interface to describe storage of buckets. Taking a token must be
+// atomic, so one store can be shared by all instances of app.
+type Store interface {
+	Take(key string, limit Limit, now time.Time) (Result, error)
+}
+
+func seconds(s float64) time.Duration {
+	if s < 0 {
+		return 0
+	}
+
+	return time.Duration(s * float64(time.Second))
+}
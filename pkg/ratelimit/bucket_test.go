@@ -0,0 +1,61 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLimit(t *testing.T) {
+	limit, err := ParseLimit("120/1m")
+	require.NoError(t, err)
+	assert.Equal(t, &Limit{Capacity: 120, Period: time.Minute}, limit)
+	assert.Equal(t, 2.0, limit.Rate())
+
+	limit, err = ParseLimit("")
+	assert.NoError(t, err)
+	assert.Nil(t, limit)
+
+	for _, s := range []string{"120", "0/1m", "x/1m", "10/0s", "10/minute"} {
+		_, err := ParseLimit(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	store := NewMemoryStore()
+	limit := Limit{Capacity: 2, Period: 2 * time.Second} // one token per second
+	now := time.Now()
+
+	// Bucket is full at start.
+	result, _ := store.Take("a", limit, now)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 1, result.Remaining)
+	assert.Equal(t, time.Second, result.Reset)
+
+	result, _ = store.Take("a", limit, now)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+
+	// Bucket is empty, the next token comes after a second.
+	result, _ = store.Take("a", limit, now.Add(500*time.Millisecond))
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 500*time.Millisecond, result.RetryAfter)
+
+	// Denied requests don't take tokens.
+	result, _ = store.Take("a", limit, now.Add(time.Second))
+	assert.True(t, result.Allowed)
+
+	// Buckets of other keys are independent.
+	result, _ = store.Take("b", limit, now)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 1, result.Remaining)
+
+	// Bucket is never filled over capacity, and full buckets are swept.
+	result, _ = store.Take("a", limit, now.Add(time.Hour))
+	assert.Equal(t, 1, result.Remaining)
+	store.Take("c", limit, now.Add(2*time.Hour))
+	assert.Len(t, store.buckets, 1)
+}
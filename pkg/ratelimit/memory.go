@@ -0,0 +1,60 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// MemoryStore struct to describe store of buckets in memory of process.
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	limit   Limit
+}
+
+// NewMemoryStore func for create a new store in memory.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: map[string]*bucket{}}
+}
+
+// Take method for take a token from bucket of the given key.
+func (s *MemoryStore) Take(key string, limit Limit, now time.Time) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep(now)
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Capacity), updated: now}
+		s.buckets[key] = b
+	}
+	b.tokens, b.updated, b.limit = limit.Refill(b.tokens, now.Sub(b.updated)), now, limit
+
+	allowed := b.tokens >= 1
+	if allowed {
+		b.tokens--
+	}
+
+	return NewResult(limit, b.tokens, allowed), nil
+}
+
+// sweep method for delete full buckets once a minute, they are the same as new ones.
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < time.Minute {
+		return
+	}
+	s.lastSweep = now
+
+	for key, b := range s.buckets {
+		if b.limit.Refill(b.tokens, now.Sub(b.updated)) >= float64(b.limit.Capacity) {
+			delete(s.buckets, key)
+		}
+	}
+}
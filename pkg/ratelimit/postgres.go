@@ -0,0 +1,62 @@
+package ratelimit
+
+import (
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// PostgresStore struct to describe store of buckets in PostgreSQL,
+// shared by all instances of app.
+type PostgresStore struct {
+	DB *sqlx.DB
+}
+
+// Take method for take a token from bucket of the given key. Bucket row is
+// locked in transaction, so concurrent requests can't take the same token.
+func (s *PostgresStore) Take(key string, limit Limit, now time.Time) (Result, error) {
+	tx, err := s.DB.Beginx()
+	if err != nil {
+		return Result{}, err
+	}
+	defer tx.Rollback()
+
+	// Create a full bucket, if it's not exists yet.
+	if _, err := tx.Exec(
+		`INSERT INTO rate_limits (key, tokens, updated_at) VALUES ($1, $2, $3) ON CONFLICT (key) DO NOTHING`,
+		key, float64(limit.Capacity), now,
+	); err != nil {
+		return Result{}, err
+	}
+
+	// Lock bucket until end of transaction.
+	var b struct {
+		Tokens    float64   `db:"tokens"`
+		UpdatedAt time.Time `db:"updated_at"`
+	}
+	if err := tx.Get(&b, `SELECT tokens, updated_at FROM rate_limits WHERE key = $1 FOR UPDATE`, key); err != nil {
+		return Result{}, err
+	}
+
+	tokens := limit.Refill(b.Tokens, now.Sub(b.UpdatedAt))
+	allowed := tokens >= 1
+	if allowed {
+		tokens--
+	}
+
+	if _, err := tx.Exec(`UPDATE rate_limits SET tokens = $2, updated_at = $3 WHERE key = $1`, key, tokens, now); err != nil {
+		return Result{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return Result{}, err
+	}
+
+	return NewResult(limit, tokens, allowed), nil
+}
+
+// Prune method for delete buckets, which were not used for the given time.
+func (s *PostgresStore) Prune(unused time.Duration) error {
+	_, err := s.DB.Exec(`DELETE FROM rate_limits WHERE updated_at < $1`, time.Now().Add(-unused))
+	return err
+}
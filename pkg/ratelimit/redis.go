@@ -0,0 +1,84 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// takeScript is Lua script of taking a token from bucket, like MemoryStore.Take.
+// Script is atomic in Redis, so concurrent requests can't take the same token.
+// Bucket expires, when it's full again, because a new bucket is the same.
+const takeScript = `
+local capacity = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local bucket = redis.call('HMGET', KEYS[1], 'tokens', 'updated')
+local tokens = tonumber(bucket[1]) or capacity
+local elapsed = math.max(0, now - (tonumber(bucket[2]) or now))
+tokens = math.min(capacity, tokens + elapsed * rate)
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+redis.call('HMSET', KEYS[1], 'tokens', tostring(tokens), 'updated', tostring(now))
+redis.call('PEXPIRE', KEYS[1], ARGV[4])
+return {allowed, tostring(tokens)}
+`
+
+// takeRedisScript is script of taking a token, which is run by EVALSHA, and
+// loaded by EVAL only if server doesn't have it yet.
+var takeRedisScript = redis.NewScript(takeScript)
+
+// RedisStore struct to describe store of buckets in Redis or compatible server
+// (Valkey, KeyDB, Dragonfly), shared by all instances of app. Connections are
+// kept in pool of client.
+type RedisStore struct {
+	client *redis.Client
+}
+
+// NewRedisStore func for create store by URL like "redis://:password@host:6379/0",
+// "rediss" scheme is used for TLS connections.
+func NewRedisStore(rawURL string) (*RedisStore, error) {
+	options, err := redis.ParseURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RedisStore{client: redis.NewClient(options)}, nil
+}
+
+// Take method for take a token from bucket of the given key.
+func (s *RedisStore) Take(key string, limit Limit, now time.Time) (Result, error) {
+	values, err := takeRedisScript.Run(context.Background(), s.client, []string{"rate_limit:" + key},
+		limit.Capacity,
+		strconv.FormatFloat(limit.Rate()/1000, 'g', -1, 64),
+		now.UnixMilli(),
+		int64(math.Ceil(float64(limit.Period)/float64(time.Millisecond))),
+	).Slice()
+	if err != nil {
+		return Result{}, err
+	}
+
+	if len(values) != 2 {
+		return Result{}, fmt.Errorf("unexpected reply of rate limit script: %v", values)
+	}
+	allowed, _ := values[0].(int64)
+	remaining, _ := values[1].(string)
+	tokens, err := strconv.ParseFloat(remaining, 64)
+	if err != nil {
+		return Result{}, fmt.Errorf("unexpected tokens of rate limit script: %w", err)
+	}
+
+	return NewResult(limit, tokens, allowed == 1), nil
+}
+
+// Close method for close connections of store.
+func (s *RedisStore) Close() error {
+	return s.client.Close()
+}
@@ -0,0 +1,26 @@
+package repository
+
+const (
+	// AdminRoleName const for admin role.
+	AdminRoleName string = "admin"
+
+	// UserRoleName const for user role.
+	UserRoleName string = "user"
+
+	// AnonymousRoleName const for role of callers without token.
+	AnonymousRoleName string = "anonymous"
+)
+
+const (
+	// OrganizationOwnerRole const for member, who manages organization and its owners.
+	OrganizationOwnerRole string = "owner"
+
+	// OrganizationAdminRole const for member, who manages members and all records of organization.
+	OrganizationAdminRole string = "admin"
+
+	// OrganizationMemberRole const for member, who creates records and modifies own records.
+	OrganizationMemberRole string = "member"
+
+	// OrganizationViewerRole const for member, who only reads records of organization.
+	OrganizationViewerRole string = "viewer"
+)
@@ -0,0 +1,89 @@
+package revocation
+
+import (
+	"sync"
+	"time"
+
+	"github.com/koddr/tutorial-go-fiber-rest-api/app/models"
+)
+
+// Store interface to describe storage of revoked tokens, shared by all instances of app.
+type Store interface {
+	GetRevokedTokens() ([]models.RevokedToken, error)
+	CreateRevokedToken(t *models.RevokedToken) error
+	DeleteExpiredRevokedTokens() error
+}
+
+// Denylist struct to describe revoked tokens. Tokens are checked by the
+// in-process cache only, which is synced with the store periodically.
+type Denylist struct {
+	store Store
+
+	mu      sync.RWMutex
+	revoked map[string]time.Time // token ID => expiration of token
+}
+
+// NewDenylist func for create a new denylist of the given store.
+func NewDenylist(store Store) *Denylist {
+	return &Denylist{store: store, revoked: map[string]time.Time{}}
+}
+
+// IsRevoked method for checking, if token with the given ID is revoked.
+func (d *Denylist) IsRevoked(tokenID string) bool {
+	if tokenID == "" {
+		return false
+	}
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	_, ok := d.revoked[tokenID]
+
+	return ok
+}
+
+// Revoke method for revoke token until its expiration. Token is rejected
+// by this instance at once, by other instances after their next sync.
+func (d *Denylist) Revoke(t *models.RevokedToken) error {
+	if err := d.store.CreateRevokedToken(t); err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	d.revoked[t.TokenID] = t.ExpiresAt
+	d.mu.Unlock()
+
+	return nil
+}
+
+// Sync method for replace cache by not expired tokens of the store.
+func (d *Denylist) Sync() error {
+	tokens, err := d.store.GetRevokedTokens()
+	if err != nil {
+		return err
+	}
+
+	revoked := make(map[string]time.Time, len(tokens))
+	for _, t := range tokens {
+		revoked[t.TokenID] = t.ExpiresAt
+	}
+
+	d.mu.Lock()
+	d.revoked = revoked
+	d.mu.Unlock()
+
+	return nil
+}
+
+// Prune method for delete expired tokens from the store and the cache.
+func (d *Denylist) Prune(now time.Time) error {
+	d.mu.Lock()
+	for tokenID, expiresAt := range d.revoked {
+		if !expiresAt.After(now) {
+			delete(d.revoked, tokenID)
+		}
+	}
+	d.mu.Unlock()
+
+	return d.store.DeleteExpiredRevokedTokens()
+}
@@ -0,0 +1,78 @@
+package revocation
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/koddr/tutorial-go-fiber-rest-api/app/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryStore struct to describe store shared by denylists of many instances.
+type memoryStore struct {
+	tokens map[string]models.RevokedToken
+	down   bool
+}
+
+func (s *memoryStore) GetRevokedTokens() ([]models.RevokedToken, error) {
+	if s.down {
+		return nil, errors.New("store is down")
+	}
+	tokens := []models.RevokedToken{}
+	for _, t := range s.tokens {
+		if t.ExpiresAt.After(time.Now()) {
+			tokens = append(tokens, t)
+		}
+	}
+	return tokens, nil
+}
+
+func (s *memoryStore) CreateRevokedToken(t *models.RevokedToken) error {
+	if s.down {
+		return errors.New("store is down")
+	}
+	if _, ok := s.tokens[t.TokenID]; !ok {
+		s.tokens[t.TokenID] = *t
+	}
+	return nil
+}
+
+func (s *memoryStore) DeleteExpiredRevokedTokens() error {
+	for id, t := range s.tokens {
+		if !t.ExpiresAt.After(time.Now()) {
+			delete(s.tokens, id)
+		}
+	}
+	return nil
+}
+
+func TestDenylist(t *testing.T) {
+	store := &memoryStore{tokens: map[string]models.RevokedToken{}}
+	instance, other := NewDenylist(store), NewDenylist(store)
+
+	// Token is rejected by the revoking instance at once, by others after sync.
+	require.NoError(t, instance.Revoke(&models.RevokedToken{TokenID: "a", ExpiresAt: time.Now().Add(time.Minute)}))
+	assert.True(t, instance.IsRevoked("a"))
+	assert.False(t, other.IsRevoked("a"))
+	require.NoError(t, other.Sync())
+	assert.True(t, other.IsRevoked("a"))
+	assert.False(t, other.IsRevoked("b"))
+	assert.False(t, other.IsRevoked(""))
+
+	// Failed revocation is not cached, failed sync keeps the cache.
+	store.down = true
+	assert.Error(t, instance.Revoke(&models.RevokedToken{TokenID: "b", ExpiresAt: time.Now().Add(time.Minute)}))
+	assert.False(t, instance.IsRevoked("b"))
+	assert.Error(t, other.Sync())
+	assert.True(t, other.IsRevoked("a"))
+	store.down = false
+
+	// Expired tokens are pruned from the cache and the store.
+	require.NoError(t, instance.Revoke(&models.RevokedToken{TokenID: "c", ExpiresAt: time.Now().Add(-time.Second)}))
+	require.NoError(t, instance.Prune(time.Now()))
+	assert.False(t, instance.IsRevoked("c"))
+	assert.True(t, instance.IsRevoked("a"))
+	assert.Len(t, store.tokens, 1)
+}
@@ -0,0 +1,106 @@
+package revocation
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/koddr/tutorial-go-fiber-rest-api/app/models"
+	"github.com/koddr/tutorial-go-fiber-rest-api/app/queries"
+	"github.com/koddr/tutorial-go-fiber-rest-api/platform/database"
+)
+
+var (
+	current   *Denylist
+	startOnce sync.Once
+)
+
+// CurrentDenylist func for get the denylist of app. It's synced with database every
+// REVOCATION_SYNC_SECONDS (default 5) seconds, and expired tokens are pruned
+// every REVOCATION_PRUNE_MINUTES (default 60) minutes. While database is not
+// available, the last synced tokens are used.
+func CurrentDenylist() *Denylist {
+	startOnce.Do(start)
+
+	return current
+}
+
+func start() {
+	current = NewDenylist(&postgresStore{})
+
+	syncSeconds, _ := strconv.Atoi(os.Getenv("REVOCATION_SYNC_SECONDS"))
+	if syncSeconds <= 0 {
+		syncSeconds = 5
+	}
+	pruneMinutes, _ := strconv.Atoi(os.Getenv("REVOCATION_PRUNE_MINUTES"))
+	if pruneMinutes <= 0 {
+		pruneMinutes = 60
+	}
+
+	if err := current.Sync(); err != nil {
+		log.Printf("Oops... Revoked tokens are not loaded! Reason: %v", err)
+	}
+
+	go watch(time.Duration(syncSeconds)*time.Second, time.Duration(pruneMinutes)*time.Minute)
+}
+
+// watch func for sync and prune the current denylist.
+func watch(syncInterval, pruneInterval time.Duration) {
+	syncTicker := time.NewTicker(syncInterval)
+	pruneTicker := time.NewTicker(pruneInterval)
+
+	for {
+		select {
+		case <-syncTicker.C:
+			if err := current.Sync(); err != nil {
+				log.Printf("Oops... Revoked tokens are not synced! Reason: %v", err)
+			}
+		case now := <-pruneTicker.C:
+			if err := current.Prune(now); err != nil {
+				log.Printf("Oops... Revoked tokens are not pruned! Reason: %v", err)
+			}
+		}
+	}
+}
+
+// postgresStore struct to describe store of revoked tokens in PostgreSQL.
+// Shared pool of connections of app is used by all syncs.
+type postgresStore struct{}
+
+func (s *postgresStore) queries() (*queries.RevokedTokenQueries, error) {
+	db, err := database.SharedConnection()
+	if err != nil {
+		return nil, err
+	}
+
+	return &queries.RevokedTokenQueries{DB: db}, nil
+}
+
+func (s *postgresStore) GetRevokedTokens() ([]models.RevokedToken, error) {
+	q, err := s.queries()
+	if err != nil {
+		return nil, err
+	}
+
+	return q.GetRevokedTokens()
+}
+
+func (s *postgresStore) CreateRevokedToken(t *models.RevokedToken) error {
+	q, err := s.queries()
+	if err != nil {
+		return err
+	}
+
+	return q.CreateRevokedToken(t)
+}
+
+func (s *postgresStore) DeleteExpiredRevokedTokens() error {
+	q, err := s.queries()
+	if err != nil {
+		return err
+	}
+
+	return q.DeleteExpiredRevokedTokens()
+}
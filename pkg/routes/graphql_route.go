@@ -0,0 +1,13 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/koddr/tutorial-go-fiber-rest-api/app/controllers"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/middleware"
+)
+
+// GraphQLRoute func for describe GraphQL endpoint of admin-ui.
+func GraphQLRoute(a *fiber.App) {
+	// Routes for POST method:
+	a.Post("/graphql", middleware.RateLimited("api"), middleware.RateLimitedWhen("auth", controllers.IsGraphQLLogin), middleware.Identified(), controllers.GraphQL) // execute GraphQL query or mutation, login is limited like sign in
+}
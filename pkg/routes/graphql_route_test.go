@@ -0,0 +1,166 @@
+package routes
+
+import (
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/joho/godotenv"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/configs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGraphQLRoute(t *testing.T) {
+	// Load .env.test file from the root folder
+	if err := godotenv.Load("../../.env.test"); err != nil {
+		panic(err)
+	}
+
+	// Define a structure for specifying input and output data of a single test case.
+	tests := []struct {
+		description  string
+		body         string // GraphQL request
+		expectedCode int
+		expectedData string // field of data in response
+		expectedErr  string // code of the first error in response
+	}{
+		{
+			description:  "invalid JSON body",
+			body:         `{"query":`,
+			expectedCode: 400,
+		},
+		{
+			description:  "introspection of schema",
+			body:         `{"query":"{ __schema { queryType { name } } }"}`,
+			expectedCode: 200,
+			expectedData: "__schema",
+		},
+		{
+			description:  "get books without token (books belong to organizations of users)",
+			body:         `{"query":"{ books { id title } }"}`,
+			expectedCode: 200,
+			expectedErr:  "UNAUTHENTICATED",
+		},
+		{
+			description:  "get users without token",
+			body:         `{"query":"{ _usersMeta { count } }"}`,
+			expectedCode: 200,
+			expectedErr:  "UNAUTHENTICATED",
+		},
+		{
+			description:  "login without password",
+			body:         `{"query":"mutation { login(credentials: {username: \"admin\", password: \"\"}) { accessToken } }"}`,
+			expectedCode: 200,
+			expectedErr:  "BAD_USER_INPUT",
+		},
+	}
+
+	// Define Fiber app.
+	app := fiber.New(configs.FiberConfig())
+
+	// Define routes.
+	GraphQLRoute(app)
+
+	// Iterate through test single test cases
+	for _, test := range tests {
+		// Create a new http request with the GraphQL request from the test case.
+		req := httptest.NewRequest("POST", "/graphql", strings.NewReader(test.body))
+		req.Header.Set("Content-Type", "application/json")
+
+		// Perform the request plain with the app.
+		resp, err := app.Test(req, -1) // the -1 disables request latency
+		assert.Nilf(t, err, test.description)
+
+		// Verify, if the status code is as expected.
+		assert.Equalf(t, test.expectedCode, resp.StatusCode, test.description)
+		if resp.StatusCode != fiber.StatusOK {
+			continue
+		}
+
+		// Parse the response body.
+		body, err := io.ReadAll(resp.Body)
+		assert.Nilf(t, err, test.description)
+		result := struct {
+			Data   map[string]interface{} `json:"data"`
+			Errors []struct {
+				Extensions map[string]interface{} `json:"extensions"`
+			} `json:"errors"`
+		}{}
+		assert.Nilf(t, json.Unmarshal(body, &result), test.description)
+
+		// Verify, if data or error is as expected.
+		if test.expectedData != "" {
+			assert.Emptyf(t, result.Errors, test.description)
+			assert.Containsf(t, result.Data, test.expectedData, test.description)
+		}
+		if test.expectedErr != "" && assert.NotEmptyf(t, result.Errors, test.description) {
+			assert.Equalf(t, test.expectedErr, result.Errors[0].Extensions["code"], test.description)
+		}
+	}
+}
+
+func TestGraphQLLoginRateLimit(t *testing.T) {
+	// Load .env.test file from the root folder
+	if err := godotenv.Load("../../.env.test"); err != nil {
+		panic(err)
+	}
+	os.Setenv("RATE_LIMIT_AUTH", "3/1h")
+	defer os.Setenv("RATE_LIMIT_AUTH", "")
+
+	login := `login(credentials: {username: \"admin\", password: \"\"}) { accessToken }`
+	tests := []struct {
+		description  string
+		body         string // GraphQL request
+		expectedCode int
+	}{
+		{
+			description:  "login",
+			body:         `{"query":"mutation { ` + login + ` }"}`,
+			expectedCode: 200,
+		},
+		{
+			description:  "query is not limited like login",
+			body:         `{"query":"{ __schema { queryType { name } } }"}`,
+			expectedCode: 200,
+		},
+		{
+			description:  "login in fragment",
+			body:         `{"query":"mutation { ...auth } fragment auth on Mutation { ` + login + ` }"}`,
+			expectedCode: 200,
+		},
+		{
+			description:  "many logins in one request",
+			body:         `{"query":"mutation { a: ` + login + ` b: ` + login + ` }"}`,
+			expectedCode: 200,
+		},
+		{
+			description:  "login after limit of auth",
+			body:         `{"query":"mutation Other { ` + login + ` }","operationName":"Other"}`,
+			expectedCode: 429,
+		},
+		{
+			description:  "query after limit of auth",
+			body:         `{"query":"{ __schema { queryType { name } } }"}`,
+			expectedCode: 200,
+		},
+	}
+
+	// Define Fiber app.
+	app := fiber.New(configs.FiberConfig())
+
+	// Define routes.
+	GraphQLRoute(app)
+
+	for _, test := range tests {
+		req := httptest.NewRequest("POST", "/graphql", strings.NewReader(test.body))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req, -1)
+		assert.Nilf(t, err, test.description)
+		assert.Equalf(t, test.expectedCode, resp.StatusCode, test.description)
+	}
+}
@@ -1,6 +1,9 @@
 package routes
 
-import "github.com/gofiber/fiber/v2"
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/problem"
+)
 
 // NotFoundRoute func for describe 404 Error route.
 func NotFoundRoute(a *fiber.App) {
@@ -9,10 +12,7 @@ func NotFoundRoute(a *fiber.App) {
 		// Anonimus function.
 		func(c *fiber.Ctx) error {
 			// Return HTTP 404 status and JSON response.
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"error": true,
-				"msg":   "sorry, endpoint is not found",
-			})
+			return problem.NotFound("sorry, endpoint is not found")
 		},
 	)
 }
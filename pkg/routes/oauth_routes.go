@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/koddr/tutorial-go-fiber-rest-api/app/controllers"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/middleware"
+)
+
+// OAuthRoutes func for describe group of OAuth 2.0 routes for machine clients.
+func OAuthRoutes(a *fiber.App) {
+	// Create routes group, clients and devices are limited as sign in.
+	route := a.Group("/oauth", middleware.RateLimited("auth"))
+
+	// Routes for POST method:
+	route.Post("/token", controllers.OAuthToken)                         // issue tokens by client credentials or device code
+	route.Post("/introspect", controllers.OAuthIntrospect)               // get state of access token
+	route.Post("/revoke", controllers.OAuthRevoke)                       // revoke access token of client
+	route.Post("/device_authorization", controllers.DeviceAuthorization) // start device authorization
+
+	// Page, where user approves device by user code:
+	a.Get("/device", controllers.DevicePage)
+}
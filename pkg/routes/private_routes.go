@@ -4,25 +4,90 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/koddr/tutorial-go-fiber-rest-api/app/controllers"
 	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/middleware"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/utils"
 )
 
 // PrivateRoutes func for describe group of private routes.
 func PrivateRoutes(a *fiber.App) {
 	// Create routes group.
-	route := a.Group("/private")
+	route := a.Group("/api/v1")
+
+	// Routes for records of organization:
+	privateResourceRoutes(route, controllers.Books)
+	privateResourceRoutes(route, controllers.Info)
+	privateResourceRoutes(route, controllers.Servers)
 
 	// Routes for POST method:
-	route.Post("/book", middleware.JWTProtected(), controllers.CreateBook) // create a new book
+	route.Post("/profile", middleware.Protected(), middleware.AccessControlled("profiles", "create"), controllers.CreateProfile) // create a new profile
 
-	// Routes for PUT method:
-	route.Put("/book", middleware.JWTProtected(), controllers.UpdateBook) // update one book by ID
+	// Routes for users (only admins create, update and delete users):
+	route.Get("/users", middleware.Protected(), middleware.AccessControlled("users", "read"), controllers.GetUsers)            // get page of users
+	route.Get("/users/:id", middleware.Protected(), middleware.AccessControlled("users", "read"), controllers.GetUser)         // get one user by ID
+	route.Post("/users", middleware.Protected(), middleware.AccessControlled("users", "create"), controllers.CreateUser)       // create a new user
+	route.Put("/users/:id", middleware.Protected(), middleware.AccessControlled("users", "update"), controllers.UpdateUser)    // update one user by ID
+	route.Delete("/users/:id", middleware.Protected(), middleware.AccessControlled("users", "delete"), controllers.DeleteUser) // delete one user by ID
 
-	// Routes for DELETE method:
-	route.Delete("/book", middleware.JWTProtected(), controllers.DeleteBook) // delete one book by ID
+	// Routes for sign out:
+	route.Post("/user/sign/out", middleware.JWTProtected(), controllers.UserSignOut)               // revoke the current session
+	route.Post("/user/sign/out/all", middleware.JWTProtected(), controllers.UserSignOutEverywhere) // revoke all sessions of user
+
+	// Routes for cookie session of browser:
+	route.Get("/session", middleware.JWTProtected(), controllers.GetSession)       // get the current session and its user
+	route.Delete("/session", middleware.JWTProtected(), controllers.DeleteSession) // revoke the current session, delete cookies
+
+	// Routes for revocation of access tokens:
+	route.Post("/token/revoke", middleware.JWTProtected(), controllers.RevokeCurrentToken) // revoke the current access token
+	route.Post("/admin/token/revoke", middleware.JWTProtected(), controllers.RevokeToken)  // revoke access token by ID (admin only)
+
+	// Routes for security of sign in (admin only):
+	route.Get("/admin/security/events", middleware.JWTProtected(), controllers.GetSecurityEvents) // get the latest security events
+	route.Get("/admin/security/lockouts", middleware.JWTProtected(), controllers.GetLockedLogins) // get locked usernames and IPs
+	route.Post("/admin/security/unlock", middleware.JWTProtected(), controllers.UnlockUser)       // unlock sign in of username
+
+	// Routes for the second factor of user:
+	route.Post("/user/2fa/enroll", middleware.JWTProtected(), controllers.EnrollTwoFactor)   // create a new TOTP secret
+	route.Get("/user/2fa/qr", middleware.JWTProtected(), controllers.GetTwoFactorQRCode)     // get QR code of pending secret
+	route.Post("/user/2fa/confirm", middleware.JWTProtected(), controllers.ConfirmTwoFactor) // enable second factor, get recovery codes
+	route.Post("/user/2fa/disable", middleware.JWTProtected(), controllers.DisableTwoFactor) // disable second factor
+
+	// Routes for email of user:
+	route.Put("/user/email", middleware.JWTProtected(), controllers.ChangeEmail)                         // set a new email and send link of its verification
+	route.Post("/user/email/verification", middleware.JWTProtected(), controllers.SendEmailVerification) // send link of email verification again
 
-	route.Get("/server", middleware.JWTProtected(), controllers.GetServer) // get one server by ID")
-	route.Post("/server", middleware.JWTProtected(), controllers.CreateServer) // create a new server 
-	route.Put("/server", middleware.JWTProtected(), controllers.UpdateServer) // update one server by ID
-	route.Delete("/server", middleware.JWTProtected(), controllers.DeleteServer) // delete one server by ID
+	// Invitations are signed by secret of .env file, app doesn't start without it.
+	if _, err := utils.InvitationSecret(); err != nil {
+		panic(err)
+	}
 
+	// Routes for organizations and their members:
+	route.Get("/organizations", middleware.JWTProtected(), controllers.GetOrganizations)                // get organizations of the current user
+	route.Post("/organization", middleware.JWTProtected(), controllers.CreateOrganization)              // create a new organization
+	route.Put("/organization/:id", middleware.JWTProtected(), controllers.UpdateOrganization)           // rename organization
+	route.Delete("/organization/:id", middleware.JWTProtected(), controllers.DeleteOrganization)        // delete organization with its records
+	route.Get("/organization/:id/members", middleware.JWTProtected(), controllers.GetMembers)           // get members of organization
+	route.Put("/organization/:id/member", middleware.JWTProtected(), controllers.UpdateMember)          // change role of member
+	route.Delete("/organization/:id/member", middleware.JWTProtected(), controllers.DeleteMember)       // remove member, or leave organization
+	route.Post("/organization/:id/invitation", middleware.JWTProtected(), controllers.CreateInvitation) // send signed link of invitation
+	route.Post("/invitation/accept", middleware.JWTProtected(), controllers.AcceptInvitation)           // join organization by link of invitation
+
+	// Routes for OAuth clients (admin only):
+	route.Get("/admin/oauth/clients", middleware.JWTProtected(), controllers.GetOAuthClients)     // get list of OAuth clients
+	route.Post("/admin/oauth/client", middleware.JWTProtected(), controllers.CreateOAuthClient)   // register a new OAuth client
+	route.Delete("/admin/oauth/client", middleware.JWTProtected(), controllers.RevokeOAuthClient) // revoke one OAuth client by ID
+
+	// Routes for device authorization by user:
+	route.Get("/device", middleware.JWTProtected(), controllers.GetDeviceAuthorization)              // get pending request by user code
+	route.Post("/device/approve", middleware.JWTProtected(), controllers.ApproveDeviceAuthorization) // approve device
+	route.Post("/device/deny", middleware.JWTProtected(), controllers.DenyDeviceAuthorization)       // deny device
+
+	// Routes for API keys (only users with JWT can manage keys):
+	route.Get("/apikeys", middleware.JWTProtected(), controllers.GetAPIKeys)     // get list of API keys of user
+	route.Post("/apikey", middleware.JWTProtected(), controllers.CreateAPIKey)   // create a new API key
+	route.Delete("/apikey", middleware.JWTProtected(), controllers.RevokeAPIKey) // revoke one API key by ID
+
+	// Routes for PUT method:
+	route.Put("/profile", middleware.Protected(), middleware.AccessControlled("profiles", "update"), controllers.UpdateProfile) // update one profile by ID
+
+	// Routes for DELETE method:
+	route.Delete("/profile", middleware.Protected(), middleware.AccessControlled("profiles", "delete"), controllers.DeleteProfile) // delete one profile by ID
 }
@@ -7,7 +7,9 @@ import (
 	"testing"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 	"github.com/joho/godotenv"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/configs"
 	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/utils"
 	"github.com/stretchr/testify/assert"
 )
@@ -22,7 +24,10 @@ func TestPrivateRoutes(t *testing.T) {
 	dataString := `{"id": "00000000-0000-0000-0000-000000000000"}`
 
 	// Create access token.
-	token, err := utils.GenerateNewAccessToken()
+	token, err := utils.GenerateNewAccessToken(&utils.Principal{
+		Subject: uuid.New().String(),
+		Roles:   []string{"user"},
+	})
 	if err != nil {
 		panic(err)
 	}
@@ -37,6 +42,24 @@ func TestPrivateRoutes(t *testing.T) {
 		expectedError bool
 		expectedCode  int
 	}{
+		{
+			description:   "get book by ID without JWT (books are read only by members of organization)",
+			route:         "/api/v1/book/" + uuid.New().String(),
+			method:        "GET",
+			tokenString:   "",
+			body:          nil,
+			expectedError: false,
+			expectedCode:  400,
+		},
+		{
+			description:   "get books without JWT",
+			route:         "/api/v1/books",
+			method:        "GET",
+			tokenString:   "",
+			body:          nil,
+			expectedError: false,
+			expectedCode:  400,
+		},
 		{
 			description:   "delete book without JWT and body",
 			route:         "/api/v1/book",
@@ -64,10 +87,37 @@ func TestPrivateRoutes(t *testing.T) {
 			expectedError: false,
 			expectedCode:  404,
 		},
+		{
+			description:   "patch book without JWT",
+			route:         "/api/v1/book/00000000-0000-0000-0000-000000000000",
+			method:        "PATCH",
+			tokenString:   "",
+			body:          strings.NewReader(`{"title": "New title"}`),
+			expectedError: false,
+			expectedCode:  400,
+		},
+		{
+			description:   "create user without JWT",
+			route:         "/api/v1/users",
+			method:        "POST",
+			tokenString:   "",
+			body:          nil,
+			expectedError: false,
+			expectedCode:  400,
+		},
+		{
+			description:   "update user without admin role",
+			route:         "/api/v1/users/00000000-0000-0000-0000-000000000000",
+			method:        "PUT",
+			tokenString:   "Bearer " + token,
+			body:          strings.NewReader(`{"password": "new-password"}`),
+			expectedError: false,
+			expectedCode:  403,
+		},
 	}
 
 	// Define a new Fiber app.
-	app := fiber.New()
+	app := fiber.New(configs.FiberConfig())
 
 	// Define routes.
 	PrivateRoutes(app)
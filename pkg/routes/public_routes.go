@@ -1,20 +1,36 @@
 package routes
 
 import (
+	"os"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/koddr/tutorial-go-fiber-rest-api/app/controllers"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/middleware"
 )
 
 // PublicRoutes func for describe group of public routes.
 func PublicRoutes(a *fiber.App) {
-	// Create routes group.
-	route := a.Group("/api/v1")
+	// Create routes group, rate limit applies to private routes of the group too.
+	route := a.Group("/api/v1", middleware.RateLimited("api"))
 
 	// Routes for GET method:
-	route.Get("/info", controllers.GetInfo)
-	route.Get("/info/:id", controllers.GetInfoByID)
-	route.Get("/books", controllers.GetBooks)              // get list of all books
-	route.Get("/book/:id", controllers.GetBook)            // get one book by ID
-	route.Get("/token/new", controllers.GetNewAccessToken) // create a new access tokens
-	route.Get("/server", controllers.GetServer)            // get one server by ID
+	route.Get("/profiles", middleware.AccessControlled("profiles", "read"), controllers.GetProfiles)   // get list of all profiles of user
+	route.Get("/profile/:id", middleware.AccessControlled("profiles", "read"), controllers.GetProfile) // get one profile by ID
+	route.Get("/user/oidc/login", controllers.UserOIDCLogin)                                           // redirect to OpenID Connect provider for login
+	route.Get("/user/oidc/callback", middleware.RateLimited("auth"), controllers.UserOIDCCallback)     // finish login and return access & refresh tokens
+
+	// Routes for POST method:
+	route.Post("/normalize", middleware.Identified(), controllers.NormalizeDocument)                 // normalize a JSON document and get its digest
+	route.Post("/user/sign/up", middleware.RateLimited("auth"), controllers.UserSignUp)              // register a new user
+	route.Post("/user/sign/in", middleware.RateLimited("auth"), controllers.UserSignIn)              // auth user and return access & refresh tokens
+	route.Post("/user/sign/in/2fa", middleware.RateLimited("auth"), controllers.UserSignInTwoFactor) // finish sign in with the second factor
+	route.Post("/token/renew", middleware.RateLimited("auth"), controllers.RenewTokens)              // renew access & refresh tokens
+	route.Post("/user/password/forgot", middleware.RateLimited("auth"), controllers.ForgotPassword)  // send link of password reset to email
+	route.Post("/user/password/reset", middleware.RateLimited("auth"), controllers.ResetPassword)    // set a new password by token from link
+	route.Post("/user/email/verify", middleware.RateLimited("auth"), controllers.VerifyEmail)        // verify email by token from link
+
+	// Routes for development mode only:
+	if os.Getenv("STAGE_STATUS") == "dev" {
+		route.Get("/token/new", controllers.GetNewAccessToken) // create a new access tokens without auth
+	}
 }
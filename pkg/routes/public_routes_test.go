@@ -2,11 +2,13 @@ package routes
 
 import (
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 	"github.com/joho/godotenv"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/configs"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -23,22 +25,24 @@ func TestPublicRoutes(t *testing.T) {
 		expectedError bool
 		expectedCode  int
 	}{
+		// Books were public before organizations, now they are read only by
+		// members of organization (see TestPrivateRoutes).
 		{
-			description:   "get book by ID",
+			description:   "get book by ID is not a public route",
 			route:         "/api/v1/book/" + uuid.New().String(),
 			expectedError: false,
 			expectedCode:  404,
 		},
 		{
-			description:   "get book by invalid ID (non UUID)",
-			route:         "/api/v1/book/123456",
+			description:   "get books is not a public route",
+			route:         "/api/v1/books",
 			expectedError: false,
-			expectedCode:  500,
+			expectedCode:  404,
 		},
 	}
 
 	// Define Fiber app.
-	app := fiber.New()
+	app := fiber.New(configs.FiberConfig())
 
 	// Define routes.
 	PublicRoutes(app)
@@ -65,3 +69,42 @@ func TestPublicRoutes(t *testing.T) {
 		assert.Equalf(t, test.expectedCode, resp.StatusCode, test.description)
 	}
 }
+
+func TestNormalizeRoute(t *testing.T) {
+	// Load .env.test file from the root folder
+	if err := godotenv.Load("../../.env.test"); err != nil {
+		panic(err)
+	}
+
+	tests := []struct {
+		description  string
+		body         string
+		expectedCode int
+	}{
+		{
+			description:  "normalize document by inline options without token",
+			body:         `{"document": {"b": 1, "a": "x"}}`,
+			expectedCode: 200,
+		},
+		{
+			description:  "normalize document by profile without token (profiles are not public)",
+			body:         `{"document": {"b": 1}, "profile_id": "` + uuid.New().String() + `"}`,
+			expectedCode: 401,
+		},
+	}
+
+	// Define Fiber app.
+	app := fiber.New(configs.FiberConfig())
+
+	// Define routes.
+	PublicRoutes(app)
+
+	for _, test := range tests {
+		req := httptest.NewRequest("POST", "/api/v1/normalize", strings.NewReader(test.body))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req, -1)
+		assert.NoErrorf(t, err, test.description)
+		assert.Equalf(t, test.expectedCode, resp.StatusCode, test.description)
+	}
+}
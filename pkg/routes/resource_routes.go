@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/koddr/tutorial-go-fiber-rest-api/app/controllers"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/middleware"
+)
+
+// privateResourceRoutes func for describe private routes of records of organization,
+// like books. Records are read only by members of organization, so reads need
+// credentials too, there are no public records.
+func privateResourceRoutes[T any, P controllers.RecordPointer[T]](route fiber.Router, r *controllers.Resource[T, P]) {
+	route.Get(r.PluralPath, middleware.Protected(), middleware.AccessControlled(r.ACL, "read"), middleware.TenantScoped(), r.List)       // get list of all records
+	route.Get(r.Path+"/:id", middleware.Protected(), middleware.AccessControlled(r.ACL, "read"), middleware.TenantScoped(), r.Get)       // get one record by ID
+	route.Post(r.Path, middleware.Protected(), middleware.AccessControlled(r.ACL, "create"), middleware.TenantScoped(), r.Create)        // create a new record
+	route.Put(r.Path, middleware.Protected(), middleware.AccessControlled(r.ACL, "update"), middleware.TenantScoped(), r.Update)         // update one record by ID
+	route.Patch(r.Path+"/:id", middleware.Protected(), middleware.AccessControlled(r.ACL, "update"), middleware.TenantScoped(), r.Patch) // change given fields of one record by ID
+	route.Delete(r.Path, middleware.Protected(), middleware.AccessControlled(r.ACL, "delete"), middleware.TenantScoped(), r.Delete)      // delete one record by ID
+}
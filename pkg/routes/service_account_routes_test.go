@@ -0,0 +1,200 @@
+package routes
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/joho/godotenv"
+	"github.com/koddr/tutorial-go-fiber-rest-api/app/models"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/configs"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/utils"
+	"github.com/koddr/tutorial-go-fiber-rest-api/platform/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// bookData is a body of request, which creates a book.
+const bookData = `{"title": "Service book", "author": "Service", "book_attrs": {"picture": "", "description": "", "rating": 5}}`
+
+// openServiceAccountDB func for connect to database of .env.test file. Tests are
+// skipped, if database is not available or migrations of service accounts are not applied.
+func openServiceAccountDB(t *testing.T) *database.Queries {
+	// Load .env.test file from the root folder.
+	if err := godotenv.Load("../../.env.test"); err != nil {
+		panic(err)
+	}
+
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		t.Skipf("database is not available: %v", err)
+	}
+	if _, err := db.GetServiceAccount("none"); !errors.Is(err, sql.ErrNoRows) {
+		t.Skipf("migrations of service accounts are not applied: %v", err)
+	}
+
+	return db
+}
+
+// newServiceAccountApp func for create app with routes of records and OAuth.
+func newServiceAccountApp() *fiber.App {
+	app := fiber.New(configs.FiberConfig())
+	OAuthRoutes(app)
+	PublicRoutes(app)
+	PrivateRoutes(app)
+
+	return app
+}
+
+// createdBookOwner func for get owner of book from response of creating it.
+func createdBookOwner(t *testing.T, resp *http.Response) string {
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equalf(t, 200, resp.StatusCode, "%s", body)
+
+	created := struct {
+		Book struct {
+			UserID string `json:"user_id"`
+		} `json:"book"`
+	}{}
+	require.NoError(t, json.Unmarshal(body, &created))
+
+	return created.Book.UserID
+}
+
+func TestClientCredentialsRoutes(t *testing.T) {
+	db := openServiceAccountDB(t)
+
+	// Register OAuth client with service account in its personal organization.
+	clientID, secret, hash, err := utils.GenerateNewOAuthClient()
+	require.NoError(t, err)
+	account := &models.ServiceAccount{UserID: uuid.New(), CreatedAt: time.Now(), Identity: models.OAuthClientIdentityPrefix + clientID}
+	client := &models.OAuthClient{
+		ID:         uuid.New(),
+		CreatedAt:  account.CreatedAt,
+		ClientID:   clientID,
+		SecretHash: hash,
+		Name:       "service account test",
+		Scopes:     models.StringList{"books:read", "books:write"},
+		Roles:      models.StringList{"user"},
+		UserID:     &account.UserID,
+	}
+	require.NoError(t, db.CreateOAuthClient(client, account, uuid.Nil, ""))
+	t.Cleanup(func() {
+		db.RevokeOAuthClient(client.ID)
+		db.DeleteUser(account.UserID)
+	})
+
+	app := newServiceAccountApp()
+
+	// Get access token by client credentials.
+	form := url.Values{"grant_type": {"client_credentials"}, "client_id": {clientID}, "client_secret": {secret}}
+	req := httptest.NewRequest("POST", "/oauth/token", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	require.Equal(t, 200, resp.StatusCode)
+
+	token := struct {
+		AccessToken string `json:"access_token"`
+	}{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&token))
+
+	// Client reads and creates books of organization of its service account.
+	req = httptest.NewRequest("GET", "/api/v1/books", nil)
+	req.Header.Set("Authorization", "Bearer "+token.AccessToken)
+	resp, err = app.Test(req, -1)
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode, "list books by client credentials token")
+
+	req = httptest.NewRequest("POST", "/api/v1/book", strings.NewReader(bookData))
+	req.Header.Set("Authorization", "Bearer "+token.AccessToken)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err = app.Test(req, -1)
+	require.NoError(t, err)
+	assert.Equal(t, account.UserID.String(), createdBookOwner(t, resp), "book is owned by service account of client")
+}
+
+func TestClientCertRoutes(t *testing.T) {
+	db := openServiceAccountDB(t)
+
+	// Identity of client certificate is registered in config, which is loaded on first use.
+	identity := "cert-test-" + uuid.New().String()
+	os.Setenv("TLS_CLIENT_PRINCIPALS", identity+":user")
+	defer os.Unsetenv("TLS_CLIENT_PRINCIPALS")
+
+	// Start app with TLS, which verifies client certificates of CA.
+	caKey, caCert := newTestCert(t, &x509.Certificate{Subject: pkix.Name{CommonName: "test CA"}, IsCA: true, BasicConstraintsValid: true, KeyUsage: x509.KeyUsageCertSign}, nil, nil)
+	serverKey, serverCert := newTestCert(t, &x509.Certificate{Subject: pkix.Name{CommonName: "localhost"}, IPAddresses: []net.IP{net.ParseIP("127.0.0.1")}, ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}}, caCert, caKey)
+	clientKey, clientCert := newTestCert(t, &x509.Certificate{Subject: pkix.Name{CommonName: identity}, ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}}, caCert, caKey)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(caCert)
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{serverCert.Raw}, PrivateKey: serverKey}},
+		ClientCAs:    pool,
+		ClientAuth:   tls.VerifyClientCertIfGiven,
+	})
+	require.NoError(t, err)
+
+	app := newServiceAccountApp()
+	go app.Listener(ln)
+	defer app.Shutdown()
+
+	httpClient := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{
+		RootCAs:      pool,
+		Certificates: []tls.Certificate{{Certificate: [][]byte{clientCert.Raw}, PrivateKey: clientKey}},
+	}}}
+	baseURL := "https://" + ln.Addr().String()
+
+	// Client reads and creates books of organization of its service account.
+	resp, err := httpClient.Get(baseURL + "/api/v1/books")
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode, "list books by client certificate")
+
+	account, err := db.GetServiceAccount(models.ClientCertIdentityPrefix + identity)
+	require.NoError(t, err, "service account is created on the first request")
+	t.Cleanup(func() { db.DeleteUser(account.UserID) })
+
+	resp, err = httpClient.Post(baseURL+"/api/v1/book", "application/json", strings.NewReader(bookData))
+	require.NoError(t, err)
+	assert.Equal(t, account.UserID.String(), createdBookOwner(t, resp), "book is owned by service account of certificate")
+}
+
+// newTestCert func for create certificate, which is signed by parent, or self-signed.
+func newTestCert(t *testing.T, template *x509.Certificate, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*ecdsa.PrivateKey, *x509.Certificate) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template.SerialNumber = big.NewInt(time.Now().UnixNano())
+	template.NotBefore = time.Now().Add(-time.Minute)
+	template.NotAfter = time.Now().Add(time.Hour)
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return key, cert
+}
@@ -0,0 +1,15 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/koddr/tutorial-go-fiber-rest-api/app/controllers"
+)
+
+// WellKnownRoutes func for describe group of well-known URIs (RFC 8615).
+func WellKnownRoutes(a *fiber.App) {
+	// Create routes group.
+	route := a.Group("/.well-known")
+
+	// Routes for GET method:
+	route.Get("/jwks.json", controllers.GetJWKS) // get public keys to verify tokens
+}
@@ -0,0 +1,58 @@
+package session
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config struct to describe cookie sessions of browsers. Session expires after
+// IdleTimeout without requests, or after AbsoluteTimeout since sign in anyway.
+type Config struct {
+	IdleTimeout     time.Duration
+	AbsoluteTimeout time.Duration
+	Secure          bool
+	SameSite        string
+	TouchInterval   time.Duration // last request time is written not more often
+}
+
+// ConfigFromEnv func for get config of sessions from .env file:
+//   - SESSION_IDLE_MINUTES: idle timeout (default 30);
+//   - SESSION_ABSOLUTE_HOURS: absolute timeout (default 12);
+//   - SESSION_COOKIE_SECURE: "false" only for development without TLS (default true);
+//   - SESSION_COOKIE_SAMESITE: "Strict" (default) or "Lax".
+func ConfigFromEnv() Config {
+	sameSite := "Strict"
+	if strings.EqualFold(os.Getenv("SESSION_COOKIE_SAMESITE"), "lax") {
+		sameSite = "Lax"
+	}
+
+	return Config{
+		IdleTimeout:     time.Duration(envInt("SESSION_IDLE_MINUTES", 30)) * time.Minute,
+		AbsoluteTimeout: time.Duration(envInt("SESSION_ABSOLUTE_HOURS", 12)) * time.Hour,
+		Secure:          os.Getenv("SESSION_COOKIE_SECURE") != "false",
+		SameSite:        sameSite,
+		TouchInterval:   time.Minute,
+	}
+}
+
+// Expired method for checking, if session with the given times of the last
+// request and absolute expiration is expired at the given time.
+func (cfg Config) Expired(lastSeenAt, expiresAt, now time.Time) bool {
+	return !now.Before(expiresAt) || now.Sub(lastSeenAt) >= cfg.IdleTimeout
+}
+
+// NeedsTouch method for checking, if time of the last request must be updated.
+func (cfg Config) NeedsTouch(lastSeenAt, now time.Time) bool {
+	return now.Sub(lastSeenAt) >= cfg.TouchInterval
+}
+
+func envInt(key string, defaultValue int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || value <= 0 {
+		return defaultValue
+	}
+
+	return value
+}
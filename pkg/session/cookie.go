@@ -0,0 +1,88 @@
+package session
+
+import (
+	"crypto/subtle"
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// CSRFHeader is a header of request, which repeats value of CSRF cookie.
+const CSRFHeader = "X-CSRF-Token"
+
+// ErrCSRF is returned for unsafe requests without valid CSRF token.
+var ErrCSRF = errors.New("permission denied, CSRF token is missing or not valid")
+
+// CookieName method for get name of session cookie. Secure cookies have the
+// "__Host-" prefix, so they are not set by other hosts or over HTTP.
+func (cfg Config) CookieName() string {
+	if cfg.Secure {
+		return "__Host-session"
+	}
+
+	return "session"
+}
+
+// CSRFCookieName method for get name of CSRF cookie.
+func (cfg Config) CSRFCookieName() string {
+	if cfg.Secure {
+		return "__Host-csrf"
+	}
+
+	return "csrf"
+}
+
+// SetCookies method for set session cookie (HttpOnly) and CSRF cookie, which
+// is sent back by client in the X-CSRF-Token header.
+func (cfg Config) SetCookies(c *fiber.Ctx, token, csrfToken string, expires time.Time) {
+	c.Cookie(cfg.cookie(cfg.CookieName(), token, expires, true))
+	c.Cookie(cfg.cookie(cfg.CSRFCookieName(), csrfToken, expires, false))
+}
+
+// ClearCookies method for delete session and CSRF cookies in browser.
+func (cfg Config) ClearCookies(c *fiber.Ctx) {
+	c.Cookie(cfg.cookie(cfg.CookieName(), "", time.Unix(0, 0), true))
+	c.Cookie(cfg.cookie(cfg.CSRFCookieName(), "", time.Unix(0, 0), false))
+}
+
+// Token method for get session token of request, or empty string.
+func (cfg Config) Token(c *fiber.Ctx) string {
+	return c.Cookies(cfg.CookieName())
+}
+
+// CSRFToken method for get double-submitted CSRF token of request. Unsafe
+// requests must send the same token in the X-CSRF-Token header and in CSRF cookie.
+// Site of attacker can send cookies with request, but can't read or set them.
+func (cfg Config) CSRFToken(c *fiber.Ctx) (string, error) {
+	header := c.Get(CSRFHeader)
+	cookie := c.Cookies(cfg.CSRFCookieName())
+	if header == "" || subtle.ConstantTimeCompare([]byte(header), []byte(cookie)) != 1 {
+		return "", ErrCSRF
+	}
+
+	return header, nil
+}
+
+// IsSafeMethod func for checking, if method of request doesn't change state,
+// so it doesn't need CSRF token.
+func IsSafeMethod(method string) bool {
+	switch method {
+	case fiber.MethodGet, fiber.MethodHead, fiber.MethodOptions, fiber.MethodTrace:
+		return true
+	}
+
+	return false
+}
+
+func (cfg Config) cookie(name, value string, expires time.Time, httpOnly bool) *fiber.Cookie {
+	return &fiber.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     "/",
+		Expires:  expires,
+		Secure:   cfg.Secure,
+		HTTPOnly: httpOnly,
+		SameSite: cfg.SameSite,
+	}
+}
@@ -0,0 +1,107 @@
+package session
+
+import (
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setenv(t *testing.T, key, value string) {
+	old, ok := os.LookupEnv(key)
+	os.Setenv(key, value)
+	t.Cleanup(func() {
+		if ok {
+			os.Setenv(key, old)
+		} else {
+			os.Unsetenv(key)
+		}
+	})
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	setenv(t, "SESSION_IDLE_MINUTES", "")
+	setenv(t, "SESSION_ABSOLUTE_HOURS", "")
+	setenv(t, "SESSION_COOKIE_SECURE", "")
+	setenv(t, "SESSION_COOKIE_SAMESITE", "")
+
+	cfg := ConfigFromEnv()
+	assert.Equal(t, 30*time.Minute, cfg.IdleTimeout)
+	assert.Equal(t, 12*time.Hour, cfg.AbsoluteTimeout)
+	assert.True(t, cfg.Secure)
+	assert.Equal(t, "Strict", cfg.SameSite)
+	assert.Equal(t, "__Host-session", cfg.CookieName())
+
+	setenv(t, "SESSION_IDLE_MINUTES", "5")
+	setenv(t, "SESSION_COOKIE_SECURE", "false")
+	setenv(t, "SESSION_COOKIE_SAMESITE", "lax")
+
+	cfg = ConfigFromEnv()
+	assert.Equal(t, 5*time.Minute, cfg.IdleTimeout)
+	assert.False(t, cfg.Secure)
+	assert.Equal(t, "Lax", cfg.SameSite)
+	assert.Equal(t, "session", cfg.CookieName())
+}
+
+func TestExpired(t *testing.T) {
+	cfg := Config{IdleTimeout: 30 * time.Minute}
+	now := time.Now()
+
+	assert.False(t, cfg.Expired(now.Add(-10*time.Minute), now.Add(time.Hour), now))
+	assert.True(t, cfg.Expired(now.Add(-30*time.Minute), now.Add(time.Hour), now), "idle timeout")
+	assert.True(t, cfg.Expired(now, now, now), "absolute timeout")
+}
+
+func TestCookiesAndCSRF(t *testing.T) {
+	cfg := Config{Secure: true, SameSite: "Strict"}
+	app := fiber.New()
+	app.Post("/sign/in", func(c *fiber.Ctx) error {
+		cfg.SetCookies(c, "session-token", "csrf-token", time.Now().Add(time.Hour))
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+	app.Post("/action", func(c *fiber.Ctx) error {
+		token, err := cfg.CSRFToken(c)
+		if err != nil {
+			return c.SendStatus(fiber.StatusForbidden)
+		}
+		return c.SendString(cfg.Token(c) + " " + token)
+	})
+
+	// Session cookie is not readable by scripts, CSRF cookie is.
+	resp, err := app.Test(httptest.NewRequest("POST", "/sign/in", nil))
+	require.NoError(t, err)
+	cookies := map[string]string{}
+	for _, cookie := range resp.Cookies() {
+		cookies[cookie.Name] = cookie.Value
+		assert.True(t, cookie.Secure, cookie.Name)
+		assert.Equal(t, cookie.Name == "__Host-session", cookie.HttpOnly, cookie.Name)
+	}
+	assert.Equal(t, map[string]string{"__Host-session": "session-token", "__Host-csrf": "csrf-token"}, cookies)
+
+	tests := []struct {
+		description  string
+		header       string
+		expectedCode int
+	}{
+		{"header repeats cookie", "csrf-token", fiber.StatusOK},
+		{"header without cookie value", "other-token", fiber.StatusForbidden},
+		{"no header", "", fiber.StatusForbidden},
+	}
+	for _, test := range tests {
+		req := httptest.NewRequest("POST", "/action", nil)
+		req.Header.Set("Cookie", "__Host-session=session-token; __Host-csrf=csrf-token")
+		if test.header != "" {
+			req.Header.Set(CSRFHeader, test.header)
+		}
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, test.expectedCode, resp.StatusCode, test.description)
+	}
+
+	assert.True(t, IsSafeMethod(fiber.MethodGet))
+	assert.False(t, IsSafeMethod(fiber.MethodDelete))
+}
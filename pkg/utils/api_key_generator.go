@@ -0,0 +1,29 @@
+package utils
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"strings"
+)
+
+// APIKeyPrefix is a prefix of every API key, to tell them apart from JWT.
+const APIKeyPrefix = "ak_"
+
+// GenerateNewAPIKey func for generate a new API key. It returns the key for
+// client, its first characters to recognize it later and its hash for storing in database.
+func GenerateNewAPIKey() (string, string, string, error) {
+	// Create a new random key.
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		// Return error, it random generation failed.
+		return "", "", "", err
+	}
+	key := APIKeyPrefix + base64.RawURLEncoding.EncodeToString(b)
+
+	return key, key[:len(APIKeyPrefix)+8], HashToken(key), nil
+}
+
+// IsAPIKey func for checking, if the given credential is an API key.
+func IsAPIKey(credential string) bool {
+	return strings.HasPrefix(credential, APIKeyPrefix)
+}
@@ -0,0 +1,53 @@
+package utils
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"math/big"
+	"strings"
+)
+
+// userCodeAlphabet has no vowels (codes don't make words) and no
+// characters, which are easily confused, like "0" and "O" (RFC 8628, section 6.1).
+const userCodeAlphabet = "BCDFGHJKLMNPQRSTVWXZ"
+
+// GenerateNewDeviceCode func for generate codes of device authorization request.
+// It returns device code for device, its hash for storing in database and
+// user code in "XXXX-XXXX" format, which user types on verification page.
+func GenerateNewDeviceCode() (string, string, string, error) {
+	// Create a new random device code.
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		// Return error, it random generation failed.
+		return "", "", "", err
+	}
+	deviceCode := base64.RawURLEncoding.EncodeToString(b)
+
+	// Create a new random user code.
+	userCode := make([]byte, 8)
+	for i := range userCode {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(userCodeAlphabet))))
+		if err != nil {
+			return "", "", "", err
+		}
+		userCode[i] = userCodeAlphabet[n.Int64()]
+	}
+
+	return deviceCode, HashToken(deviceCode), string(userCode[:4]) + "-" + string(userCode[4:]), nil
+}
+
+// NormalizeUserCode func for make user code typed by user comparable with the
+// stored one: case and separators are ignored.
+func NormalizeUserCode(code string) string {
+	letters := []rune{}
+	for _, r := range strings.ToUpper(code) {
+		if strings.ContainsRune(userCodeAlphabet, r) {
+			letters = append(letters, r)
+		}
+	}
+	if len(letters) != 8 {
+		return string(letters)
+	}
+
+	return string(letters[:4]) + "-" + string(letters[4:])
+}
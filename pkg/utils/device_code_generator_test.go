@@ -0,0 +1,22 @@
+package utils
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeviceCode(t *testing.T) {
+	deviceCode, hash, userCode, err := GenerateNewDeviceCode()
+	require.NoError(t, err)
+	assert.Equal(t, HashToken(deviceCode), hash)
+	assert.Regexp(t, regexp.MustCompile(`^[BCDFGHJKLMNPQRSTVWXZ]{4}-[BCDFGHJKLMNPQRSTVWXZ]{4}$`), userCode)
+
+	// User may type code in lower case, without or with other separators.
+	assert.Equal(t, "WDJB-MJHT", NormalizeUserCode("wdjbmjht"))
+	assert.Equal(t, "WDJB-MJHT", NormalizeUserCode(" wdjb mjht "))
+	assert.Equal(t, "WDJB-MJHT", NormalizeUserCode("WDJB-MJHT"))
+	assert.Equal(t, "WDJB", NormalizeUserCode("WDJB"))
+}
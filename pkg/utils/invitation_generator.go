@@ -0,0 +1,79 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Invitation struct to describe invitation to organization, which is kept
+// in a signed link, so no server storage is needed. Only user with the
+// verified email can accept it.
+type Invitation struct {
+	OrganizationID uuid.UUID `json:"o"`
+	Email          string    `json:"e"`
+	Role           string    `json:"r"`
+	InvitedBy      uuid.UUID `json:"i"`
+	Expires        int64     `json:"x"`
+}
+
+// InvitationSecret func for get secret to sign invitations from .env file
+// (INVITATION_SECRET). It's required, because invitations must be accepted
+// by all instances of app and after restart.
+func InvitationSecret() ([]byte, error) {
+	secret := os.Getenv("INVITATION_SECRET")
+	if secret == "" {
+		return nil, errors.New("INVITATION_SECRET is not set")
+	}
+
+	return []byte(secret), nil
+}
+
+// GenerateNewInvitation func for sign invitation by the given secret.
+func GenerateNewInvitation(secret []byte, i *Invitation) (string, error) {
+	data, err := json.Marshal(i)
+	if err != nil {
+		return "", err
+	}
+
+	payload := base64.RawURLEncoding.EncodeToString(data)
+
+	return payload + "." + signInvitation(secret, payload), nil
+}
+
+// ParseInvitation func for verify signature and expiration of invitation.
+func ParseInvitation(secret []byte, token string) (*Invitation, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 2 || !hmac.Equal([]byte(parts[1]), []byte(signInvitation(secret, parts[0]))) {
+		return nil, errors.New("invitation is not valid")
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, err
+	}
+
+	i := &Invitation{}
+	if err := json.Unmarshal(data, i); err != nil {
+		return nil, err
+	}
+
+	if time.Now().Unix() > i.Expires {
+		return nil, errors.New("invitation is expired")
+	}
+
+	return i, nil
+}
+
+func signInvitation(secret []byte, payload string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte("invitation." + payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,66 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInvitation(t *testing.T) {
+	secret := []byte("secret")
+	invitation := &Invitation{
+		OrganizationID: uuid.New(),
+		Email:          "john@example.com",
+		Role:           "member",
+		InvitedBy:      uuid.New(),
+		Expires:        time.Now().Add(time.Hour).Unix(),
+	}
+
+	token, err := GenerateNewInvitation(secret, invitation)
+	require.NoError(t, err)
+
+	parsed, err := ParseInvitation(secret, token)
+	require.NoError(t, err)
+	assert.Equal(t, invitation, parsed)
+
+	// Invitation is signed by the other secret.
+	_, err = ParseInvitation([]byte("other"), token)
+	assert.Error(t, err)
+
+	// Role of invitation is changed.
+	parts := strings.Split(token, ".")
+	changed, err := GenerateNewInvitation([]byte("other"), &Invitation{
+		OrganizationID: invitation.OrganizationID,
+		Email:          invitation.Email,
+		Role:           "owner",
+		Expires:        invitation.Expires,
+	})
+	require.NoError(t, err)
+	_, err = ParseInvitation(secret, strings.Split(changed, ".")[0]+"."+parts[1])
+	assert.Error(t, err)
+
+	// Invitation is expired.
+	invitation.Expires = time.Now().Add(-time.Minute).Unix()
+	token, err = GenerateNewInvitation(secret, invitation)
+	require.NoError(t, err)
+	_, err = ParseInvitation(secret, token)
+	assert.Error(t, err)
+
+	_, err = ParseInvitation(secret, "not-a-token")
+	assert.Error(t, err)
+}
+
+func TestInvitationSecret(t *testing.T) {
+	t.Setenv("INVITATION_SECRET", "")
+	_, err := InvitationSecret()
+	assert.Error(t, err, "app doesn't start without secret")
+
+	t.Setenv("INVITATION_SECRET", "secret")
+	secret, err := InvitationSecret()
+	require.NoError(t, err)
+	assert.Equal(t, []byte("secret"), secret)
+}
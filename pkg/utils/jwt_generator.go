@@ -1,32 +1,56 @@
 package utils
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"os"
 	"strconv"
 	"time"
 
 	"github.com/golang-jwt/jwt"
+	"github.com/google/uuid"
 )
 
-// GenerateNewAccessToken func for generate a new Access token.
-func GenerateNewAccessToken() (string, error) {
-	// Set secret key from .env file.
-	secret := os.Getenv("JWT_SECRET_KEY")
+// GenerateNewAccessToken func for generate a new Access token for the given principal.
+func GenerateNewAccessToken(p *Principal) (string, error) {
+	// Get keys for signing token.
+	keys, err := JWTKeySet()
+	if err != nil {
+		return "", err
+	}
 
-	// Set expires minutes count for secret key from .env file.
-	minutesCount, _ := strconv.Atoi(os.Getenv("JWT_SECRET_KEY_EXPIRE_MINUTES_COUNT"))
+	// Get now time.
+	now := time.Now()
 
 	// Create a new claims.
 	claims := jwt.MapClaims{}
 
 	// Set public claims:
-	claims["exp"] = time.Now().Add(time.Minute * time.Duration(minutesCount)).Unix()
+	claims["sub"] = p.Subject
+	claims["roles"] = p.Roles
+	claims["scopes"] = p.Scopes
+	claims["jti"] = uuid.New().String()
+	claims["iat"] = now.Unix()
+	claims["iss"] = os.Getenv("JWT_ISSUER")
+	claims["exp"] = AccessTokenExpiration(now).Unix()
+
+	// Set claim of OAuth client, if token is issued to it.
+	if p.ClientID != "" {
+		claims["client_id"] = p.ClientID
+	}
 
 	// Create a new JWT access token with claims.
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token := jwt.NewWithClaims(keys.Method, claims)
+
+	// Set ID of the signing key, so it can be rotated.
+	if keys.SigningKID != "" {
+		token.Header["kid"] = keys.SigningKID
+	}
 
 	// Generate token.
-	t, err := token.SignedString([]byte(secret))
+	t, err := token.SignedString(keys.SigningKey)
 	if err != nil {
 		// Return error, it JWT token generation failed.
 		return "", err
@@ -34,3 +58,39 @@ func GenerateNewAccessToken() (string, error) {
 
 	return t, nil
 }
+
+// GenerateNewRefreshToken func for generate a new opaque Refresh token.
+// It returns the token for client and its hash for storing in database.
+func GenerateNewRefreshToken() (string, string, error) {
+	// Create a new random token.
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		// Return error, it random generation failed.
+		return "", "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+
+	return token, HashToken(token), nil
+}
+
+// HashToken func for make a hash of opaque token for storing in database.
+func HashToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+// AccessTokenExpiration func for get expiration time of Access token issued at the given time.
+func AccessTokenExpiration(issuedAt time.Time) time.Time {
+	// Set expires minutes count for secret key from .env file.
+	minutesCount, _ := strconv.Atoi(os.Getenv("JWT_SECRET_KEY_EXPIRE_MINUTES_COUNT"))
+
+	return issuedAt.Add(time.Minute * time.Duration(minutesCount))
+}
+
+// RefreshTokenExpiration func for get expiration time of a new Refresh token.
+func RefreshTokenExpiration() time.Time {
+	// Set expires hours count for refresh key from .env file.
+	hoursCount, _ := strconv.Atoi(os.Getenv("JWT_REFRESH_KEY_EXPIRE_HOURS_COUNT"))
+
+	return time.Now().Add(time.Hour * time.Duration(hoursCount))
+}
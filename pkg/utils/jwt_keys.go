@@ -0,0 +1,218 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/golang-jwt/jwt"
+)
+
+// KeySet struct to describe keys for signing and verifying JWT.
+type KeySet struct {
+	Method     jwt.SigningMethod
+	SigningKID string
+	SigningKey interface{}
+	VerifyKeys map[string]interface{} // by key ID, includes the signing key
+}
+
+var (
+	keySet     *KeySet
+	keySetErr  error
+	keySetOnce sync.Once
+)
+
+// JWTKeySet func for get keys for JWT, loaded once from .env file:
+//   - JWT_SIGNING_METHOD: HS256 (default), RS256, ES256 or EdDSA;
+//   - JWT_PRIVATE_KEYS: comma-separated "kid:path" of PEM private keys, first one signs tokens;
+//   - JWT_PUBLIC_KEYS: comma-separated "kid:path" of PEM public keys of retired keys, verify only.
+//
+// HS256 uses JWT_SECRET_KEY like before.
+func JWTKeySet() (*KeySet, error) {
+	keySetOnce.Do(func() {
+		keySet, keySetErr = loadKeySet()
+	})
+
+	return keySet, keySetErr
+}
+
+func loadKeySet() (*KeySet, error) {
+	method := os.Getenv("JWT_SIGNING_METHOD")
+	if method == "" || method == jwt.SigningMethodHS256.Alg() {
+		secret := []byte(os.Getenv("JWT_SECRET_KEY"))
+		return &KeySet{
+			Method:     jwt.SigningMethodHS256,
+			SigningKey: secret,
+			VerifyKeys: map[string]interface{}{"": secret},
+		}, nil
+	}
+
+	keys := &KeySet{Method: jwt.GetSigningMethod(method), VerifyKeys: map[string]interface{}{}}
+	if keys.Method == nil {
+		return nil, fmt.Errorf("unsupported JWT signing method %q", method)
+	}
+
+	// Load private keys, first of them is used for signing.
+	for i, entry := range splitKeyEntries(os.Getenv("JWT_PRIVATE_KEYS")) {
+		key, err := readPEMKey(entry[1], true)
+		if err != nil {
+			return nil, fmt.Errorf("private key %q is not loaded, %w", entry[0], err)
+		}
+		if i == 0 {
+			keys.SigningKID, keys.SigningKey = entry[0], key
+		}
+		keys.VerifyKeys[entry[0]] = key.(crypto.Signer).Public()
+	}
+	if keys.SigningKey == nil {
+		return nil, errors.New("JWT_PRIVATE_KEYS must have at least one key")
+	}
+
+	// Load public keys of retired private keys.
+	for _, entry := range splitKeyEntries(os.Getenv("JWT_PUBLIC_KEYS")) {
+		key, err := readPEMKey(entry[1], false)
+		if err != nil {
+			return nil, fmt.Errorf("public key %q is not loaded, %w", entry[0], err)
+		}
+		keys.VerifyKeys[entry[0]] = key
+	}
+
+	return keys, nil
+}
+
+// VerifyKey method for get key to verify token with the given header.
+func (k *KeySet) VerifyKey(token *jwt.Token) (interface{}, error) {
+	// Checking, if token is signed by the expected method.
+	if token.Method.Alg() != k.Method.Alg() {
+		return nil, errors.New("unexpected signing method of token")
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	key, ok := k.VerifyKeys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key ID %q of token", kid)
+	}
+
+	return key, nil
+}
+
+// PublicJWKS method for get public keys as a JSON Web Key Set (RFC 7517).
+// Symmetric keys are never published.
+func (k *KeySet) PublicJWKS() map[string]interface{} {
+	keys := []map[string]interface{}{}
+
+	for kid, key := range k.VerifyKeys {
+		jwk := map[string]interface{}{"kid": kid, "use": "sig", "alg": k.Method.Alg()}
+
+		switch public := key.(type) {
+		case *rsa.PublicKey:
+			jwk["kty"] = "RSA"
+			jwk["n"] = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+			jwk["e"] = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+		case *ecdsa.PublicKey:
+			size := (public.Curve.Params().BitSize + 7) / 8
+			jwk["kty"] = "EC"
+			jwk["crv"] = public.Curve.Params().Name
+			jwk["x"] = base64.RawURLEncoding.EncodeToString(public.X.FillBytes(make([]byte, size)))
+			jwk["y"] = base64.RawURLEncoding.EncodeToString(public.Y.FillBytes(make([]byte, size)))
+		case ed25519.PublicKey:
+			jwk["kty"] = "OKP"
+			jwk["crv"] = "Ed25519"
+			jwk["x"] = base64.RawURLEncoding.EncodeToString(public)
+		default:
+			continue
+		}
+
+		keys = append(keys, jwk)
+	}
+
+	return map[string]interface{}{"keys": keys}
+}
+
+func splitKeyEntries(s string) [][2]string {
+	entries := [][2]string{}
+	for _, entry := range strings.Split(s, ",") {
+		parts := strings.SplitN(strings.TrimSpace(entry), ":", 2)
+		if len(parts) == 2 && parts[0] != "" {
+			entries = append(entries, [2]string{parts[0], parts[1]})
+		}
+	}
+
+	return entries
+}
+
+func readPEMKey(path string, private bool) (interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("file has no PEM block")
+	}
+
+	switch {
+	case !private:
+		return x509.ParsePKIXPublicKey(block.Bytes)
+	case block.Type == "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case block.Type == "EC PRIVATE KEY":
+		return x509.ParseECPrivateKey(block.Bytes)
+	default:
+		return x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+}
+
+// SigningMethodEdDSA implements the EdDSA (Ed25519) signing method,
+// which is not a part of github.com/golang-jwt/jwt v3.
+type SigningMethodEdDSA struct{}
+
+func init() {
+	jwt.RegisterSigningMethod("EdDSA", func() jwt.SigningMethod {
+		return &SigningMethodEdDSA{}
+	})
+}
+
+// Alg method for get name of signing method.
+func (m *SigningMethodEdDSA) Alg() string {
+	return "EdDSA"
+}
+
+// Sign method for sign string by ed25519.PrivateKey.
+func (m *SigningMethodEdDSA) Sign(signingString string, key interface{}) (string, error) {
+	private, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return "", jwt.ErrInvalidKeyType
+	}
+
+	return jwt.EncodeSegment(ed25519.Sign(private, []byte(signingString))), nil
+}
+
+// Verify method for verify signature by ed25519.PublicKey.
+func (m *SigningMethodEdDSA) Verify(signingString, signature string, key interface{}) error {
+	public, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return jwt.ErrInvalidKeyType
+	}
+
+	sig, err := jwt.DecodeSegment(signature)
+	if err != nil {
+		return err
+	}
+
+	if !ed25519.Verify(public, []byte(signingString), sig) {
+		return jwt.ErrSignatureInvalid
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package utils
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writePEM(t *testing.T, name, blockType string, der []byte) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600))
+	return path
+}
+
+func setenv(t *testing.T, key, value string) {
+	old, ok := os.LookupEnv(key)
+	os.Setenv(key, value)
+	t.Cleanup(func() {
+		if ok {
+			os.Setenv(key, old)
+		} else {
+			os.Unsetenv(key)
+		}
+	})
+}
+
+func TestKeySetRotation(t *testing.T) {
+	tests := []struct {
+		method string
+		newKey func() (interface{}, interface{})
+	}{
+		{"ES256", func() (interface{}, interface{}) {
+			key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			return key, &key.PublicKey
+		}},
+		{"EdDSA", func() (interface{}, interface{}) {
+			public, key, _ := ed25519.GenerateKey(rand.Reader)
+			return key, public
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.method, func(t *testing.T) {
+			// Retired key is published, but not used for signing anymore.
+			oldKey, oldPublic := test.newKey()
+			newKey, _ := test.newKey()
+			oldDER, err := x509.MarshalPKIXPublicKey(oldPublic)
+			require.NoError(t, err)
+			newDER, err := x509.MarshalPKCS8PrivateKey(newKey)
+			require.NoError(t, err)
+
+			setenv(t, "JWT_SIGNING_METHOD", test.method)
+			setenv(t, "JWT_PRIVATE_KEYS", "new:"+writePEM(t, "new.pem", "PRIVATE KEY", newDER))
+			setenv(t, "JWT_PUBLIC_KEYS", "old:"+writePEM(t, "old.pem", "PUBLIC KEY", oldDER))
+
+			keys, err := loadKeySet()
+			require.NoError(t, err)
+			assert.Equal(t, "new", keys.SigningKID)
+			assert.Len(t, keys.PublicJWKS()["keys"], 2)
+
+			sign := func(kid string, key interface{}) string {
+				token := jwt.NewWithClaims(keys.Method, jwt.MapClaims{"sub": "test"})
+				token.Header["kid"] = kid
+				s, err := token.SignedString(key)
+				require.NoError(t, err)
+				return s
+			}
+
+			// Tokens of both keys are valid, token of unknown key is not.
+			for _, s := range []string{sign("new", keys.SigningKey), sign("old", oldKey)} {
+				token, err := jwt.Parse(s, keys.VerifyKey)
+				assert.NoError(t, err)
+				assert.True(t, token.Valid)
+			}
+			_, err = jwt.Parse(sign("other", oldKey), keys.VerifyKey)
+			assert.Error(t, err)
+			_, err = jwt.Parse(sign("new", oldKey), keys.VerifyKey)
+			assert.Error(t, err)
+
+			// HS256 tokens are not accepted with asymmetric keys.
+			hs, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{}).SignedString([]byte("secret"))
+			_, err = jwt.Parse(hs, keys.VerifyKey)
+			assert.Error(t, err)
+		})
+	}
+}
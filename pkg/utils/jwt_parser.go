@@ -1,37 +1,90 @@
 package utils
 
 import (
+	"errors"
 	"os"
 	"strings"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/golang-jwt/jwt"
+	"github.com/google/uuid"
 )
 
-// TokenMetadata struct to describe metadata in JWT.
-type TokenMetadata struct {
-	Expires int64
+// ExtractPrincipal func to extract principal from JWT of the Authorization header.
+func ExtractPrincipal(c *fiber.Ctx) (*Principal, error) {
+	token, err := verifyToken(c)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewPrincipal(token)
 }
 
-// ExtractTokenMetadata func to extract metadata from JWT.
-func ExtractTokenMetadata(c *fiber.Ctx) (*TokenMetadata, error) {
-	token, err := verifyToken(c)
+// ParsePrincipal func to verify the given JWT and make principal from its claims.
+func ParsePrincipal(tokenString string) (*Principal, error) {
+	token, err := jwt.Parse(tokenString, jwtKeyFunc)
 	if err != nil {
 		return nil, err
 	}
 
+	return NewPrincipal(token)
+}
+
+// NewPrincipal func to make principal from claims of a verified JWT.
+func NewPrincipal(token *jwt.Token) (*Principal, error) {
 	// Setting and checking token and credentials.
 	claims, ok := token.Claims.(jwt.MapClaims)
-	if ok && token.Valid {
-		// Expires time.
-		expires := int64(claims["exp"].(float64))
+	if !ok || !token.Valid {
+		return nil, errors.New("token is not valid")
+	}
+
+	// Checking, if token was issued by this server.
+	if issuer := os.Getenv("JWT_ISSUER"); issuer != "" && !claims.VerifyIssuer(issuer, true) {
+		return nil, errors.New("token issuer is not valid")
+	}
+
+	// Intermediate tokens of sign in are not access tokens.
+	if stringClaim(claims, "token_use") != "" {
+		return nil, errors.New("token is not an access token")
+	}
 
-		return &TokenMetadata{
-			Expires: expires,
-		}, nil
+	principal := &Principal{
+		Subject:  stringClaim(claims, "sub"),
+		ClientID: stringClaim(claims, "client_id"),
+		Roles:    stringsClaim(claims, "roles"),
+		Scopes:   stringsClaim(claims, "scopes"),
+		TokenID:  stringClaim(claims, "jti"),
+		IssuedAt: int64Claim(claims, "iat"),
+		Expires:  int64Claim(claims, "exp"),
 	}
 
-	return nil, err
+	// User ID is known only for users, not for other subjects.
+	if userID, err := uuid.Parse(principal.Subject); err == nil {
+		principal.UserID = userID
+	}
+
+	return principal, nil
+}
+
+func stringClaim(claims jwt.MapClaims, name string) string {
+	value, _ := claims[name].(string)
+	return value
+}
+
+func stringsClaim(claims jwt.MapClaims, name string) []string {
+	values, _ := claims[name].([]interface{})
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		if s, ok := value.(string); ok {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+func int64Claim(claims jwt.MapClaims, name string) int64 {
+	value, _ := claims[name].(float64)
+	return int64(value)
 }
 
 func extractToken(c *fiber.Ctx) string {
@@ -58,5 +111,11 @@ func verifyToken(c *fiber.Ctx) (*jwt.Token, error) {
 }
 
 func jwtKeyFunc(token *jwt.Token) (interface{}, error) {
-	return []byte(os.Getenv("JWT_SECRET_KEY")), nil
+	// Get keys for verifying token.
+	keys, err := JWTKeySet()
+	if err != nil {
+		return nil, err
+	}
+
+	return keys.VerifyKey(token)
 }
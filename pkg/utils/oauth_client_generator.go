@@ -0,0 +1,32 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+)
+
+// OAuthClientIDPrefix is a prefix of every ID of OAuth client, it's a subject
+// of access tokens issued to client, so it never looks like ID of user.
+const OAuthClientIDPrefix = "cl_"
+
+// GenerateNewOAuthClient func for generate credentials of a new OAuth client.
+// It returns ID of client, its secret and hash of secret for storing in database.
+func GenerateNewOAuthClient() (string, string, string, error) {
+	// Create a new random ID and secret.
+	b := make([]byte, 48)
+	if _, err := rand.Read(b); err != nil {
+		// Return error, it random generation failed.
+		return "", "", "", err
+	}
+	clientID := OAuthClientIDPrefix + base64.RawURLEncoding.EncodeToString(b[:16])
+	secret := base64.RawURLEncoding.EncodeToString(b[16:])
+
+	return clientID, secret, HashToken(secret), nil
+}
+
+// CompareSecretHash func for checking, if secret matches the stored hash
+// in constant time.
+func CompareSecretHash(hash, secret string) bool {
+	return subtle.ConstantTimeCompare([]byte(hash), []byte(HashToken(secret))) == 1
+}
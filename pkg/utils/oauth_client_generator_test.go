@@ -0,0 +1,35 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOAuthClientToken(t *testing.T) {
+	setenv(t, "JWT_SECRET_KEY", "secret")
+	setenv(t, "JWT_SECRET_KEY_EXPIRE_MINUTES_COUNT", "15")
+
+	clientID, secret, hash, err := GenerateNewOAuthClient()
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(clientID, OAuthClientIDPrefix))
+	assert.True(t, CompareSecretHash(hash, secret))
+	assert.False(t, CompareSecretHash(hash, secret+"x"))
+
+	accessToken, err := GenerateNewAccessToken(&Principal{
+		Subject:  clientID,
+		ClientID: clientID,
+		Roles:    []string{"user"},
+		Scopes:   []string{"books:read"},
+	})
+	require.NoError(t, err)
+
+	principal, err := ParsePrincipal(accessToken)
+	require.NoError(t, err)
+	assert.Equal(t, clientID, principal.ClientID)
+	assert.Equal(t, []string{"books:read"}, principal.Scopes)
+	assert.True(t, principal.AllowsScope("books", "read"))
+	assert.False(t, principal.AllowsScope("books", "create"))
+}
@@ -0,0 +1,70 @@
+package utils
+
+import (
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// GeneratePassword func for a make hash from password by bcrypt.
+func GeneratePassword(p string) (string, error) {
+	// Make hash from the password with the default cost.
+	hash, err := bcrypt.GenerateFromPassword([]byte(p), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+
+	return string(hash), nil
+}
+
+// ComparePasswords func for a comparing password with its hash.
+// Hashes made by bcrypt and argon2id (PHC string format) are supported.
+func ComparePasswords(hashedPassword, inputPassword string) bool {
+	// Checking, if password was hashed by argon2id.
+	if strings.HasPrefix(hashedPassword, "$argon2id$") {
+		return compareArgon2id(hashedPassword, inputPassword)
+	}
+
+	// Compare password with bcrypt hash.
+	err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(inputPassword))
+
+	return err == nil
+}
+
+// compareArgon2id func for a comparing password with a hash like
+// $argon2id$v=19$m=65536,t=3,p=2$<salt>$<key>.
+func compareArgon2id(hashedPassword, inputPassword string) bool {
+	parts := strings.Split(hashedPassword, "$")
+	if len(parts) != 6 {
+		return false
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return false
+	}
+
+	var memory, time uint32
+	var threads uint8
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &memory, &time, &threads); err != nil {
+		return false
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return false
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return false
+	}
+
+	// Make key from the input password with the same parameters.
+	inputKey := argon2.IDKey([]byte(inputPassword), salt, time, memory, threads, uint32(len(key)))
+
+	return subtle.ConstantTimeCompare(key, inputKey) == 1
+}
@@ -0,0 +1,96 @@
+package utils
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/repository"
+)
+
+// principalContextKey is a key of fiber.Ctx locals for the current Principal.
+const principalContextKey = "principal"
+
+// Principal struct to describe the authenticated caller of request.
+type Principal struct {
+	UserID   uuid.UUID // parsed from Subject, uuid.Nil for non-user subjects
+	Subject  string
+	ClientID string // OAuth client, which the token was issued to
+	Roles    []string
+	Scopes   []string
+	TokenID  string
+	IssuedAt int64
+	Expires  int64
+}
+
+// HasRole method for checking, if principal has the given role.
+func (p *Principal) HasRole(role string) bool {
+	for _, r := range p.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// HasScope method for checking, if principal has the given scope.
+func (p *Principal) HasScope(scope string) bool {
+	for _, s := range p.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsScope method for checking, if principal is allowed to do the action
+// with the resource by its scopes, like "books:read" or "books:write".
+// Principals without scopes (users with JWT) are limited only by their roles.
+func (p *Principal) AllowsScope(resource, action string) bool {
+	if len(p.Scopes) == 0 {
+		return true
+	}
+
+	if action != "read" {
+		action = "write"
+	}
+
+	return p.HasScope(resource + ":" + action)
+}
+
+// IsAdmin method for checking, if principal has the admin role.
+func (p *Principal) IsAdmin() bool {
+	return p.HasRole(repository.AdminRoleName)
+}
+
+// SetPrincipal func for store principal of the current request.
+func SetPrincipal(c *fiber.Ctx, p *Principal) {
+	c.Locals(principalContextKey, p)
+}
+
+// GetPrincipal func for get principal of the current request,
+// which was stored by the auth middleware.
+func GetPrincipal(c *fiber.Ctx) (*Principal, error) {
+	principal, ok := c.Locals(principalContextKey).(*Principal)
+	if !ok || principal == nil {
+		return nil, errors.New("unauthorized, principal is not found in request")
+	}
+
+	// Checking, if now time greather than expiration of principal.
+	if principal.Expires != 0 && time.Now().Unix() > principal.Expires {
+		return nil, errors.New("unauthorized, check expiration time of your token")
+	}
+
+	return principal, nil
+}
+
+// CanModify method for checking, if principal is allowed to modify a record
+// of the given owner. Admins can modify any record.
+func (p *Principal) CanModify(ownerID uuid.UUID) bool {
+	if p.IsAdmin() {
+		return true
+	}
+
+	return p.UserID != uuid.Nil && p.UserID == ownerID
+}
@@ -6,6 +6,7 @@ import (
 	"os/signal"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/mtls"
 )
 
 // StartServerWithGracefulShutdown function for starting server with a graceful shutdown.
@@ -28,7 +29,7 @@ func StartServerWithGracefulShutdown(a *fiber.App) {
 	}()
 
 	// Run server.
-	if err := a.Listen(os.Getenv("SERVER_URL")); err != nil {
+	if err := listen(a); err != nil {
 		log.Printf("Oops... Server is not running! Reason: %v", err)
 	}
 
@@ -38,7 +39,17 @@ func StartServerWithGracefulShutdown(a *fiber.App) {
 // StartServer func for starting a simple server.
 func StartServer(a *fiber.App) {
 	// Run server.
-	if err := a.Listen(os.Getenv("SERVER_URL")); err != nil {
+	if err := listen(a); err != nil {
 		log.Printf("Oops... Server is not running! Reason: %v", err)
 	}
 }
+
+// listen func for run server with TLS, if it's configured, or plain HTTP.
+func listen(a *fiber.App) error {
+	ln, err := mtls.Listen(os.Getenv("SERVER_URL"))
+	if err != nil {
+		return err
+	}
+
+	return a.Listener(ln)
+}
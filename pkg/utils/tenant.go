@@ -0,0 +1,52 @@
+package utils
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/repository"
+)
+
+// tenantContextKey is a key of fiber.Ctx locals for the current Tenant.
+const tenantContextKey = "tenant"
+
+// Tenant struct to describe organization, which records of request belong to,
+// and role of the caller in it.
+type Tenant struct {
+	OrganizationID uuid.UUID
+	UserID         uuid.UUID // user, whose row-level security is used by queries
+	Role           string
+}
+
+// CanWrite method for checking, if caller can create records of organization.
+func (t *Tenant) CanWrite() bool {
+	return t.Role != repository.OrganizationViewerRole
+}
+
+// CanManage method for checking, if caller can manage members and modify
+// any record of organization.
+func (t *Tenant) CanManage() bool {
+	return t.Role == repository.OrganizationOwnerRole || t.Role == repository.OrganizationAdminRole
+}
+
+// IsOwner method for checking, if caller is owner of organization.
+func (t *Tenant) IsOwner() bool {
+	return t.Role == repository.OrganizationOwnerRole
+}
+
+// SetTenant func for store tenant of the current request.
+func SetTenant(c *fiber.Ctx, t *Tenant) {
+	c.Locals(tenantContextKey, t)
+}
+
+// GetTenant func for get tenant of the current request,
+// which was stored by the tenant middleware.
+func GetTenant(c *fiber.Ctx) (*Tenant, error) {
+	tenant, ok := c.Locals(tenantContextKey).(*Tenant)
+	if !ok || tenant == nil {
+		return nil, errors.New("organization of request is not found")
+	}
+
+	return tenant, nil
+}
@@ -0,0 +1,112 @@
+package utils
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/google/uuid"
+	"github.com/koddr/tutorial-go-fiber-rest-api/pkg/repository"
+)
+
+// twoFactorTokenUse is a "token_use" claim of intermediate token between
+// the first (password) and the second (code) steps of sign in.
+const twoFactorTokenUse = "2fa"
+
+// TwoFactorRequiredRoles func for get roles, which are granted only to sessions
+// with the second factor, from .env file (TWO_FACTOR_REQUIRED_ROLES, default "admin").
+func TwoFactorRequiredRoles() []string {
+	value, ok := os.LookupEnv("TWO_FACTOR_REQUIRED_ROLES")
+	if !ok {
+		return []string{repository.AdminRoleName}
+	}
+
+	roles := []string{}
+	for _, role := range strings.Split(value, ",") {
+		if role = strings.TrimSpace(role); role != "" {
+			roles = append(roles, role)
+		}
+	}
+
+	return roles
+}
+
+// EffectiveRoles func for get roles of session. Roles, which require the second
+// factor, are dropped from sessions without it.
+func EffectiveRoles(roles []string, twoFactor bool) []string {
+	if twoFactor {
+		return roles
+	}
+
+	required := TwoFactorRequiredRoles()
+	effective := make([]string, 0, len(roles))
+	for _, role := range roles {
+		if !containsString(required, role) {
+			effective = append(effective, role)
+		}
+	}
+
+	// Session has at least the role of usual user.
+	if len(effective) == 0 {
+		effective = append(effective, repository.UserRoleName)
+	}
+
+	return effective
+}
+
+// GenerateTwoFactorToken func for generate a short-lived intermediate token of user,
+// who passed the first step of sign in. It's not accepted as access token.
+func GenerateTwoFactorToken(userID uuid.UUID) (string, error) {
+	// Get keys for signing token.
+	keys, err := JWTKeySet()
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	token := jwt.NewWithClaims(keys.Method, jwt.MapClaims{
+		"sub":       userID.String(),
+		"token_use": twoFactorTokenUse,
+		"jti":       uuid.New().String(),
+		"iat":       now.Unix(),
+		"iss":       os.Getenv("JWT_ISSUER"),
+		"exp":       now.Add(5 * time.Minute).Unix(),
+	})
+	if keys.SigningKID != "" {
+		token.Header["kid"] = keys.SigningKID
+	}
+
+	return token.SignedString(keys.SigningKey)
+}
+
+// ParseTwoFactorToken func for verify intermediate token and get ID of its user.
+func ParseTwoFactorToken(tokenString string) (uuid.UUID, error) {
+	token, err := jwt.Parse(tokenString, jwtKeyFunc)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid || stringClaim(claims, "token_use") != twoFactorTokenUse {
+		return uuid.Nil, errors.New("token of sign in is not valid")
+	}
+
+	// Checking, if token was issued by this server.
+	if issuer := os.Getenv("JWT_ISSUER"); issuer != "" && !claims.VerifyIssuer(issuer, true) {
+		return uuid.Nil, errors.New("token issuer is not valid")
+	}
+
+	return uuid.Parse(stringClaim(claims, "sub"))
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEffectiveRoles(t *testing.T) {
+	setenv(t, "TWO_FACTOR_REQUIRED_ROLES", "admin")
+
+	assert.Equal(t, []string{"admin", "user"}, EffectiveRoles([]string{"admin", "user"}, true))
+	assert.Equal(t, []string{"user"}, EffectiveRoles([]string{"admin", "user"}, false))
+	assert.Equal(t, []string{"user"}, EffectiveRoles([]string{"admin"}, false))
+	assert.Equal(t, []string{"moderator"}, EffectiveRoles([]string{"moderator"}, false))
+}
+
+func TestTwoFactorToken(t *testing.T) {
+	setenv(t, "JWT_SECRET_KEY", "secret")
+	userID := uuid.New()
+
+	mfaToken, err := GenerateTwoFactorToken(userID)
+	require.NoError(t, err)
+
+	parsedID, err := ParseTwoFactorToken(mfaToken)
+	require.NoError(t, err)
+	assert.Equal(t, userID, parsedID)
+
+	// Intermediate token must not be accepted as access token.
+	token, err := jwt.Parse(mfaToken, jwtKeyFunc)
+	require.NoError(t, err)
+	_, err = NewPrincipal(token)
+	assert.Error(t, err)
+}
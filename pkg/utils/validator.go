@@ -1,10 +1,15 @@
 package utils
 
 import (
+	"regexp"
+
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
 )
 
+// scopePattern is a format of scope: "<resource>:read" or "<resource>:write".
+var scopePattern = regexp.MustCompile(`^[a-z_]+:(read|write)$`)
+
 // NewValidator func for create a new validator for model fields.
 func NewValidator() *validator.Validate {
 	// Create a new validator for a Book model.
@@ -19,6 +24,11 @@ func NewValidator() *validator.Validate {
 		return false
 	})
 
+	// Custom validation for scopes of API keys, like "books:write".
+	_ = validate.RegisterValidation("scope", func(fl validator.FieldLevel) bool {
+		return scopePattern.MatchString(fl.Field().String())
+	})
+
 	return validate
 }
 
@@ -1,26 +1,151 @@
 package database
 
-import "github.com/koddr/tutorial-go-fiber-rest-api/app/queries"
+import (
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/koddr/tutorial-go-fiber-rest-api/app/queries"
+)
 
 // Queries struct for collect all app queries.
 type Queries struct {
-	*queries.BookQueries // load queries from Book model
-	*queries.InfoQueries // load queries from User model
-	*queries.ServerQueries // load queries from Server model
+	*queries.BookQueries           // load queries from Book model
+	*queries.InfoQueries           // load queries from User model
+	*queries.ServerQueries         // load queries from Server model
+	*queries.ProfileQueries        // load queries from Profile model
+	*queries.UserQueries           // load queries from User model
+	*queries.TokenQueries          // load queries from RefreshToken model
+	*queries.APIKeyQueries         // load queries from APIKey model
+	*queries.RevokedTokenQueries   // load queries from RevokedToken model
+	*queries.SecurityQueries       // load queries from LoginAttempt and SecurityEvent models
+	*queries.TwoFactorQueries      // load queries from UserTOTP model
+	*queries.OAuthClientQueries    // load queries from OAuthClient model
+	*queries.DeviceCodeQueries     // load queries from DeviceCode model
+	*queries.UserTokenQueries      // load queries from UserToken model
+	*queries.OrganizationQueries   // load queries from Organization and Membership models
+	*queries.SessionQueries        // load queries from Session model
+	*queries.ServiceAccountQueries // load queries from ServiceAccount model
+}
+
+// shared is the pool of connections of app, all queries share it.
+var shared struct {
+	mu sync.Mutex
+	db *sqlx.DB
+}
+
+// Connect func for open the shared pool of connections at start of app. While
+// database is not available, the pool is opened again on the next use.
+func Connect() error {
+	_, err := SharedConnection()
+	return err
+}
+
+// SharedConnection func for get the shared pool of connections of app. Pool
+// is opened on first use and is never closed until Close, so handlers and
+// middleware must not close it.
+func SharedConnection() (*sqlx.DB, error) {
+	shared.mu.Lock()
+	defer shared.mu.Unlock()
+
+	if shared.db == nil {
+		db, err := PostgreSQLConnection()
+		if err != nil {
+			return nil, err
+		}
+		shared.db = db
+	}
+
+	return shared.db, nil
+}
+
+// Close func for close the shared pool of connections on shutdown of app.
+func Close() error {
+	shared.mu.Lock()
+	defer shared.mu.Unlock()
+
+	if shared.db == nil {
+		return nil
+	}
+	err := shared.db.Close()
+	shared.db = nil
+
+	return err
 }
 
-// OpenDBConnection func for opening database connection.
+// OpenDBConnection func for get queries of the shared pool of connections.
+// Queries of books, servers and Info fail with ErrNoTenant, they need user of
+// row-level security (see OpenTenantDBConnection).
 func OpenDBConnection() (*Queries, error) {
-	// Define a new PostgreSQL connection.
-	db, err := PostgreSQLConnection()
+	// Get the shared PostgreSQL connection.
+	db, err := SharedConnection()
 	if err != nil {
 		return nil, err
 	}
 
+	return newQueries(db), nil
+}
+
+// newQueries func for make queries of all models with the given pool of connections.
+func newQueries(db *sqlx.DB) *Queries {
 	return &Queries{
 		// Set queries from models:
-		BookQueries: &queries.BookQueries{DB: db}, // from Book model
-		InfoQueries: &queries.InfoQueries{DB: db}, // from Book model
-		ServerQueries: &queries.ServerQueries{DB: db}, // from Book model
-	}, nil
+		BookQueries:           &queries.BookQueries{Executor: noTenantExecutor{}},   // from Book model, see OpenTenantDBConnection
+		InfoQueries:           &queries.InfoQueries{Executor: noTenantExecutor{}},   // from Info model, see OpenTenantDBConnection
+		ServerQueries:         &queries.ServerQueries{Executor: noTenantExecutor{}}, // from Server model, see OpenTenantDBConnection
+		ProfileQueries:        &queries.ProfileQueries{DB: db},                      // from Profile model
+		UserQueries:           &queries.UserQueries{DB: db},                         // from User model
+		TokenQueries:          &queries.TokenQueries{DB: db},                        // from RefreshToken model
+		APIKeyQueries:         &queries.APIKeyQueries{DB: db},                       // from APIKey model
+		RevokedTokenQueries:   &queries.RevokedTokenQueries{DB: db},                 // from RevokedToken model
+		SecurityQueries:       &queries.SecurityQueries{DB: db},                     // from LoginAttempt and SecurityEvent models
+		TwoFactorQueries:      &queries.TwoFactorQueries{DB: db},                    // from UserTOTP model
+		OAuthClientQueries:    &queries.OAuthClientQueries{DB: db},                  // from OAuthClient model
+		DeviceCodeQueries:     &queries.DeviceCodeQueries{DB: db},                   // from DeviceCode model
+		UserTokenQueries:      &queries.UserTokenQueries{DB: db},                    // from UserToken model
+		OrganizationQueries:   &queries.OrganizationQueries{DB: db},                 // from Organization and Membership models
+		SessionQueries:        &queries.SessionQueries{DB: db},                      // from Session model
+		ServiceAccountQueries: &queries.ServiceAccountQueries{DB: db},               // from ServiceAccount model
+	}
+}
+
+// OpenTenantDBConnection func for opening database connection of the given user.
+// Queries of books, servers and Info are sent with row-level security of user,
+// so they return only records of organizations of user.
+func OpenTenantDBConnection(userID uuid.UUID) (*Queries, error) {
+	q, err := OpenDBConnection()
+	if err != nil {
+		return nil, err
+	}
+
+	// All queries share one pool of connections.
+	tenant := &TenantExecutor{DB: q.UserQueries.DB, UserID: userID}
+
+	// Set queries from tenant models:
+	q.BookQueries = &queries.BookQueries{Executor: tenant}     // from Book model
+	q.InfoQueries = &queries.InfoQueries{Executor: tenant}     // from Info model
+	q.ServerQueries = &queries.ServerQueries{Executor: tenant} // from Server model
+
+	return q, nil
+}
+
+// InTenantTransaction func for run fn with database connection of the given
+// user, like OpenTenantDBConnection, but queries of books, servers and Info are
+// sent in one transaction. Transaction is committed, if fn returns nil, so
+// records locked by fn (see queries.Repository) stay locked until then.
+func InTenantTransaction(userID uuid.UUID, fn func(db *Queries) error) error {
+	q, err := OpenDBConnection()
+	if err != nil {
+		return err
+	}
+
+	tenant := &TenantExecutor{DB: q.UserQueries.DB, UserID: userID}
+	return tenant.inTransaction(func(tx *sqlx.Tx) error {
+		// Set queries from tenant models:
+		q.BookQueries = &queries.BookQueries{Executor: tx}     // from Book model
+		q.InfoQueries = &queries.InfoQueries{Executor: tx}     // from Info model
+		q.ServerQueries = &queries.ServerQueries{Executor: tx} // from Server model
+
+		return fn(q)
+	})
 }
@@ -0,0 +1,89 @@
+package database
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/joho/godotenv"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewQueries(t *testing.T) {
+	// Every embedded queries are set, methods of nil queries panic.
+	q := reflect.ValueOf(*newQueries(&sqlx.DB{}))
+	for i := 0; i < q.NumField(); i++ {
+		assert.Falsef(t, q.Field(i).IsNil(), "%s is not set", q.Type().Field(i).Name)
+	}
+}
+
+func TestSharedConnection(t *testing.T) {
+	// Load .env.test file from the root folder.
+	if err := godotenv.Load("../../.env.test"); err != nil {
+		panic(err)
+	}
+
+	db, err := SharedConnection()
+	if err != nil {
+		t.Skipf("database is not available: %v", err)
+	}
+	t.Cleanup(func() { Close() })
+
+	// Queries of every request use the same pool, it's not opened again.
+	q, err := OpenDBConnection()
+	require.NoError(t, err)
+	assert.Same(t, db, q.UserQueries.DB)
+
+	tenant, err := OpenTenantDBConnection(uuid.New())
+	require.NoError(t, err)
+	assert.Same(t, db, tenant.UserQueries.DB)
+}
+
+func TestInTenantTransaction(t *testing.T) {
+	openTestDB(t)
+	db, err := SharedConnection()
+	require.NoError(t, err)
+	t.Cleanup(func() { Close() })
+
+	// Fixtures are committed, so both transactions see them.
+	userID, bookID := uuid.New(), uuid.New()
+	db.MustExec(`INSERT INTO users (id, username, password_hash) VALUES ($1, $2, '')`, userID, "lock-"+userID.String())
+	db.MustExec(`INSERT INTO organizations (id, name) VALUES ($1, $2)`, userID, "lock-"+userID.String())
+	db.MustExec(`INSERT INTO memberships (organization_id, user_id, role) VALUES ($1, $1, 'owner')`, userID)
+	db.MustExec(`INSERT INTO books (id, user_id, org_id, title, author, book_status, book_attrs) VALUES ($1, $2, $2, 'Title', 'Author', 1, '{}')`, bookID, userID)
+	t.Cleanup(func() {
+		db.MustExec(`DELETE FROM organizations WHERE id = $1`, userID)
+		db.MustExec(`DELETE FROM users WHERE id = $1`, userID)
+	})
+
+	// The first transaction locks book and changes it after a while.
+	locked, done := make(chan struct{}), make(chan error)
+	go func() {
+		done <- InTenantTransaction(userID, func(q *Queries) error {
+			book, err := q.GetBookForUpdate(userID, bookID)
+			close(locked)
+			if err != nil {
+				return err
+			}
+			time.Sleep(200 * time.Millisecond)
+			book.Title = "First"
+			return q.UpdateBook(bookID, &book)
+		})
+	}()
+	<-locked
+
+	// The second transaction waits for the first one and gets the changed book.
+	err = InTenantTransaction(userID, func(q *Queries) error {
+		book, err := q.GetBookForUpdate(userID, bookID)
+		if err != nil {
+			return err
+		}
+		assert.Equal(t, "First", book.Title)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, <-done)
+}
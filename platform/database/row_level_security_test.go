@@ -0,0 +1,155 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/joho/godotenv"
+	"github.com/koddr/tutorial-go-fiber-rest-api/app/queries"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// openTestDB func for connect to database of .env.test file. Tests are skipped,
+// if database is not available or migrations with row-level security are not applied.
+func openTestDB(t *testing.T) *sqlx.DB {
+	// Load .env.test file from the root folder.
+	if err := godotenv.Load("../../.env.test"); err != nil {
+		panic(err)
+	}
+
+	db, err := PostgreSQLConnection()
+	if err != nil {
+		t.Skipf("database is not available: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	policies := 0
+	if err := db.Get(&policies, `SELECT COUNT (*) FROM pg_policies WHERE policyname LIKE '%_tenant_isolation'`); err != nil || policies < 3 {
+		t.Skip("migrations with row-level security are not applied")
+	}
+
+	return db
+}
+
+// setUser func for set user of row-level security in transaction, as TenantExecutor does.
+func setUser(tx *sqlx.Tx, userID uuid.UUID) {
+	tx.MustExec(`SELECT set_config ('app.user_id', $1, TRUE)`, userID.String())
+}
+
+func TestRowLevelSecurity(t *testing.T) {
+	db := openTestDB(t)
+
+	// Fixtures are created in transaction, which is rolled back at the end.
+	tx := db.MustBegin()
+	defer tx.Rollback()
+
+	// Users with personal organizations, each has a book, a server and an Info.
+	alice, bob := uuid.New(), uuid.New()
+	records := map[uuid.UUID]map[string]string{}
+	for _, userID := range []uuid.UUID{alice, bob} {
+		tx.MustExec(`INSERT INTO users (id, username, password_hash) VALUES ($1, $2, '')`, userID, "rls-"+userID.String())
+		tx.MustExec(`INSERT INTO organizations (id, name) VALUES ($1, $2)`, userID, "rls-"+userID.String())
+		tx.MustExec(`INSERT INTO memberships (organization_id, user_id, role) VALUES ($1, $1, 'owner')`, userID)
+
+		records[userID] = map[string]string{
+			"books":   uuid.New().String(),
+			"servers": uuid.New().String(),
+			"info":    uuid.New().String(),
+		}
+		tx.MustExec(`INSERT INTO books (id, user_id, org_id, title, author, book_status, book_attrs) VALUES ($1, $2, $2, 'Title', 'Author', 1, '{}')`,
+			records[userID]["books"], userID)
+		tx.MustExec(`INSERT INTO servers (id, user_id, org_id, title, author, server_status, server_attrs) VALUES ($1, $2, $2, 'Title', 'Author', 1, '{}')`,
+			records[userID]["servers"], userID)
+		tx.MustExec(`INSERT INTO info (id, user_id, org_id, name, portfolio, info_status, info_attrs) VALUES ($1, $2, $2, 'Name', 'Portfolio', 1, '{}')`,
+			records[userID]["info"], userID)
+	}
+
+	// Policies are checked as a regular role, superusers skip them.
+	tx.MustExec(`CREATE ROLE rls_test NOLOGIN`)
+	tx.MustExec(`GRANT SELECT, INSERT, UPDATE, DELETE ON books, servers, info TO rls_test`)
+	tx.MustExec(`GRANT SELECT ON users, organizations, memberships TO rls_test`)
+	tx.MustExec(`SET LOCAL ROLE rls_test`)
+
+	for _, table := range []string{"books", "servers", "info"} {
+		// Query without tenant condition returns only records of organizations of user.
+		for _, userID := range []uuid.UUID{alice, bob} {
+			setUser(tx, userID)
+			ids := []string{}
+			require.NoError(t, tx.Select(&ids, `SELECT id::TEXT FROM `+table))
+			assert.Contains(t, ids, records[userID][table], table)
+			assert.NotContains(t, ids, records[other(userID, alice, bob)][table], table)
+		}
+
+		// Transaction without user sees no records at all.
+		tx.MustExec(`SET LOCAL app.user_id = ''`)
+		count := 0
+		require.NoError(t, tx.Get(&count, `SELECT COUNT (*) FROM `+table))
+		assert.Equal(t, 0, count, table)
+
+		// Records of other organization are not updated and not deleted.
+		setUser(tx, alice)
+		result := tx.MustExec(`UPDATE `+table+` SET updated_at = NOW () WHERE id::TEXT = $1`, records[bob][table])
+		affected, err := result.RowsAffected()
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), affected, table)
+		result = tx.MustExec(`DELETE FROM `+table+` WHERE id::TEXT = $1`, records[bob][table])
+		affected, err = result.RowsAffected()
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), affected, table)
+	}
+
+	// Queries of app return nothing of other organization, even if it's requested.
+	setUser(tx, alice)
+	servers := &queries.ServerQueries{Executor: tx}
+	list, err := servers.GetServers(bob)
+	require.NoError(t, err)
+	assert.Empty(t, list)
+	_, err = servers.GetServer(bob, uuid.MustParse(records[bob]["servers"]))
+	assert.Error(t, err)
+	list, err = servers.GetServers(alice)
+	require.NoError(t, err)
+	assert.Len(t, list, 1)
+
+	// Records are not created in other organization.
+	tx.MustExec(`SAVEPOINT insert_into_other_organization`)
+	_, err = tx.Exec(`INSERT INTO servers (id, user_id, org_id, title, author, server_status, server_attrs) VALUES ($1, $2, $3, 'Title', 'Author', 1, '{}')`,
+		uuid.New(), alice, bob)
+	assert.Error(t, err)
+	tx.MustExec(`ROLLBACK TO SAVEPOINT insert_into_other_organization`)
+}
+
+func TestTenantExecutor(t *testing.T) {
+	db := openTestDB(t)
+	db.SetMaxOpenConns(1) // the next query uses the same connection
+	userID := uuid.New()
+	executor := &TenantExecutor{DB: db, UserID: userID}
+
+	// Setting is set in transaction of query.
+	setting := ""
+	require.NoError(t, executor.Get(&setting, `SELECT current_setting ('app.user_id')`))
+	assert.Equal(t, userID.String(), setting)
+
+	// Connection returns to pool without setting.
+	require.NoError(t, db.Get(&setting, `SELECT COALESCE (current_setting ('app.user_id', TRUE), '')`))
+	assert.Empty(t, setting)
+}
+
+func TestNoTenant(t *testing.T) {
+	// Queries of tenant records without user fail, instead of returning no rows.
+	books := &queries.BookQueries{Executor: noTenantExecutor{}}
+	_, err := books.GetBooks(uuid.New())
+	assert.ErrorIs(t, err, ErrNoTenant)
+
+	servers := &queries.ServerQueries{Executor: &TenantExecutor{UserID: uuid.Nil}}
+	_, err = servers.GetServers(uuid.New())
+	assert.ErrorIs(t, err, ErrNoTenant)
+}
+
+func other(userID, a, b uuid.UUID) uuid.UUID {
+	if userID == a {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,90 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// ErrNoTenant is error of queries of tenant records, which are sent without
+// user of row-level security. Policies would return no rows for them, so
+// queries fail instead (see OpenTenantDBConnection).
+var ErrNoTenant = errors.New("queries of tenant records need connection of user, see OpenTenantDBConnection")
+
+// TenantExecutor struct to describe connection, which sends every query in its
+// own transaction with local setting "app.user_id". Row-level security policies of
+// PostgreSQL (see migration 000016) show only records of organizations of the user,
+// so a query without tenant condition can't read records of other organizations.
+type TenantExecutor struct {
+	DB     *sqlx.DB
+	UserID uuid.UUID
+}
+
+// Get method for get one record in transaction of user.
+func (e *TenantExecutor) Get(dest interface{}, query string, args ...interface{}) error {
+	return e.inTransaction(func(tx *sqlx.Tx) error {
+		return tx.Get(dest, query, args...)
+	})
+}
+
+// Select method for get records in transaction of user.
+func (e *TenantExecutor) Select(dest interface{}, query string, args ...interface{}) error {
+	return e.inTransaction(func(tx *sqlx.Tx) error {
+		return tx.Select(dest, query, args...)
+	})
+}
+
+// Exec method for execute query in transaction of user.
+func (e *TenantExecutor) Exec(query string, args ...interface{}) (sql.Result, error) {
+	var result sql.Result
+	err := e.inTransaction(func(tx *sqlx.Tx) error {
+		var err error
+		result, err = tx.Exec(query, args...)
+		return err
+	})
+
+	return result, err
+}
+
+func (e *TenantExecutor) inTransaction(fn func(tx *sqlx.Tx) error) error {
+	if e.UserID == uuid.Nil {
+		return ErrNoTenant
+	}
+
+	// Begin a new transaction.
+	tx, err := e.DB.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Setting is local, it lives until the end of transaction, so connection
+	// returns to pool without it.
+	if _, err := tx.Exec(`SELECT set_config ('app.user_id', $1, TRUE)`, e.UserID.String()); err != nil {
+		return err
+	}
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// noTenantExecutor struct to describe connection of tenant records without
+// user, which is used by OpenDBConnection. All queries fail with ErrNoTenant.
+type noTenantExecutor struct{}
+
+func (noTenantExecutor) Get(dest interface{}, query string, args ...interface{}) error {
+	return ErrNoTenant
+}
+
+func (noTenantExecutor) Select(dest interface{}, query string, args ...interface{}) error {
+	return ErrNoTenant
+}
+
+func (noTenantExecutor) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return nil, ErrNoTenant
+}
@@ -0,0 +1,12 @@
+-- Create profiles table
+CREATE TABLE profiles (
+    id UUID DEFAULT uuid_generate_v4 () PRIMARY KEY,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW (),
+    updated_at TIMESTAMP NULL,
+    user_id UUID NOT NULL,
+    name VARCHAR (255) NOT NULL,
+    profile_options JSONB NOT NULL
+);
+
+-- Add indexes
+CREATE UNIQUE INDEX profiles_user_id_name ON profiles (user_id, name);
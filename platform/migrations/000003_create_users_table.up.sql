@@ -0,0 +1,11 @@
+-- Create users table
+CREATE TABLE users (
+    id UUID DEFAULT uuid_generate_v4 () PRIMARY KEY,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW (),
+    updated_at TIMESTAMP NULL,
+    username VARCHAR (255) NOT NULL UNIQUE,
+    first_name VARCHAR (255) NOT NULL DEFAULT '',
+    last_name VARCHAR (255) NOT NULL DEFAULT '',
+    roles JSONB NOT NULL DEFAULT '["user"]',
+    password_hash VARCHAR (255) NOT NULL
+);
@@ -0,0 +1,7 @@
+-- Delete tables
+DROP TABLE IF EXISTS servers;
+
+-- Delete columns
+ALTER TABLE info DROP COLUMN IF EXISTS user_id;
+ALTER TABLE info RENAME COLUMN name TO title;
+ALTER TABLE books DROP COLUMN IF EXISTS user_id;
@@ -0,0 +1,23 @@
+-- Add owner of books
+ALTER TABLE books ADD COLUMN user_id UUID NULL REFERENCES users (id) ON DELETE SET NULL;
+
+-- Add owner of info and align columns with Info model
+ALTER TABLE info RENAME COLUMN title TO name;
+ALTER TABLE info ADD COLUMN user_id UUID NULL REFERENCES users (id) ON DELETE SET NULL;
+
+-- Create servers table
+CREATE TABLE servers (
+    id UUID DEFAULT uuid_generate_v4 () PRIMARY KEY,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW (),
+    updated_at TIMESTAMP NULL,
+    user_id UUID NULL REFERENCES users (id) ON DELETE SET NULL,
+    title VARCHAR (255) NOT NULL,
+    author VARCHAR (255) NOT NULL,
+    server_status INT NOT NULL,
+    server_attrs JSONB NOT NULL
+);
+
+-- Add indexes
+CREATE INDEX books_user_id ON books (user_id);
+CREATE INDEX info_user_id ON info (user_id);
+CREATE INDEX servers_user_id ON servers (user_id);
@@ -0,0 +1,12 @@
+-- Create user_identities table
+CREATE TABLE user_identities (
+    id UUID DEFAULT uuid_generate_v4 () PRIMARY KEY,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW (),
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    issuer VARCHAR (255) NOT NULL,
+    subject VARCHAR (255) NOT NULL,
+    UNIQUE (issuer, subject)
+);
+
+-- Add indexes
+CREATE INDEX user_identities_user_id ON user_identities (user_id);
@@ -0,0 +1,17 @@
+-- Create api_keys table
+CREATE TABLE api_keys (
+    id UUID DEFAULT uuid_generate_v4 () PRIMARY KEY,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW (),
+    expires_at TIMESTAMP WITH TIME ZONE NULL,
+    last_used_at TIMESTAMP WITH TIME ZONE NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE NULL,
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    name VARCHAR (255) NOT NULL,
+    prefix VARCHAR (16) NOT NULL,
+    key_hash VARCHAR (64) NOT NULL UNIQUE,
+    scopes JSONB NOT NULL DEFAULT '[]',
+    allowed_ips JSONB NOT NULL DEFAULT '[]'
+);
+
+-- Add indexes
+CREATE INDEX api_keys_user_id ON api_keys (user_id);
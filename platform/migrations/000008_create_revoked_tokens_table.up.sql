@@ -0,0 +1,10 @@
+-- Create revoked_tokens table
+CREATE TABLE revoked_tokens (
+    token_id VARCHAR (255) PRIMARY KEY,
+    revoked_at TIMESTAMP WITH TIME ZONE DEFAULT NOW (),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    user_id UUID NULL REFERENCES users (id) ON DELETE CASCADE
+);
+
+-- Add indexes
+CREATE INDEX revoked_tokens_expires_at ON revoked_tokens (expires_at);
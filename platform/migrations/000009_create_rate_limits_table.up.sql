@@ -0,0 +1,9 @@
+-- Create rate_limits table (token buckets)
+CREATE TABLE rate_limits (
+    key VARCHAR (255) PRIMARY KEY,
+    tokens DOUBLE PRECISION NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+-- Add indexes
+CREATE INDEX rate_limits_updated_at ON rate_limits (updated_at);
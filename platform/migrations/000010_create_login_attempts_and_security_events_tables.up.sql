@@ -0,0 +1,22 @@
+-- Create login_attempts table (failures per username or IP)
+CREATE TABLE login_attempts (
+    key VARCHAR (255) PRIMARY KEY,
+    failures INT NOT NULL DEFAULT 0,
+    last_failure_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    locked_until TIMESTAMP WITH TIME ZONE NULL
+);
+
+-- Create security_events table
+CREATE TABLE security_events (
+    id UUID DEFAULT uuid_generate_v4 () PRIMARY KEY,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW (),
+    event_type VARCHAR (64) NOT NULL,
+    user_id UUID NULL REFERENCES users (id) ON DELETE SET NULL,
+    username VARCHAR (255) NOT NULL DEFAULT '',
+    ip VARCHAR (64) NOT NULL DEFAULT '',
+    user_agent VARCHAR (512) NOT NULL DEFAULT ''
+);
+
+-- Add indexes
+CREATE INDEX security_events_created_at ON security_events (created_at);
+CREATE INDEX security_events_username ON security_events (username);
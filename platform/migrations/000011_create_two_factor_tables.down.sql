@@ -0,0 +1,6 @@
+-- Delete columns
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS two_factor;
+
+-- Delete tables
+DROP TABLE IF EXISTS recovery_codes;
+DROP TABLE IF EXISTS user_totp;
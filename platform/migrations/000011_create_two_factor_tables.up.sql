@@ -0,0 +1,20 @@
+-- Create user_totp table (TOTP secret of user, enabled after confirmation)
+CREATE TABLE user_totp (
+    user_id UUID PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW (),
+    enabled_at TIMESTAMP WITH TIME ZONE NULL,
+    secret VARCHAR (64) NOT NULL,
+    last_used_step BIGINT NOT NULL DEFAULT 0
+);
+
+-- Create recovery_codes table
+CREATE TABLE recovery_codes (
+    id UUID DEFAULT uuid_generate_v4 () PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    code_hash VARCHAR (64) NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE NULL,
+    UNIQUE (user_id, code_hash)
+);
+
+-- Mark sessions, which were started with the second factor
+ALTER TABLE refresh_tokens ADD COLUMN two_factor BOOLEAN NOT NULL DEFAULT FALSE;
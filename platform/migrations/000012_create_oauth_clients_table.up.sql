@@ -0,0 +1,11 @@
+-- Create oauth_clients table
+CREATE TABLE oauth_clients (
+    id UUID DEFAULT uuid_generate_v4 () PRIMARY KEY,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW (),
+    revoked_at TIMESTAMP WITH TIME ZONE NULL,
+    client_id VARCHAR (64) NOT NULL UNIQUE,
+    secret_hash VARCHAR (64) NOT NULL,
+    name VARCHAR (255) NOT NULL,
+    scopes JSONB NOT NULL DEFAULT '[]',
+    roles JSONB NOT NULL DEFAULT '[]'
+);
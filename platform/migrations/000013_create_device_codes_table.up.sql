@@ -0,0 +1,17 @@
+-- Create device_codes table (device authorization requests, RFC 8628)
+CREATE TABLE device_codes (
+    id UUID DEFAULT uuid_generate_v4 () PRIMARY KEY,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW (),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    device_code_hash VARCHAR (64) NOT NULL UNIQUE,
+    user_code VARCHAR (16) NOT NULL UNIQUE,
+    client_id VARCHAR (255) NOT NULL,
+    status VARCHAR (16) NOT NULL DEFAULT 'pending',
+    user_id UUID NULL REFERENCES users (id) ON DELETE CASCADE,
+    two_factor BOOLEAN NOT NULL DEFAULT FALSE,
+    poll_interval INT NOT NULL,
+    last_polled_at TIMESTAMP WITH TIME ZONE NULL
+);
+
+-- Add indexes
+CREATE INDEX device_codes_expires_at ON device_codes (expires_at);
@@ -0,0 +1,6 @@
+-- Delete tables
+DROP TABLE IF EXISTS user_tokens;
+
+-- Delete email of users
+ALTER TABLE users DROP COLUMN IF EXISTS email_verified_at;
+ALTER TABLE users DROP COLUMN IF EXISTS email;
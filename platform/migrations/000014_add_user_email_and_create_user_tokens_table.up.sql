@@ -0,0 +1,18 @@
+-- Add email of users (stored in lower case)
+ALTER TABLE users ADD COLUMN email VARCHAR (255) NULL UNIQUE;
+ALTER TABLE users ADD COLUMN email_verified_at TIMESTAMP WITH TIME ZONE NULL;
+
+-- Create user_tokens table (single-use tokens of password reset and email verification)
+CREATE TABLE user_tokens (
+    id UUID DEFAULT uuid_generate_v4 () PRIMARY KEY,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW (),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE NULL,
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    purpose VARCHAR (32) NOT NULL,
+    token_hash VARCHAR (64) NOT NULL UNIQUE,
+    email VARCHAR (255) NOT NULL
+);
+
+-- Add indexes
+CREATE INDEX user_tokens_user_id ON user_tokens (user_id);
@@ -0,0 +1,8 @@
+-- Delete organization of records
+ALTER TABLE info DROP COLUMN IF EXISTS org_id;
+ALTER TABLE servers DROP COLUMN IF EXISTS org_id;
+ALTER TABLE books DROP COLUMN IF EXISTS org_id;
+
+-- Delete tables
+DROP TABLE IF EXISTS memberships;
+DROP TABLE IF EXISTS organizations;
@@ -0,0 +1,44 @@
+-- Create organizations table
+CREATE TABLE organizations (
+    id UUID DEFAULT uuid_generate_v4 () PRIMARY KEY,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW (),
+    updated_at TIMESTAMP WITH TIME ZONE NULL,
+    name VARCHAR (255) NOT NULL
+);
+
+-- Create memberships table
+CREATE TABLE memberships (
+    organization_id UUID NOT NULL REFERENCES organizations (id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    role VARCHAR (16) NOT NULL CHECK (role IN ('owner', 'admin', 'member', 'viewer')),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW (),
+    PRIMARY KEY (organization_id, user_id)
+);
+
+-- Create personal organization of every user (it has ID of user)
+INSERT INTO organizations (id, name) SELECT id, username FROM users;
+INSERT INTO memberships (organization_id, user_id, role) SELECT id, id, 'owner' FROM users;
+
+-- Create default organization for records without owner, admins are its owners
+INSERT INTO organizations (id, name) VALUES ('6c1b2bd4-3f3e-4b8e-9d2a-2f0c6a8e5d01', 'Default');
+INSERT INTO memberships (organization_id, user_id, role)
+    SELECT '6c1b2bd4-3f3e-4b8e-9d2a-2f0c6a8e5d01', id, 'owner' FROM users WHERE roles ? 'admin';
+
+-- Add organization of books, servers and info (records of user go to personal organization)
+ALTER TABLE books ADD COLUMN org_id UUID NULL REFERENCES organizations (id) ON DELETE CASCADE;
+UPDATE books SET org_id = COALESCE (user_id, '6c1b2bd4-3f3e-4b8e-9d2a-2f0c6a8e5d01');
+ALTER TABLE books ALTER COLUMN org_id SET NOT NULL;
+
+ALTER TABLE servers ADD COLUMN org_id UUID NULL REFERENCES organizations (id) ON DELETE CASCADE;
+UPDATE servers SET org_id = COALESCE (user_id, '6c1b2bd4-3f3e-4b8e-9d2a-2f0c6a8e5d01');
+ALTER TABLE servers ALTER COLUMN org_id SET NOT NULL;
+
+ALTER TABLE info ADD COLUMN org_id UUID NULL REFERENCES organizations (id) ON DELETE CASCADE;
+UPDATE info SET org_id = COALESCE (user_id, '6c1b2bd4-3f3e-4b8e-9d2a-2f0c6a8e5d01');
+ALTER TABLE info ALTER COLUMN org_id SET NOT NULL;
+
+-- Add indexes
+CREATE INDEX memberships_user_id ON memberships (user_id);
+CREATE INDEX books_org_id ON books (org_id);
+CREATE INDEX servers_org_id ON servers (org_id);
+CREATE INDEX info_org_id ON info (org_id);
@@ -0,0 +1,16 @@
+-- Delete policies
+DROP POLICY IF EXISTS info_tenant_isolation ON info;
+DROP POLICY IF EXISTS servers_tenant_isolation ON servers;
+DROP POLICY IF EXISTS books_tenant_isolation ON books;
+
+-- Disable row-level security
+ALTER TABLE info NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE info DISABLE ROW LEVEL SECURITY;
+ALTER TABLE servers NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE servers DISABLE ROW LEVEL SECURITY;
+ALTER TABLE books NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE books DISABLE ROW LEVEL SECURITY;
+
+-- Delete functions
+DROP FUNCTION IF EXISTS app_user_organizations ();
+DROP FUNCTION IF EXISTS app_user_id ();
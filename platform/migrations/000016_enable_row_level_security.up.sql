@@ -0,0 +1,36 @@
+-- Row-level security of books, servers and info. App sets "SET LOCAL app.user_id"
+-- in every transaction of tenant records, rows of other organizations are not visible
+-- and can't be written. Transactions without the setting see no rows at all.
+-- Superusers and roles with BYPASSRLS skip policies, so app must connect to
+-- database as a regular role. FORCE applies policies to owner of tables too.
+
+-- Add functions of the current user
+CREATE FUNCTION app_user_id () RETURNS UUID LANGUAGE SQL STABLE AS $$
+    SELECT NULLIF (current_setting ('app.user_id', TRUE), '')::UUID
+$$;
+
+-- Organizations of the current user, admins of app have access to all organizations
+CREATE FUNCTION app_user_organizations () RETURNS SETOF UUID LANGUAGE SQL STABLE AS $$
+    SELECT organization_id FROM memberships WHERE user_id = app_user_id ()
+    UNION
+    SELECT id FROM organizations WHERE EXISTS (SELECT 1 FROM users WHERE id = app_user_id () AND roles ? 'admin')
+$$;
+
+-- Enable row-level security
+ALTER TABLE books ENABLE ROW LEVEL SECURITY;
+ALTER TABLE books FORCE ROW LEVEL SECURITY;
+ALTER TABLE servers ENABLE ROW LEVEL SECURITY;
+ALTER TABLE servers FORCE ROW LEVEL SECURITY;
+ALTER TABLE info ENABLE ROW LEVEL SECURITY;
+ALTER TABLE info FORCE ROW LEVEL SECURITY;
+
+-- Add policies
+CREATE POLICY books_tenant_isolation ON books
+    USING (org_id IN (SELECT app_user_organizations ()))
+    WITH CHECK (org_id IN (SELECT app_user_organizations ()));
+CREATE POLICY servers_tenant_isolation ON servers
+    USING (org_id IN (SELECT app_user_organizations ()))
+    WITH CHECK (org_id IN (SELECT app_user_organizations ()));
+CREATE POLICY info_tenant_isolation ON info
+    USING (org_id IN (SELECT app_user_organizations ()))
+    WITH CHECK (org_id IN (SELECT app_user_organizations ()));
@@ -0,0 +1,17 @@
+-- Create sessions table (cookie sessions of browsers, only hashes of tokens are stored)
+CREATE TABLE sessions (
+    id UUID DEFAULT uuid_generate_v4 () PRIMARY KEY,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW (),
+    last_seen_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE NULL,
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    token_hash VARCHAR (64) NOT NULL UNIQUE,
+    csrf_token_hash VARCHAR (64) NOT NULL,
+    two_factor BOOLEAN NOT NULL DEFAULT FALSE,
+    ip VARCHAR (64) NOT NULL DEFAULT '',
+    user_agent VARCHAR (255) NOT NULL DEFAULT ''
+);
+
+-- Add indexes
+CREATE INDEX sessions_user_id ON sessions (user_id);
@@ -0,0 +1,9 @@
+-- Delete service accounts with their personal organizations
+DELETE FROM organizations WHERE id IN (SELECT user_id FROM service_accounts);
+DELETE FROM users WHERE id IN (SELECT user_id FROM service_accounts);
+
+-- Delete service account of OAuth clients
+ALTER TABLE oauth_clients DROP COLUMN IF EXISTS user_id;
+
+-- Delete tables
+DROP TABLE IF EXISTS service_accounts;
@@ -0,0 +1,17 @@
+-- Create service_accounts table (users of OAuth clients and client certificates,
+-- which own records and are members of organizations, they can't sign in)
+CREATE TABLE service_accounts (
+    user_id UUID PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW (),
+    identity VARCHAR (255) NOT NULL UNIQUE
+);
+
+-- Add service account of OAuth clients
+ALTER TABLE oauth_clients ADD COLUMN user_id UUID NULL REFERENCES users (id) ON DELETE SET NULL;
+
+-- Create service account with personal organization of every OAuth client (it has ID of client)
+INSERT INTO users (id, username, roles, password_hash) SELECT id, 'oauth:' || client_id, roles, '' FROM oauth_clients;
+INSERT INTO service_accounts (user_id, identity) SELECT id, 'oauth:' || client_id FROM oauth_clients;
+INSERT INTO organizations (id, name) SELECT id, 'oauth:' || client_id FROM oauth_clients;
+INSERT INTO memberships (organization_id, user_id, role) SELECT id, id, 'owner' FROM oauth_clients;
+UPDATE oauth_clients SET user_id = id;